@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	"pfeifer.dev/mapd/cereal/log"
+	ms "pfeifer.dev/mapd/settings"
+)
+
+type altitudeSample struct {
+	Altitude float64
+	Accuracy float64
+	Time     time.Time
+}
+
+// AltitudeTracker keeps a short history of gps altitude so the way matcher
+// can tell whether we are climbing onto or descending from a stacked road.
+type AltitudeTracker struct {
+	samples []altitudeSample
+}
+
+func (a *AltitudeTracker) Update(location log.GpsLocationData) {
+	now := time.UnixMilli(location.UnixTimestampMillis())
+	cutoff := now.Add(-ms.LEVEL_TREND_WINDOW)
+	start := 0
+	for start < len(a.samples) && (a.samples[start].Time.Before(cutoff) || a.samples[start].Time.After(now)) {
+		start++
+	}
+	a.samples = a.samples[start:]
+
+	accuracy := float64(location.VerticalAccuracy())
+	if accuracy <= 0 || accuracy > ms.MAX_VERTICAL_ACCURACY {
+		return
+	}
+	a.samples = append(a.samples, altitudeSample{
+		Altitude: location.Altitude(),
+		Accuracy: accuracy,
+		Time:     now,
+	})
+}
+
+// Climb is the altitude change across the trend window and the mean vertical
+// accuracy of the samples it was computed from.
+func (a *AltitudeTracker) Climb() (climb float64, accuracy float64, ok bool) {
+	if len(a.samples) < 2 {
+		return 0, 0, false
+	}
+	for _, s := range a.samples {
+		accuracy += s.Accuracy
+	}
+	accuracy /= float64(len(a.samples))
+	climb = a.samples[len(a.samples)-1].Altitude - a.samples[0].Altitude
+	return climb, accuracy, true
+}
+
+// ExpectedLevelChange returns 1 when the altitude trend shows we climbed a
+// level, -1 when we descended one and 0 when we appear to be on the same
+// level or the altitude data is not good enough to tell.
+func (a *AltitudeTracker) ExpectedLevelChange() int {
+	climb, accuracy, ok := a.Climb()
+	if !ok {
+		return 0
+	}
+	threshold := max(ms.LEVEL_HEIGHT, accuracy/2)
+	if math.Abs(climb) < threshold {
+		return 0
+	}
+	if climb > 0 {
+		return 1
+	}
+	return -1
+}
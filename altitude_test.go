@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"capnproto.org/go/capnp/v3"
+	"pfeifer.dev/mapd/cereal/log"
+)
+
+func testLocation(t *testing.T, lat float64, lon float64, bearing float32) log.GpsLocationData {
+	t.Helper()
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	location, err := log.NewRootGpsLocationData(seg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	location.SetLatitude(lat)
+	location.SetLongitude(lon)
+	location.SetBearingDeg(bearing)
+	location.SetHorizontalAccuracy(1)
+	return location
+}
+
+// trackAltitudes feeds one sample a second to a new tracker.
+func trackAltitudes(t *testing.T, accuracy float32, altitudes ...float64) *AltitudeTracker {
+	t.Helper()
+	tracker := &AltitudeTracker{}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, altitude := range altitudes {
+		location := testLocation(t, 40, -83, 90)
+		location.SetAltitude(altitude)
+		location.SetVerticalAccuracy(accuracy)
+		location.SetUnixTimestampMillis(start.Add(time.Duration(i) * time.Second).UnixMilli())
+		tracker.Update(location)
+	}
+	return tracker
+}
+
+func TestExpectedLevelChange(t *testing.T) {
+	cases := []struct {
+		name      string
+		accuracy  float32
+		altitudes []float64
+		expected  int
+	}{
+		{"climbing", 2, []float64{200, 202, 204, 206, 208}, 1},
+		{"descending", 2, []float64{208, 206, 204, 202, 200}, -1},
+		{"level", 2, []float64{200, 201, 200, 202, 201}, 0},
+		{"noisy climb", 14, []float64{200, 202, 204, 206}, 0},
+		{"inaccurate", 20, []float64{200, 210, 220, 230}, 0},
+		{"single sample", 2, []float64{200}, 0},
+	}
+	for _, c := range cases {
+		tracker := trackAltitudes(t, c.accuracy, c.altitudes...)
+		if change := tracker.ExpectedLevelChange(); change != c.expected {
+			t.Errorf("%s: expected level change %d, got %d", c.name, c.expected, change)
+		}
+	}
+}
+
+func TestAltitudeTrendForgetsOldSamples(t *testing.T) {
+	altitudes := []float64{200, 205, 210}
+	for range 20 {
+		altitudes = append(altitudes, 210)
+	}
+	tracker := trackAltitudes(t, 2, altitudes...)
+	if change := tracker.ExpectedLevelChange(); change != 0 {
+		t.Errorf("expected a climb outside of the trend window to be forgotten, got %d", change)
+	}
+	climb, accuracy, ok := tracker.Climb()
+	if !ok || climb != 0 || accuracy != 2 {
+		t.Errorf("expected a flat climb, got %f %f %v", climb, accuracy, ok)
+	}
+}
@@ -1218,12 +1218,12 @@ type MapdDownloadProgress capnp.Struct
 const MapdDownloadProgress_TypeID = 0xfaa35dcac85073a2
 
 func NewMapdDownloadProgress(s *capnp.Segment) (MapdDownloadProgress, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2})
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 48, PointerCount: 5})
 	return MapdDownloadProgress(st), err
 }
 
 func NewRootMapdDownloadProgress(s *capnp.Segment) (MapdDownloadProgress, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2})
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 48, PointerCount: 5})
 	return MapdDownloadProgress(st), err
 }
 
@@ -1337,13 +1337,141 @@ func (s MapdDownloadProgress) NewLocationDetails(n int32) (MapdDownloadLocationD
 	err = capnp.Struct(s).SetPtr(1, l.ToPtr())
 	return l, err
 }
+func (s MapdDownloadProgress) UpdateCheck() bool {
+	return capnp.Struct(s).Bit(2)
+}
+
+func (s MapdDownloadProgress) SetUpdateCheck(v bool) {
+	capnp.Struct(s).SetBit(2, v)
+}
+
+func (s MapdDownloadProgress) UpdatesAvailable() uint32 {
+	return capnp.Struct(s).Uint32(12)
+}
+
+func (s MapdDownloadProgress) SetUpdatesAvailable(v uint32) {
+	capnp.Struct(s).SetUint32(12, v)
+}
+
+func (s MapdDownloadProgress) Paused() bool {
+	return capnp.Struct(s).Bit(3)
+}
+
+func (s MapdDownloadProgress) SetPaused(v bool) {
+	capnp.Struct(s).SetBit(3, v)
+}
+
+func (s MapdDownloadProgress) DownloadedBytes() uint64 {
+	return capnp.Struct(s).Uint64(16)
+}
+
+func (s MapdDownloadProgress) SetDownloadedBytes(v uint64) {
+	capnp.Struct(s).SetUint64(16, v)
+}
+
+func (s MapdDownloadProgress) BytesPerSecond() float32 {
+	return math.Float32frombits(capnp.Struct(s).Uint32(24))
+}
+
+func (s MapdDownloadProgress) SetBytesPerSecond(v float32) {
+	capnp.Struct(s).SetUint32(24, math.Float32bits(v))
+}
+
+func (s MapdDownloadProgress) EtaSeconds() int32 {
+	return int32(capnp.Struct(s).Uint32(28))
+}
+
+func (s MapdDownloadProgress) SetEtaSeconds(v int32) {
+	capnp.Struct(s).SetUint32(28, uint32(v))
+}
+
+func (s MapdDownloadProgress) CurrentFile() (string, error) {
+	p, err := capnp.Struct(s).Ptr(2)
+	return p.Text(), err
+}
+
+func (s MapdDownloadProgress) HasCurrentFile() bool {
+	return capnp.Struct(s).HasPtr(2)
+}
+
+func (s MapdDownloadProgress) CurrentFileBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(2)
+	return p.TextBytes(), err
+}
+
+func (s MapdDownloadProgress) SetCurrentFile(v string) error {
+	return capnp.Struct(s).SetText(2, v)
+}
+
+func (s MapdDownloadProgress) CurrentFileReceived() uint64 {
+	return capnp.Struct(s).Uint64(32)
+}
+
+func (s MapdDownloadProgress) SetCurrentFileReceived(v uint64) {
+	capnp.Struct(s).SetUint64(32, v)
+}
+
+func (s MapdDownloadProgress) CurrentFileSize() uint64 {
+	return capnp.Struct(s).Uint64(40)
+}
+
+func (s MapdDownloadProgress) SetCurrentFileSize(v uint64) {
+	capnp.Struct(s).SetUint64(40, v)
+}
+
+func (s MapdDownloadProgress) FailedFiles() (MapdDownloadFailure_List, error) {
+	p, err := capnp.Struct(s).Ptr(3)
+	return MapdDownloadFailure_List(p.List()), err
+}
+
+func (s MapdDownloadProgress) HasFailedFiles() bool {
+	return capnp.Struct(s).HasPtr(3)
+}
+
+func (s MapdDownloadProgress) SetFailedFiles(v MapdDownloadFailure_List) error {
+	return capnp.Struct(s).SetPtr(3, v.ToPtr())
+}
+
+// NewFailedFiles sets the failedFiles field to a newly
+// allocated MapdDownloadFailure_List, preferring placement in s's segment.
+func (s MapdDownloadProgress) NewFailedFiles(n int32) (MapdDownloadFailure_List, error) {
+	l, err := NewMapdDownloadFailure_List(capnp.Struct(s).Segment(), n)
+	if err != nil {
+		return MapdDownloadFailure_List{}, err
+	}
+	err = capnp.Struct(s).SetPtr(3, l.ToPtr())
+	return l, err
+}
+func (s MapdDownloadProgress) UnverifiedFiles() (capnp.TextList, error) {
+	p, err := capnp.Struct(s).Ptr(4)
+	return capnp.TextList(p.List()), err
+}
+
+func (s MapdDownloadProgress) HasUnverifiedFiles() bool {
+	return capnp.Struct(s).HasPtr(4)
+}
+
+func (s MapdDownloadProgress) SetUnverifiedFiles(v capnp.TextList) error {
+	return capnp.Struct(s).SetPtr(4, v.ToPtr())
+}
+
+// NewUnverifiedFiles sets the unverifiedFiles field to a newly
+// allocated capnp.TextList, preferring placement in s's segment.
+func (s MapdDownloadProgress) NewUnverifiedFiles(n int32) (capnp.TextList, error) {
+	l, err := capnp.NewTextList(capnp.Struct(s).Segment(), n)
+	if err != nil {
+		return capnp.TextList{}, err
+	}
+	err = capnp.Struct(s).SetPtr(4, l.ToPtr())
+	return l, err
+}
 
 // MapdDownloadProgress_List is a list of MapdDownloadProgress.
 type MapdDownloadProgress_List = capnp.StructList[MapdDownloadProgress]
 
 // NewMapdDownloadProgress creates a new list of MapdDownloadProgress.
 func NewMapdDownloadProgress_List(s *capnp.Segment, sz int32) (MapdDownloadProgress_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2}, sz)
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 48, PointerCount: 5}, sz)
 	return capnp.StructList[MapdDownloadProgress](l), err
 }
 
@@ -1355,6 +1483,132 @@ func (f MapdDownloadProgress_Future) Struct() (MapdDownloadProgress, error) {
 	return MapdDownloadProgress(p.Struct()), err
 }
 
+type MapdDownloadFailure capnp.Struct
+
+// MapdDownloadFailure_TypeID is the unique identifier for the type MapdDownloadFailure.
+const MapdDownloadFailure_TypeID = 0xe830550b45c292f6
+
+func NewMapdDownloadFailure(s *capnp.Segment) (MapdDownloadFailure, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 3})
+	return MapdDownloadFailure(st), err
+}
+
+func NewRootMapdDownloadFailure(s *capnp.Segment) (MapdDownloadFailure, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 3})
+	return MapdDownloadFailure(st), err
+}
+
+func ReadRootMapdDownloadFailure(msg *capnp.Message) (MapdDownloadFailure, error) {
+	root, err := msg.Root()
+	return MapdDownloadFailure(root.Struct()), err
+}
+
+func (s MapdDownloadFailure) String() string {
+	str, _ := text.Marshal(0xe830550b45c292f6, capnp.Struct(s))
+	return str
+}
+
+func (s MapdDownloadFailure) EncodeAsPtr(seg *capnp.Segment) capnp.Ptr {
+	return capnp.Struct(s).EncodeAsPtr(seg)
+}
+
+func (MapdDownloadFailure) DecodeFromPtr(p capnp.Ptr) MapdDownloadFailure {
+	return MapdDownloadFailure(capnp.Struct{}.DecodeFromPtr(p))
+}
+
+func (s MapdDownloadFailure) ToPtr() capnp.Ptr {
+	return capnp.Struct(s).ToPtr()
+}
+func (s MapdDownloadFailure) IsValid() bool {
+	return capnp.Struct(s).IsValid()
+}
+
+func (s MapdDownloadFailure) Message() *capnp.Message {
+	return capnp.Struct(s).Message()
+}
+
+func (s MapdDownloadFailure) Segment() *capnp.Segment {
+	return capnp.Struct(s).Segment()
+}
+func (s MapdDownloadFailure) File() (string, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.Text(), err
+}
+
+func (s MapdDownloadFailure) HasFile() bool {
+	return capnp.Struct(s).HasPtr(0)
+}
+
+func (s MapdDownloadFailure) FileBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.TextBytes(), err
+}
+
+func (s MapdDownloadFailure) SetFile(v string) error {
+	return capnp.Struct(s).SetText(0, v)
+}
+
+func (s MapdDownloadFailure) Location() (string, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.Text(), err
+}
+
+func (s MapdDownloadFailure) HasLocation() bool {
+	return capnp.Struct(s).HasPtr(1)
+}
+
+func (s MapdDownloadFailure) LocationBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.TextBytes(), err
+}
+
+func (s MapdDownloadFailure) SetLocation(v string) error {
+	return capnp.Struct(s).SetText(1, v)
+}
+
+func (s MapdDownloadFailure) Error() (string, error) {
+	p, err := capnp.Struct(s).Ptr(2)
+	return p.Text(), err
+}
+
+func (s MapdDownloadFailure) HasError() bool {
+	return capnp.Struct(s).HasPtr(2)
+}
+
+func (s MapdDownloadFailure) ErrorBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(2)
+	return p.TextBytes(), err
+}
+
+func (s MapdDownloadFailure) SetError(v string) error {
+	return capnp.Struct(s).SetText(2, v)
+}
+
+func (s MapdDownloadFailure) Attempts() uint32 {
+	return capnp.Struct(s).Uint32(0)
+}
+
+func (s MapdDownloadFailure) SetAttempts(v uint32) {
+	capnp.Struct(s).SetUint32(0, v)
+}
+
+// MapdDownloadFailure_List is a list of MapdDownloadFailure.
+type MapdDownloadFailure_List = capnp.StructList[MapdDownloadFailure]
+
+// NewMapdDownloadFailure creates a new list of MapdDownloadFailure.
+func NewMapdDownloadFailure_List(s *capnp.Segment, sz int32) (MapdDownloadFailure_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 3}, sz)
+	return capnp.StructList[MapdDownloadFailure](l), err
+}
+
+// MapdDownloadFailure_Future is a wrapper for a MapdDownloadFailure promised by a client call.
+type MapdDownloadFailure_Future struct{ *capnp.Future }
+
+func (f MapdDownloadFailure_Future) Struct() (MapdDownloadFailure, error) {
+	p, err := f.Future.Ptr()
+	return MapdDownloadFailure(p.Struct()), err
+}
+
 type MapdPathPoint capnp.Struct
 
 // MapdPathPoint_TypeID is the unique identifier for the type MapdPathPoint.
@@ -1531,164 +1785,753 @@ func (f MapdPosition_Future) Struct() (MapdPosition, error) {
 	return MapdPosition(p.Struct()), err
 }
 
-type MapdExtendedOut capnp.Struct
+type MapdInstalledRegion capnp.Struct
 
-// MapdExtendedOut_TypeID is the unique identifier for the type MapdExtendedOut.
-const MapdExtendedOut_TypeID = 0xa30662f84033036c
+// MapdInstalledRegion_TypeID is the unique identifier for the type MapdInstalledRegion.
+const MapdInstalledRegion_TypeID = 0xbafa62a5f42ae3c4
 
-func NewMapdExtendedOut(s *capnp.Segment) (MapdExtendedOut, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 4})
-	return MapdExtendedOut(st), err
+func NewMapdInstalledRegion(s *capnp.Segment) (MapdInstalledRegion, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 24, PointerCount: 3})
+	return MapdInstalledRegion(st), err
 }
 
-func NewRootMapdExtendedOut(s *capnp.Segment) (MapdExtendedOut, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 4})
-	return MapdExtendedOut(st), err
+func NewRootMapdInstalledRegion(s *capnp.Segment) (MapdInstalledRegion, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 24, PointerCount: 3})
+	return MapdInstalledRegion(st), err
 }
 
-func ReadRootMapdExtendedOut(msg *capnp.Message) (MapdExtendedOut, error) {
+func ReadRootMapdInstalledRegion(msg *capnp.Message) (MapdInstalledRegion, error) {
 	root, err := msg.Root()
-	return MapdExtendedOut(root.Struct()), err
+	return MapdInstalledRegion(root.Struct()), err
 }
 
-func (s MapdExtendedOut) String() string {
-	str, _ := text.Marshal(0xa30662f84033036c, capnp.Struct(s))
+func (s MapdInstalledRegion) String() string {
+	str, _ := text.Marshal(0xbafa62a5f42ae3c4, capnp.Struct(s))
 	return str
 }
 
-func (s MapdExtendedOut) EncodeAsPtr(seg *capnp.Segment) capnp.Ptr {
+func (s MapdInstalledRegion) EncodeAsPtr(seg *capnp.Segment) capnp.Ptr {
 	return capnp.Struct(s).EncodeAsPtr(seg)
 }
 
-func (MapdExtendedOut) DecodeFromPtr(p capnp.Ptr) MapdExtendedOut {
-	return MapdExtendedOut(capnp.Struct{}.DecodeFromPtr(p))
+func (MapdInstalledRegion) DecodeFromPtr(p capnp.Ptr) MapdInstalledRegion {
+	return MapdInstalledRegion(capnp.Struct{}.DecodeFromPtr(p))
 }
 
-func (s MapdExtendedOut) ToPtr() capnp.Ptr {
+func (s MapdInstalledRegion) ToPtr() capnp.Ptr {
 	return capnp.Struct(s).ToPtr()
 }
-func (s MapdExtendedOut) IsValid() bool {
+func (s MapdInstalledRegion) IsValid() bool {
 	return capnp.Struct(s).IsValid()
 }
 
-func (s MapdExtendedOut) Message() *capnp.Message {
+func (s MapdInstalledRegion) Message() *capnp.Message {
 	return capnp.Struct(s).Message()
 }
 
-func (s MapdExtendedOut) Segment() *capnp.Segment {
+func (s MapdInstalledRegion) Segment() *capnp.Segment {
 	return capnp.Struct(s).Segment()
 }
-func (s MapdExtendedOut) DownloadProgress() (MapdDownloadProgress, error) {
+func (s MapdInstalledRegion) Name() (string, error) {
 	p, err := capnp.Struct(s).Ptr(0)
-	return MapdDownloadProgress(p.Struct()), err
+	return p.Text(), err
 }
 
-func (s MapdExtendedOut) HasDownloadProgress() bool {
+func (s MapdInstalledRegion) HasName() bool {
 	return capnp.Struct(s).HasPtr(0)
 }
 
-func (s MapdExtendedOut) SetDownloadProgress(v MapdDownloadProgress) error {
-	return capnp.Struct(s).SetPtr(0, capnp.Struct(v).ToPtr())
+func (s MapdInstalledRegion) NameBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.TextBytes(), err
 }
 
-// NewDownloadProgress sets the downloadProgress field to a newly
-// allocated MapdDownloadProgress struct, preferring placement in s's segment.
-func (s MapdExtendedOut) NewDownloadProgress() (MapdDownloadProgress, error) {
-	ss, err := NewMapdDownloadProgress(capnp.Struct(s).Segment())
-	if err != nil {
-		return MapdDownloadProgress{}, err
-	}
-	err = capnp.Struct(s).SetPtr(0, capnp.Struct(ss).ToPtr())
-	return ss, err
+func (s MapdInstalledRegion) SetName(v string) error {
+	return capnp.Struct(s).SetText(0, v)
 }
 
-func (s MapdExtendedOut) Settings() (string, error) {
+func (s MapdInstalledRegion) FullName() (string, error) {
 	p, err := capnp.Struct(s).Ptr(1)
 	return p.Text(), err
 }
 
-func (s MapdExtendedOut) HasSettings() bool {
+func (s MapdInstalledRegion) HasFullName() bool {
 	return capnp.Struct(s).HasPtr(1)
 }
 
-func (s MapdExtendedOut) SettingsBytes() ([]byte, error) {
+func (s MapdInstalledRegion) FullNameBytes() ([]byte, error) {
 	p, err := capnp.Struct(s).Ptr(1)
 	return p.TextBytes(), err
 }
 
-func (s MapdExtendedOut) SetSettings(v string) error {
+func (s MapdInstalledRegion) SetFullName(v string) error {
 	return capnp.Struct(s).SetText(1, v)
 }
 
-func (s MapdExtendedOut) Path() (MapdPathPoint_List, error) {
-	p, err := capnp.Struct(s).Ptr(2)
-	return MapdPathPoint_List(p.List()), err
+func (s MapdInstalledRegion) Files() uint32 {
+	return capnp.Struct(s).Uint32(0)
 }
 
-func (s MapdExtendedOut) HasPath() bool {
-	return capnp.Struct(s).HasPtr(2)
+func (s MapdInstalledRegion) SetFiles(v uint32) {
+	capnp.Struct(s).SetUint32(0, v)
 }
 
-func (s MapdExtendedOut) SetPath(v MapdPathPoint_List) error {
-	return capnp.Struct(s).SetPtr(2, v.ToPtr())
+func (s MapdInstalledRegion) SizeBytes() uint64 {
+	return capnp.Struct(s).Uint64(8)
 }
 
-// NewPath sets the path field to a newly
-// allocated MapdPathPoint_List, preferring placement in s's segment.
-func (s MapdExtendedOut) NewPath(n int32) (MapdPathPoint_List, error) {
-	l, err := NewMapdPathPoint_List(capnp.Struct(s).Segment(), n)
-	if err != nil {
-		return MapdPathPoint_List{}, err
-	}
-	err = capnp.Struct(s).SetPtr(2, l.ToPtr())
-	return l, err
+func (s MapdInstalledRegion) SetSizeBytes(v uint64) {
+	capnp.Struct(s).SetUint64(8, v)
 }
-func (s MapdExtendedOut) Position() (MapdPosition, error) {
-	p, err := capnp.Struct(s).Ptr(3)
-	return MapdPosition(p.Struct()), err
+
+func (s MapdInstalledRegion) DataVersion() (string, error) {
+	p, err := capnp.Struct(s).Ptr(2)
+	return p.Text(), err
 }
 
-func (s MapdExtendedOut) HasPosition() bool {
-	return capnp.Struct(s).HasPtr(3)
+func (s MapdInstalledRegion) HasDataVersion() bool {
+	return capnp.Struct(s).HasPtr(2)
 }
 
-func (s MapdExtendedOut) SetPosition(v MapdPosition) error {
-	return capnp.Struct(s).SetPtr(3, capnp.Struct(v).ToPtr())
+func (s MapdInstalledRegion) DataVersionBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(2)
+	return p.TextBytes(), err
 }
 
-// NewPosition sets the position field to a newly
-// allocated MapdPosition struct, preferring placement in s's segment.
-func (s MapdExtendedOut) NewPosition() (MapdPosition, error) {
-	ss, err := NewMapdPosition(capnp.Struct(s).Segment())
-	if err != nil {
-		return MapdPosition{}, err
-	}
-	err = capnp.Struct(s).SetPtr(3, capnp.Struct(ss).ToPtr())
-	return ss, err
+func (s MapdInstalledRegion) SetDataVersion(v string) error {
+	return capnp.Struct(s).SetText(2, v)
 }
 
-func (s MapdExtendedOut) LoopRateAverage() float32 {
-	return math.Float32frombits(capnp.Struct(s).Uint32(0))
+func (s MapdInstalledRegion) InstalledAt() int64 {
+	return int64(capnp.Struct(s).Uint64(16))
 }
 
-func (s MapdExtendedOut) SetLoopRateAverage(v float32) {
-	capnp.Struct(s).SetUint32(0, math.Float32bits(v))
+func (s MapdInstalledRegion) SetInstalledAt(v int64) {
+	capnp.Struct(s).SetUint64(16, uint64(v))
 }
 
-func (s MapdExtendedOut) LoopRateMin() float32 {
-	return math.Float32frombits(capnp.Struct(s).Uint32(4))
+// MapdInstalledRegion_List is a list of MapdInstalledRegion.
+type MapdInstalledRegion_List = capnp.StructList[MapdInstalledRegion]
+
+// NewMapdInstalledRegion creates a new list of MapdInstalledRegion.
+func NewMapdInstalledRegion_List(s *capnp.Segment, sz int32) (MapdInstalledRegion_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 24, PointerCount: 3}, sz)
+	return capnp.StructList[MapdInstalledRegion](l), err
 }
 
-func (s MapdExtendedOut) SetLoopRateMin(v float32) {
-	capnp.Struct(s).SetUint32(4, math.Float32bits(v))
+// MapdInstalledRegion_Future is a wrapper for a MapdInstalledRegion promised by a client call.
+type MapdInstalledRegion_Future struct{ *capnp.Future }
+
+func (f MapdInstalledRegion_Future) Struct() (MapdInstalledRegion, error) {
+	p, err := f.Future.Ptr()
+	return MapdInstalledRegion(p.Struct()), err
 }
 
-// MapdExtendedOut_List is a list of MapdExtendedOut.
-type MapdExtendedOut_List = capnp.StructList[MapdExtendedOut]
+type MapdEviction capnp.Struct
 
-// NewMapdExtendedOut creates a new list of MapdExtendedOut.
+// MapdEviction_TypeID is the unique identifier for the type MapdEviction.
+const MapdEviction_TypeID = 0x8c5a0932805fcaaf
+
+func NewMapdEviction(s *capnp.Segment) (MapdEviction, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 1})
+	return MapdEviction(st), err
+}
+
+func NewRootMapdEviction(s *capnp.Segment) (MapdEviction, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 1})
+	return MapdEviction(st), err
+}
+
+func ReadRootMapdEviction(msg *capnp.Message) (MapdEviction, error) {
+	root, err := msg.Root()
+	return MapdEviction(root.Struct()), err
+}
+
+func (s MapdEviction) String() string {
+	str, _ := text.Marshal(0x8c5a0932805fcaaf, capnp.Struct(s))
+	return str
+}
+
+func (s MapdEviction) EncodeAsPtr(seg *capnp.Segment) capnp.Ptr {
+	return capnp.Struct(s).EncodeAsPtr(seg)
+}
+
+func (MapdEviction) DecodeFromPtr(p capnp.Ptr) MapdEviction {
+	return MapdEviction(capnp.Struct{}.DecodeFromPtr(p))
+}
+
+func (s MapdEviction) ToPtr() capnp.Ptr {
+	return capnp.Struct(s).ToPtr()
+}
+func (s MapdEviction) IsValid() bool {
+	return capnp.Struct(s).IsValid()
+}
+
+func (s MapdEviction) Message() *capnp.Message {
+	return capnp.Struct(s).Message()
+}
+
+func (s MapdEviction) Segment() *capnp.Segment {
+	return capnp.Struct(s).Segment()
+}
+func (s MapdEviction) File() (string, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.Text(), err
+}
+
+func (s MapdEviction) HasFile() bool {
+	return capnp.Struct(s).HasPtr(0)
+}
+
+func (s MapdEviction) FileBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.TextBytes(), err
+}
+
+func (s MapdEviction) SetFile(v string) error {
+	return capnp.Struct(s).SetText(0, v)
+}
+
+func (s MapdEviction) SizeBytes() uint64 {
+	return capnp.Struct(s).Uint64(0)
+}
+
+func (s MapdEviction) SetSizeBytes(v uint64) {
+	capnp.Struct(s).SetUint64(0, v)
+}
+
+func (s MapdEviction) EvictedAt() int64 {
+	return int64(capnp.Struct(s).Uint64(8))
+}
+
+func (s MapdEviction) SetEvictedAt(v int64) {
+	capnp.Struct(s).SetUint64(8, uint64(v))
+}
+
+// MapdEviction_List is a list of MapdEviction.
+type MapdEviction_List = capnp.StructList[MapdEviction]
+
+// NewMapdEviction creates a new list of MapdEviction.
+func NewMapdEviction_List(s *capnp.Segment, sz int32) (MapdEviction_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 16, PointerCount: 1}, sz)
+	return capnp.StructList[MapdEviction](l), err
+}
+
+// MapdEviction_Future is a wrapper for a MapdEviction promised by a client call.
+type MapdEviction_Future struct{ *capnp.Future }
+
+func (f MapdEviction_Future) Struct() (MapdEviction, error) {
+	p, err := f.Future.Ptr()
+	return MapdEviction(p.Struct()), err
+}
+
+type MapdNearbyRegion capnp.Struct
+
+// MapdNearbyRegion_TypeID is the unique identifier for the type MapdNearbyRegion.
+const MapdNearbyRegion_TypeID = 0xdb986541c9b0628f
+
+func NewMapdNearbyRegion(s *capnp.Segment) (MapdNearbyRegion, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2})
+	return MapdNearbyRegion(st), err
+}
+
+func NewRootMapdNearbyRegion(s *capnp.Segment) (MapdNearbyRegion, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2})
+	return MapdNearbyRegion(st), err
+}
+
+func ReadRootMapdNearbyRegion(msg *capnp.Message) (MapdNearbyRegion, error) {
+	root, err := msg.Root()
+	return MapdNearbyRegion(root.Struct()), err
+}
+
+func (s MapdNearbyRegion) String() string {
+	str, _ := text.Marshal(0xdb986541c9b0628f, capnp.Struct(s))
+	return str
+}
+
+func (s MapdNearbyRegion) EncodeAsPtr(seg *capnp.Segment) capnp.Ptr {
+	return capnp.Struct(s).EncodeAsPtr(seg)
+}
+
+func (MapdNearbyRegion) DecodeFromPtr(p capnp.Ptr) MapdNearbyRegion {
+	return MapdNearbyRegion(capnp.Struct{}.DecodeFromPtr(p))
+}
+
+func (s MapdNearbyRegion) ToPtr() capnp.Ptr {
+	return capnp.Struct(s).ToPtr()
+}
+func (s MapdNearbyRegion) IsValid() bool {
+	return capnp.Struct(s).IsValid()
+}
+
+func (s MapdNearbyRegion) Message() *capnp.Message {
+	return capnp.Struct(s).Message()
+}
+
+func (s MapdNearbyRegion) Segment() *capnp.Segment {
+	return capnp.Struct(s).Segment()
+}
+func (s MapdNearbyRegion) Name() (string, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.Text(), err
+}
+
+func (s MapdNearbyRegion) HasName() bool {
+	return capnp.Struct(s).HasPtr(0)
+}
+
+func (s MapdNearbyRegion) NameBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.TextBytes(), err
+}
+
+func (s MapdNearbyRegion) SetName(v string) error {
+	return capnp.Struct(s).SetText(0, v)
+}
+
+func (s MapdNearbyRegion) FullName() (string, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.Text(), err
+}
+
+func (s MapdNearbyRegion) HasFullName() bool {
+	return capnp.Struct(s).HasPtr(1)
+}
+
+func (s MapdNearbyRegion) FullNameBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.TextBytes(), err
+}
+
+func (s MapdNearbyRegion) SetFullName(v string) error {
+	return capnp.Struct(s).SetText(1, v)
+}
+
+func (s MapdNearbyRegion) Files() uint32 {
+	return capnp.Struct(s).Uint32(0)
+}
+
+func (s MapdNearbyRegion) SetFiles(v uint32) {
+	capnp.Struct(s).SetUint32(0, v)
+}
+
+func (s MapdNearbyRegion) InstalledFiles() uint32 {
+	return capnp.Struct(s).Uint32(4)
+}
+
+func (s MapdNearbyRegion) SetInstalledFiles(v uint32) {
+	capnp.Struct(s).SetUint32(4, v)
+}
+
+func (s MapdNearbyRegion) Installed() bool {
+	return capnp.Struct(s).Bit(64)
+}
+
+func (s MapdNearbyRegion) SetInstalled(v bool) {
+	capnp.Struct(s).SetBit(64, v)
+}
+
+// MapdNearbyRegion_List is a list of MapdNearbyRegion.
+type MapdNearbyRegion_List = capnp.StructList[MapdNearbyRegion]
+
+// NewMapdNearbyRegion creates a new list of MapdNearbyRegion.
+func NewMapdNearbyRegion_List(s *capnp.Segment, sz int32) (MapdNearbyRegion_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2}, sz)
+	return capnp.StructList[MapdNearbyRegion](l), err
+}
+
+// MapdNearbyRegion_Future is a wrapper for a MapdNearbyRegion promised by a client call.
+type MapdNearbyRegion_Future struct{ *capnp.Future }
+
+func (f MapdNearbyRegion_Future) Struct() (MapdNearbyRegion, error) {
+	p, err := f.Future.Ptr()
+	return MapdNearbyRegion(p.Struct()), err
+}
+
+type MapdMapData capnp.Struct
+
+// MapdMapData_TypeID is the unique identifier for the type MapdMapData.
+const MapdMapData_TypeID = 0xd0799a7b5d0bc224
+
+func NewMapdMapData(s *capnp.Segment) (MapdMapData, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2})
+	return MapdMapData(st), err
+}
+
+func NewRootMapdMapData(s *capnp.Segment) (MapdMapData, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2})
+	return MapdMapData(st), err
+}
+
+func ReadRootMapdMapData(msg *capnp.Message) (MapdMapData, error) {
+	root, err := msg.Root()
+	return MapdMapData(root.Struct()), err
+}
+
+func (s MapdMapData) String() string {
+	str, _ := text.Marshal(0xd0799a7b5d0bc224, capnp.Struct(s))
+	return str
+}
+
+func (s MapdMapData) EncodeAsPtr(seg *capnp.Segment) capnp.Ptr {
+	return capnp.Struct(s).EncodeAsPtr(seg)
+}
+
+func (MapdMapData) DecodeFromPtr(p capnp.Ptr) MapdMapData {
+	return MapdMapData(capnp.Struct{}.DecodeFromPtr(p))
+}
+
+func (s MapdMapData) ToPtr() capnp.Ptr {
+	return capnp.Struct(s).ToPtr()
+}
+func (s MapdMapData) IsValid() bool {
+	return capnp.Struct(s).IsValid()
+}
+
+func (s MapdMapData) Message() *capnp.Message {
+	return capnp.Struct(s).Message()
+}
+
+func (s MapdMapData) Segment() *capnp.Segment {
+	return capnp.Struct(s).Segment()
+}
+func (s MapdMapData) Date() int64 {
+	return int64(capnp.Struct(s).Uint64(0))
+}
+
+func (s MapdMapData) SetDate(v int64) {
+	capnp.Struct(s).SetUint64(0, uint64(v))
+}
+
+func (s MapdMapData) FormatVersion() uint16 {
+	return capnp.Struct(s).Uint16(8)
+}
+
+func (s MapdMapData) SetFormatVersion(v uint16) {
+	capnp.Struct(s).SetUint16(8, v)
+}
+
+func (s MapdMapData) GeneratorVersion() (string, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.Text(), err
+}
+
+func (s MapdMapData) HasGeneratorVersion() bool {
+	return capnp.Struct(s).HasPtr(0)
+}
+
+func (s MapdMapData) GeneratorVersionBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.TextBytes(), err
+}
+
+func (s MapdMapData) SetGeneratorVersion(v string) error {
+	return capnp.Struct(s).SetText(0, v)
+}
+
+func (s MapdMapData) SourceHash() (string, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.Text(), err
+}
+
+func (s MapdMapData) HasSourceHash() bool {
+	return capnp.Struct(s).HasPtr(1)
+}
+
+func (s MapdMapData) SourceHashBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.TextBytes(), err
+}
+
+func (s MapdMapData) SetSourceHash(v string) error {
+	return capnp.Struct(s).SetText(1, v)
+}
+
+// MapdMapData_List is a list of MapdMapData.
+type MapdMapData_List = capnp.StructList[MapdMapData]
+
+// NewMapdMapData creates a new list of MapdMapData.
+func NewMapdMapData_List(s *capnp.Segment, sz int32) (MapdMapData_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2}, sz)
+	return capnp.StructList[MapdMapData](l), err
+}
+
+// MapdMapData_Future is a wrapper for a MapdMapData promised by a client call.
+type MapdMapData_Future struct{ *capnp.Future }
+
+func (f MapdMapData_Future) Struct() (MapdMapData, error) {
+	p, err := f.Future.Ptr()
+	return MapdMapData(p.Struct()), err
+}
+
+type MapdExtendedOut capnp.Struct
+
+// MapdExtendedOut_TypeID is the unique identifier for the type MapdExtendedOut.
+const MapdExtendedOut_TypeID = 0xa30662f84033036c
+
+func NewMapdExtendedOut(s *capnp.Segment) (MapdExtendedOut, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 24, PointerCount: 8})
+	return MapdExtendedOut(st), err
+}
+
+func NewRootMapdExtendedOut(s *capnp.Segment) (MapdExtendedOut, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 24, PointerCount: 8})
+	return MapdExtendedOut(st), err
+}
+
+func ReadRootMapdExtendedOut(msg *capnp.Message) (MapdExtendedOut, error) {
+	root, err := msg.Root()
+	return MapdExtendedOut(root.Struct()), err
+}
+
+func (s MapdExtendedOut) String() string {
+	str, _ := text.Marshal(0xa30662f84033036c, capnp.Struct(s))
+	return str
+}
+
+func (s MapdExtendedOut) EncodeAsPtr(seg *capnp.Segment) capnp.Ptr {
+	return capnp.Struct(s).EncodeAsPtr(seg)
+}
+
+func (MapdExtendedOut) DecodeFromPtr(p capnp.Ptr) MapdExtendedOut {
+	return MapdExtendedOut(capnp.Struct{}.DecodeFromPtr(p))
+}
+
+func (s MapdExtendedOut) ToPtr() capnp.Ptr {
+	return capnp.Struct(s).ToPtr()
+}
+func (s MapdExtendedOut) IsValid() bool {
+	return capnp.Struct(s).IsValid()
+}
+
+func (s MapdExtendedOut) Message() *capnp.Message {
+	return capnp.Struct(s).Message()
+}
+
+func (s MapdExtendedOut) Segment() *capnp.Segment {
+	return capnp.Struct(s).Segment()
+}
+func (s MapdExtendedOut) DownloadProgress() (MapdDownloadProgress, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return MapdDownloadProgress(p.Struct()), err
+}
+
+func (s MapdExtendedOut) HasDownloadProgress() bool {
+	return capnp.Struct(s).HasPtr(0)
+}
+
+func (s MapdExtendedOut) SetDownloadProgress(v MapdDownloadProgress) error {
+	return capnp.Struct(s).SetPtr(0, capnp.Struct(v).ToPtr())
+}
+
+// NewDownloadProgress sets the downloadProgress field to a newly
+// allocated MapdDownloadProgress struct, preferring placement in s's segment.
+func (s MapdExtendedOut) NewDownloadProgress() (MapdDownloadProgress, error) {
+	ss, err := NewMapdDownloadProgress(capnp.Struct(s).Segment())
+	if err != nil {
+		return MapdDownloadProgress{}, err
+	}
+	err = capnp.Struct(s).SetPtr(0, capnp.Struct(ss).ToPtr())
+	return ss, err
+}
+
+func (s MapdExtendedOut) Settings() (string, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.Text(), err
+}
+
+func (s MapdExtendedOut) HasSettings() bool {
+	return capnp.Struct(s).HasPtr(1)
+}
+
+func (s MapdExtendedOut) SettingsBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.TextBytes(), err
+}
+
+func (s MapdExtendedOut) SetSettings(v string) error {
+	return capnp.Struct(s).SetText(1, v)
+}
+
+func (s MapdExtendedOut) Path() (MapdPathPoint_List, error) {
+	p, err := capnp.Struct(s).Ptr(2)
+	return MapdPathPoint_List(p.List()), err
+}
+
+func (s MapdExtendedOut) HasPath() bool {
+	return capnp.Struct(s).HasPtr(2)
+}
+
+func (s MapdExtendedOut) SetPath(v MapdPathPoint_List) error {
+	return capnp.Struct(s).SetPtr(2, v.ToPtr())
+}
+
+// NewPath sets the path field to a newly
+// allocated MapdPathPoint_List, preferring placement in s's segment.
+func (s MapdExtendedOut) NewPath(n int32) (MapdPathPoint_List, error) {
+	l, err := NewMapdPathPoint_List(capnp.Struct(s).Segment(), n)
+	if err != nil {
+		return MapdPathPoint_List{}, err
+	}
+	err = capnp.Struct(s).SetPtr(2, l.ToPtr())
+	return l, err
+}
+func (s MapdExtendedOut) Position() (MapdPosition, error) {
+	p, err := capnp.Struct(s).Ptr(3)
+	return MapdPosition(p.Struct()), err
+}
+
+func (s MapdExtendedOut) HasPosition() bool {
+	return capnp.Struct(s).HasPtr(3)
+}
+
+func (s MapdExtendedOut) SetPosition(v MapdPosition) error {
+	return capnp.Struct(s).SetPtr(3, capnp.Struct(v).ToPtr())
+}
+
+// NewPosition sets the position field to a newly
+// allocated MapdPosition struct, preferring placement in s's segment.
+func (s MapdExtendedOut) NewPosition() (MapdPosition, error) {
+	ss, err := NewMapdPosition(capnp.Struct(s).Segment())
+	if err != nil {
+		return MapdPosition{}, err
+	}
+	err = capnp.Struct(s).SetPtr(3, capnp.Struct(ss).ToPtr())
+	return ss, err
+}
+
+func (s MapdExtendedOut) LoopRateAverage() float32 {
+	return math.Float32frombits(capnp.Struct(s).Uint32(0))
+}
+
+func (s MapdExtendedOut) SetLoopRateAverage(v float32) {
+	capnp.Struct(s).SetUint32(0, math.Float32bits(v))
+}
+
+func (s MapdExtendedOut) LoopRateMin() float32 {
+	return math.Float32frombits(capnp.Struct(s).Uint32(4))
+}
+
+func (s MapdExtendedOut) SetLoopRateMin(v float32) {
+	capnp.Struct(s).SetUint32(4, math.Float32bits(v))
+}
+
+func (s MapdExtendedOut) InstalledRegions() (MapdInstalledRegion_List, error) {
+	p, err := capnp.Struct(s).Ptr(4)
+	return MapdInstalledRegion_List(p.List()), err
+}
+
+func (s MapdExtendedOut) HasInstalledRegions() bool {
+	return capnp.Struct(s).HasPtr(4)
+}
+
+func (s MapdExtendedOut) SetInstalledRegions(v MapdInstalledRegion_List) error {
+	return capnp.Struct(s).SetPtr(4, v.ToPtr())
+}
+
+// NewInstalledRegions sets the installedRegions field to a newly
+// allocated MapdInstalledRegion_List, preferring placement in s's segment.
+func (s MapdExtendedOut) NewInstalledRegions(n int32) (MapdInstalledRegion_List, error) {
+	l, err := NewMapdInstalledRegion_List(capnp.Struct(s).Segment(), n)
+	if err != nil {
+		return MapdInstalledRegion_List{}, err
+	}
+	err = capnp.Struct(s).SetPtr(4, l.ToPtr())
+	return l, err
+}
+func (s MapdExtendedOut) InstalledSizeBytes() uint64 {
+	return capnp.Struct(s).Uint64(8)
+}
+
+func (s MapdExtendedOut) SetInstalledSizeBytes(v uint64) {
+	capnp.Struct(s).SetUint64(8, v)
+}
+
+func (s MapdExtendedOut) DiskQuotaBytes() uint64 {
+	return capnp.Struct(s).Uint64(16)
+}
+
+func (s MapdExtendedOut) SetDiskQuotaBytes(v uint64) {
+	capnp.Struct(s).SetUint64(16, v)
+}
+
+func (s MapdExtendedOut) Evictions() (MapdEviction_List, error) {
+	p, err := capnp.Struct(s).Ptr(5)
+	return MapdEviction_List(p.List()), err
+}
+
+func (s MapdExtendedOut) HasEvictions() bool {
+	return capnp.Struct(s).HasPtr(5)
+}
+
+func (s MapdExtendedOut) SetEvictions(v MapdEviction_List) error {
+	return capnp.Struct(s).SetPtr(5, v.ToPtr())
+}
+
+// NewEvictions sets the evictions field to a newly
+// allocated MapdEviction_List, preferring placement in s's segment.
+func (s MapdExtendedOut) NewEvictions(n int32) (MapdEviction_List, error) {
+	l, err := NewMapdEviction_List(capnp.Struct(s).Segment(), n)
+	if err != nil {
+		return MapdEviction_List{}, err
+	}
+	err = capnp.Struct(s).SetPtr(5, l.ToPtr())
+	return l, err
+}
+func (s MapdExtendedOut) NearbyRegions() (MapdNearbyRegion_List, error) {
+	p, err := capnp.Struct(s).Ptr(6)
+	return MapdNearbyRegion_List(p.List()), err
+}
+
+func (s MapdExtendedOut) HasNearbyRegions() bool {
+	return capnp.Struct(s).HasPtr(6)
+}
+
+func (s MapdExtendedOut) SetNearbyRegions(v MapdNearbyRegion_List) error {
+	return capnp.Struct(s).SetPtr(6, v.ToPtr())
+}
+
+// NewNearbyRegions sets the nearbyRegions field to a newly
+// allocated MapdNearbyRegion_List, preferring placement in s's segment.
+func (s MapdExtendedOut) NewNearbyRegions(n int32) (MapdNearbyRegion_List, error) {
+	l, err := NewMapdNearbyRegion_List(capnp.Struct(s).Segment(), n)
+	if err != nil {
+		return MapdNearbyRegion_List{}, err
+	}
+	err = capnp.Struct(s).SetPtr(6, l.ToPtr())
+	return l, err
+}
+func (s MapdExtendedOut) MapData() (MapdMapData, error) {
+	p, err := capnp.Struct(s).Ptr(7)
+	return MapdMapData(p.Struct()), err
+}
+
+func (s MapdExtendedOut) HasMapData() bool {
+	return capnp.Struct(s).HasPtr(7)
+}
+
+func (s MapdExtendedOut) SetMapData(v MapdMapData) error {
+	return capnp.Struct(s).SetPtr(7, capnp.Struct(v).ToPtr())
+}
+
+// NewMapData sets the mapData field to a newly
+// allocated MapdMapData struct, preferring placement in s's segment.
+func (s MapdExtendedOut) NewMapData() (MapdMapData, error) {
+	ss, err := NewMapdMapData(capnp.Struct(s).Segment())
+	if err != nil {
+		return MapdMapData{}, err
+	}
+	err = capnp.Struct(s).SetPtr(7, capnp.Struct(ss).ToPtr())
+	return ss, err
+}
+
+// MapdExtendedOut_List is a list of MapdExtendedOut.
+type MapdExtendedOut_List = capnp.StructList[MapdExtendedOut]
+
+// NewMapdExtendedOut creates a new list of MapdExtendedOut.
 func NewMapdExtendedOut_List(s *capnp.Segment, sz int32) (MapdExtendedOut_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 4}, sz)
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 24, PointerCount: 8}, sz)
 	return capnp.StructList[MapdExtendedOut](l), err
 }
 
@@ -1705,6 +2548,9 @@ func (p MapdExtendedOut_Future) DownloadProgress() MapdDownloadProgress_Future {
 func (p MapdExtendedOut_Future) Position() MapdPosition_Future {
 	return MapdPosition_Future{Future: p.Future.Field(3, nil)}
 }
+func (p MapdExtendedOut_Future) MapData() MapdMapData_Future {
+	return MapdMapData_Future{Future: p.Future.Field(7, nil)}
+}
 
 type MapdInputType uint16
 
@@ -1760,6 +2606,12 @@ const (
 	MapdInputType_setShadowModelV2                       MapdInputType = 41
 	MapdInputType_setShadowGpsLocation                   MapdInputType = 42
 	MapdInputType_setShadowGpsLocationExternal           MapdInputType = 46
+	MapdInputType_checkForUpdates                        MapdInputType = 47
+	MapdInputType_updateMaps                             MapdInputType = 48
+	MapdInputType_downloadRadius                         MapdInputType = 49
+	MapdInputType_setMeteredConnection                   MapdInputType = 50
+	MapdInputType_setMapSources                          MapdInputType = 51
+	MapdInputType_deleteRegion                           MapdInputType = 52
 )
 
 // String returns the enum's constant name.
@@ -1859,6 +2711,18 @@ func (c MapdInputType) String() string {
 		return "setShadowGpsLocation"
 	case MapdInputType_setShadowGpsLocationExternal:
 		return "setShadowGpsLocationExternal"
+	case MapdInputType_checkForUpdates:
+		return "checkForUpdates"
+	case MapdInputType_updateMaps:
+		return "updateMaps"
+	case MapdInputType_downloadRadius:
+		return "downloadRadius"
+	case MapdInputType_setMeteredConnection:
+		return "setMeteredConnection"
+	case MapdInputType_setMapSources:
+		return "setMapSources"
+	case MapdInputType_deleteRegion:
+		return "deleteRegion"
 
 	default:
 		return ""
@@ -1963,6 +2827,18 @@ func MapdInputTypeFromString(c string) MapdInputType {
 		return MapdInputType_setShadowGpsLocation
 	case "setShadowGpsLocationExternal":
 		return MapdInputType_setShadowGpsLocationExternal
+	case "checkForUpdates":
+		return MapdInputType_checkForUpdates
+	case "updateMaps":
+		return MapdInputType_updateMaps
+	case "downloadRadius":
+		return MapdInputType_downloadRadius
+	case "setMeteredConnection":
+		return MapdInputType_setMeteredConnection
+	case "setMapSources":
+		return MapdInputType_setMapSources
+	case "deleteRegion":
+		return MapdInputType_deleteRegion
 
 	default:
 		return 0
@@ -2695,212 +3571,280 @@ func (f MapdOut_Future) Struct() (MapdOut, error) {
 	return MapdOut(p.Struct()), err
 }
 
-const schema_b526ba661d550a59 = "x\xda\x94X}p\x1d\xd5u?\xe7^==}\xd8" +
-	"~^\xdd}\xfe\x80\xb8\x02\x12S\xe4\xa2\x04YV\x82" +
-	"\x9c\x10!Kv\x90\xe6\x09k\xf5l\x84=\xf1\x94\xd5" +
-	"\xdb+i\xed\xd5\xeec\xf7>\xc9\x8f\x09cp\xcc\x0c" +
-	"\xa6d\xd2\xa6!1L\x98@\x80\x19\xd2\x02a\xd2\xd0" +
-	"a\x18:%\x1e\xff\xe1\xb8x\xc6d\xda\xce\x940\x85" +
-	"0\xcd\x90\xa6d\x08-\x9e\xa6i\x98\xed\x9c\xbb\xefK" +
-	"\xf2kl\xfd\xa3}\xfb\xbbg\xcf9\xf7w\xce\xbd\xe7" +
-	"\x1c\xdd\xf4\xd9\xf4\xad-}k\xff\xb4\x13\x98u\"\xd5" +
-	"\x1a\x1bG\x0e\xbc\xeb\xab\x17\xee\x03\xe3j\x8c\x0ft\xec" +
-	"\xdf2\xfb\xca\xf5/AK\x1a\xa0\xff\x91\xd4A\x14\xdf" +
-	"O\xa5\x81\xc7?\xf8(7x\xf07\xa7\xefo\"\xf5" +
-	"\x00I=\xaa\xa5\xee\x1c9\xfd\xf5\x99\xeb\xbf\xfb\x00\x18" +
-	"W\xb3\xba\x14`\x7f)5\x8e\xe2d*\x11\x0fZ\x00" +
-	"\xe3\xae\x1f\xe1\xdc\xdc\xd9\xf3\xdfi\xa2\x10;fPd" +
-	";Ha\xef\xcf\xba\xf9hz\xfe\xc9&R\x1f\xb6\x1f" +
-	"D\x91\xd2R\x1e\xef\xbf\xf5\xb73\xadO\x81u56" +
-	"\x88\xa5\xb4\xdc\xcfI\xeeb{\xf2\xc94\x02\xc6\xc7/" +
-	"^\xfcd\xff\x7f|\xf84\x89\xb77\x88\xb7\x92\x8c\xec" +
-	"\xbc\x0aE\xa9\x93~\xde\xdd\xf9A\x0a0\x1e\x1dy\xed" +
-	"\xdc\xf3o?\xf5\xcc%\x9b\xea\xeb:\x8ebwW\x1a" +
-	"@\x0cw}\x0e0\xde\xff\xb4\xf5/7.\x9e\x7f\xa6" +
-	"\x89\xb7\xbb\xbb\x0e\xa28\xd0E\xde\xbe\xf0\xb3\xc2#\x7f" +
-	"\xf6\xec\x07\x7f}\x89\xbe\x81\xae\x9du}{\x01\xe3C" +
-	"/\xbd\xd7\xf7\xf5\x8f\xdf~\xa1\x89\xbe\xfd\xa4\xcf\xd5\xfa" +
-	"\x86\xde\xfe\xd5\xbd\x13\xd7L\xbf\xd8\xd4\xeaL\xd5\xeaY" +
-	"\xcc]\xeb\x16\xfc\x97\x9bH\x0d\x90\xae1-5\xff\xea" +
-	"_~3\xff\xf9\xd1\xbfk\"\xb5\x95t\x0dj\xa9\x13" +
-	"|\xc7;\xf2\x0b\x9f:\xddD*KR=Z\xea\x83" +
-	"\xbf\xf9\xe2\x1b7\xdfr\xf8\xec\xca\xa80\x92Kuu" +
-	"\xa1\xc8\xd2^\xfb\x8d\xaen\x8a\xca\xce\x03\xd3E\xef\x9f" +
-	"\xbe\xfb\x0f\xcdL\x0b2-H\xe9\x85\xe3O\xcc\xfd\xef" +
-	"\x9b\xdf:\xdf\xcc4I\xf5h\xa9-g\x0ao\xbd\xa5" +
-	"\x8e\xbfq\x09\xc5)\xb1\x0bEVh\xb3B'\xc3\xe0" +
-	"\xe0\xabO\xbe\xfe\xd0\x7f\xff3y\xc9W&\xa29\x8e" +
-	"\"kR@\x0c\xf3=\xc0x 7\xfa\xd5S\xd3\xdf" +
-	"z\xb3\x89\xf5\x8b\xe6A\x14\xedY\xb2>\xf8\xd5s\x8f" +
-	"};\xf5\xed\x7f%\x95l\x85\xd8/\xcc](.j" +
-	"\x95\x1f\x9aK\x80\xf1i\xff\xef;\xef8\xf3\xe5\xffj" +
-	"\x16\xe3,\xc5X\xab\xdc\xfc\xf8\xe3c\xed\xefo\xf8\xa8" +
-	"Y\x8cI\xea\x80\x96z\xaa\xa5\xf8\xf1\xe7O|\xed\x7f" +
-	"\x9a\xc5\x98\xa4\xc6\xb4\xd4\xb6_n\xbd\xfd\xbd/\x7f\xf1" +
-	"w\x97\x90\xb35K4g\xc9\xb7\x81\xec1\xc0\xf8{" +
-	"\xd1\xe4\xd9s\x87\x9e\xfa\xdd\x8a\x8d$\x11,e\x8f\xa3" +
-	"8\x99\xd5G:\xab\xa94\xfev\xe9\xc1_\x0f\xcf\xfc" +
-	"\xbe\x89\xf9\xd4\xc6\x19\x14\x9b7\x92\xf9c\xa7^|/" +
-	"\x7f\xea\xc1xeZ\xa0fq\xc3+(\xd6n$\x17" +
-	"\xda7\xfe\x00z\xe3\x82\x0c\xa5\xed}\xa6\xd0R\x8aT" +
-	"\xb0\xf0\x99\x82~|\xba`\x17\xfd\xe2\xce\x11\xfd2%" +
-	"#\x19.J\xee\xec\x98D\\\x8d\xfcM\x97\x93\x9f\xb0" +
-	"\x8b\xce\x98_,\xa9}\xe5\xa2\x04\x98D\xb4^E\x06" +
-	" z\xd98\x00\xae\x13=\xec\x87\x00\x98\x11=\xec{" +
-	"\x00\xb8^\xf4\xb0\xbf\x02@C\xf4\xb0\xd3\x00\xd8%z" +
-	"\xd8\x9b\x00\xd8)z\xd9\x0c\x00\x0a\xd1\xc3\xce\x01\xa0)" +
-	"z\xf5\x13E\x1f\xbb\x07\x00\x99\xe8e\x87\x010+z" +
-	"\xf4\xfb\x06\xb1\x95\xfd\x0a\x007\x8a\x1e\xf6S\x00\xdc$" +
-	"z\xd9\xbb\x00\xb8Y\xf4\xe9\xf7\xab\xc4\x00{\x0c\x00\xaf" +
-	"\x16\x03\xda\xee'\xc4\x80\xd6\xb7E\x0c\xea\xf7?\x12\x83" +
-	"\xda/^yo\x11\x83\xda\x9fn1\xa8\xf5^#n" +
-	"\xd1\xfa\xae\xed\x1ff;\x11\x00Sb\x8c\xbd\x02\x80\xad" +
-	"bL;\xb0V\xecf\x07\x01p\x8d\x18\xd6\x8e]'" +
-	"n\xd1\x1f~R\x0ck\xc5\x9f\xaa<\xb7\xf6\x0f\xb3." +
-	"R\xd0!\xc6\xd8C\x00x\xbd\x18c\xff\x09\x80\x7f\xdc" +
-	"?\xc1\xae\xa3\x85\x1b\xc4~MAO\xff\x01\xc6\x08\xd8" +
-	"\xd6\x7f(\xf9\xf1'\xc2f\xdf\x00\xc0\x1b\x85\xad?\xed" +
-	"\x15\xb6&/]yo\x136;\x0e\x80\xed\xe2\x90~" +
-	"~Z\x1c \x1fb'X\xf2\xbd\xc0v\x00 \x8e\xa4" +
-	"\xdag\x87s\x12U\xceV2\xb4\xbd\xee\xe1BAz" +
-	"\x84\xe7\x8bR:\x98s\x17\\\xb5wv6\x1dI\xb5" +
-	"\x02\x1d\x09\xfc\x8c\x0a\x03-<a\x17GJ\xa9pQ" +
-	"\xea\xf5\x91\xc0\xa7\x05\x88\xa4\xba\xc3\x8d\xdc\xc0\x1f)-" +
-	"[\xe2\xc9G\xb9`.'!\xbd\x98\xd8\xd3\x92,\x11" +
-	"\xd5>\x91K\xc3\x89\x8f\x8dk\x13\xae\x9f,\xdf\x01\x10" +
-	"\x87\x92v\x92\x970\xa4\x94\xeb\xcfEqd/\xca\xbc" +
-	"T\x0a2\xc9\xabT\xbb}{\xc6\x83\xa1\xc4\xfcJe" +
-	"\xfb#\xa9\xd7e>S]\xd6[a\xcb\xd6\x8aR\xa2" +
-	"S\xdb=\xd3\xbboXMW\xbe\xbc-\xf0\x9c\x1c\xb3" +
-	"#\x95\x97\xd2\xd7\xa2$\x89\xaa\x81e\x8d\x8eK\x1e\x1e" +
-	"Y\x09\x0e\x17\xd2\x15\xe25\xca\x12t\x9f\xbb \xf7\xce" +
-	"\xceFR%D\x8c\xcaY\xbb\x84\x9e\xca\xd9\xbe\x9cv" +
-	"\xd3\x8e\x9a\xaf\xb9\x8cU\xde\xba5q1\x11C\xe2X" +
-	"\xf2\x141\xe2\xa6\x89\x10B\xa7d!H-,H\xdf" +
-	"\x91\x8e^\xf1\xe7\"\x8aU\xde\x0b\x96F\x83%\x7fO" +
-	"\x10\xde.\x8f&\x0e\xe42\xb4\xd9\xfa\xde\xf7\x17\x97\xad" +
-	"\xba\xe9\xca*\xed=\xcf\xab{V\xd3\xf3\xae'G\xe6" +
-	"m\x7f\xce\xf5\xe7\xf2r(\x11\xd7\xd6'e\x18\xa1\x1b" +
-	")\xe9+ZH\xc2V\xb0\xfd\x82\xf4F\x03\x18Jr" +
-	"\xb3\x92\x1e\xe3\x11\xf0\xc0\xaf\xbc\xe4\x03\xc8\x94\xc2\x82\xd4" +
-	"A=\xaad\xc8|\xdb\xab\xd1\xbc,\x1d\xf52V\x97" +
-	"\xbbs\xcb\xf6\x90d\xefd\xe8v\x07\xa1\xab\xca5\x9c" +
-	"'j\xc8i9%\xef.\xb9\xa1\x8c\xe84\x14Q\xc5" +
-	"6=U\xbe\x88UkI8&C\x19E\xecKv" +
-	"\xb4/\x18\xaeH,\xb37\xec\x1c.E\x9c\xe8O\xa2" +
-	"\xd9(U\xe7N\x83L\xd5\xb7BQ\x0fxI\xd5L" +
-	"\xb4k\x13{\x17e\x18\xba\x8e\xac\x0bR\xd4F\x02\xdf" +
-	"q\x95\x1b\xac$\xa3z\x02\xf3\xf3\xb6\x13,\x8d\xd8a" +
-	"^\xd9\x0a5{\x1a\xc2\xa5\x89\xc0\x91\xde\x1d\xdb\x93\xad" +
-	"T\xb0/\x15\xa3\\P\xb03\xa4\x90\xe0\xf1(\xf0'" +
-	"\xd1V\xf3{\xbc\xc0\xae\xecZc\xad\xb6\x9a\xdf'\x8f" +
-	"j\x174`\xab\xf9]A\xa3\xc9\x8a*\xd2D\xf1\xc8" +
-	"\x84\xbe\xed]yqI;}\xdbWY\x8d\x06\xaf\xa4" +
-	"\x1a\x91+\x94\xf8{yIQ9\xda\xc4[\x00Z\x10" +
-	"\xc0x\xf4!\x00\xeb;\x1c\xadg\x19\x1a\x88&\x12\xf8" +
-	"\xcc8\x80\xf54G\xebE\x86\x06c&\x15/\xe3\xf9" +
-	"m\x00\xd6\xb3\x1c\xad3\x0c\x0d\xceM\xe4\x00\xc6\x8fI" +
-	"\xf25\x8e\xd6\xeb\x0c\xb1\xc5\xc4\x16\x00\xe3'\xc7\x01\xac" +
-	"\xb3\x1c\xad\x7fdh\xa4\xd0\xc4\x14\x80\xf1\xc6\x0c\x80u" +
-	"\x81\xa3\xf5\x16\xc3\xdae\x8c\x93a0G\xa1\xa6*X" +
-	"\xef\x1a\x00q= \x91\x9e\x9cR*)\xc0p\x0d`" +
-	"\xa6h\xaby\\\x078\xc9\x11\xd7\xd7{0@\x02\xe3" +
-	"b\x10\xe9\xa4\x00\xad\xaf\xd6NU\xf4yAP\x9c\xb2" +
-	"\x95\xc4\xe1E\x19\xdas\x12\xb0\x03\x18v4\xac@z" +
-	"\xc2\xf5kh\x95T\xfe\xff\x90Z%\xd3\xab\x92)~" +
-	"\xc1v\x01\xe4\xdfa\x1c\xf3\xef\xb3:\x9f\xe2\xdf\xd9N" +
-	"\x80\xfc\xbf\x11\xfe\x1bV\xa7T\xfc\x9a\xfa\x81\xfc\xfb\x84" +
-	"\xff\x961\xc4\x84Tq\x91\xaah\xfe#\xc6q\x8a3" +
-	"4ZP\xf3*>\xa6\"\x9b\xff=I\xb7\x11\x9eb" +
-	"\x9aZ\x91\xe2\xaf\x00\xe4\xdb8\xc7\xbcIx+7\xb1" +
-	"\x95ZONV\xd7\x10\xbe\x89\xf0t\x8bI\xbd\x92\xc8" +
-	"rRo\x12~\x0d\xe1m\xdc\xc46\x00\xb1\x85?\x06" +
-	"\x90\xbf\x86\xf0\x1b\x09oo1\xb1\x1d@\xf4\xf0\x10 " +
-	"\x7f\x03\xe1;\x08\xefH\x99\xd8\x01 \xfa\xf87\x00\xf2" +
-	";\x08\xbf\x95\xf0\xceV\x13;\x01\xc4-\xfc\xa7\x00\xf9" +
-	"Q\xc2'\x09_\xf3\x8e\x89k\x00\xc4\x84\xf6\xe76\xc2" +
-	"\xf7\x11\xbev\x8b\x89k\x01\x84\xc5\xb7\x03\xe4s\x84\xdf" +
-	"I\xf8\xba\x9f\x9b\xb8\x0e@\xec\xd7~\xee#\xfc.\xc2" +
-	"3m&f\x00\xc4!~\x0e \xef\x10^$|}" +
-	"\xbb\x89\xeb\x01\xc4\x02'~<\xc2\x8f\x12nt\x98h" +
-	"\x00\x88\x92\xde\xd7Q\xc2O\x10\xde\x951\xb1\x0b@\xdc" +
-	"\xcfg\x00\xf2\xf7\x11\xfe0\xe1\xa2\xd3D\x01 N\xf2" +
-	"\x1f\x02\xe4\x1f&\xfc\x14\xe1\xe6\x1a\x13M\x00\xf1\x08\x7f" +
-	"\x08 \x7f\x8a\xf0\xa7\x09\xcf\xae51\x0b \x9e\xd4\xfc" +
-	"<A\xf8s\x84o\xf8\x84\x89\x1b\x00\xc4\xf7\xb5\xfcs" +
-	"\x84\xbfL\xf8\xc6wM\xdc\x08 ^\xd2\xfe\xbcL\xf8" +
-	"\x19\xc27m1q\x13\x80\xf81?\x0c\x90\x7f\x8d\xf0" +
-	"\xd7\x09\xdf\xdcf\xe2f\x00\xf1\x13\xcd\xcf\x19\xc2/\x10" +
-	"~U\xca\xc4\xab\x00\xc4y\xed\xe7\x05\xc2\xdf\xe2\x0c\x8f" +
-	"-\xd9\xe5\xdb\xed\x05Y=+CKvyJ\xceV" +
-	"_\xe30\xb0\x1dZo8NqT\xb9;\x81\xbb\xaa" +
-	"\x96\xf7~\xa5\xd0\xc1Pr\xad^\xb2\x80\x09>\xea\x0e" +
-	"E\x8aJXU`h\xde\xbe\xc7\x0e\x9d\x9av\x92\xbf" +
-	"\xcd\xbe\xc7\x06\xde\x04\xc4\xd0\x19u\xe9{^W\x10\xdb" +
-	"\xce\xa2\x1b\x05a\x19\xba\x93\xa2\xd5hy\xd8Yt\x91" +
-	"\x16\x13\x17.Yc\xd5\xb5\x8a\xde\x02\xd6\x1d\x0b|9" +
-	"m\x97\x11\x81!\x02v{\xb6/#l\x05\x86\xad\x80" +
-	"\xb1r=\x99\xa3\xd6\x90K\xa7*Rc\x86\xb9*_" +
-	"\x9a\x9b\x93\x91\x92\x8eV\x0e\xf5k#\xaa,\xc0\x90\xb3" +
-	"\xdc]\x19)w\x81n\x1ag*\xb0\x9di\xd7\xe1j" +
-	"\xbe\xb6Hq\xa0J\x05iyTa\xa6>\xd6\x03b" +
-	"\x060v\xdc\x0a\xab{\xc2`a\xda.\x8ftK\x9f" +
-	"\x0a{\xf5\xfb\xc5Js\x89\xd5\xee\xb2\xc1\xa3\x05\xea\x89" +
-	"\xc2E\xb9\x92\xbf%\xbb\x9c\x97\x9e, \xdd\x8e\xc9T" +
-	"\x82\x99\xfa@W\xb1\\\xdd3\xbaIuV\x8d\x84\xcc" +
-	"\xbbs\xf3Kvy\x042\x9e\x1dE\x98\xa9\xcf\xca\xc9" +
-	"\xd7\xddKvy\xcc\xc1\x140L\xd1\xe5Y)\xd0+" +
-	"\xfa\x91Z\x1aT/\xd7T\x93\xcb\xb5^\xd1\x93.P" +
-	";L7m\x9b.D\xc6N2i\xb4\xef\x02\xa0\x04" +
-	"Tn\xe1XQ\x86\x05\xe9\xab\xd5\xd4\xcd\x81\xc6\xba\xd9" +
-	"\xec\x8a\xa7\xd0\x8d\x0c\x05\xbe\x92G\xf55\xbfF\x1b\xdf" +
-	"\xb2K\x1b\xcfn\xa3\x01\xccX\xbb\x0b\xe0\xd8l(\xe5" +
-	"\x92]\xce\x14\\U>V\xf2\x8f\xf8\xc1\x92\xbf\x1aO" +
-	"\xfaVU\xf1\xd3N\xdfj\x07\xd6\xcf\xad\xd6\xc0\xc0j" +
-	"?\xe8\xbb\x1c\x99z$F\x9fx4k\xbd\xc7\xbd\xd4" +
-	"Q\x1c\xe5h\x9dh\xe8=\xee\xdf\x0e`}\x85\xa3\xf5" +
-	" C\xac\xb4\x1e\x0f\\\x07`\xdd\xc7\xd1z\x98Z\x8f" +
-	"\xf5I\xebq\x92\xbe>\xc1\xd1\xfa\xf3z\x8d4\xbeF" +
-	"\xfd\xc8\xc3\x1c\xadS\x0c3\xaa\\\x94\x98\xa9\xffs\xb1" +
-	"\x92\xa8\xb3\xd4\xd4U\x8fF:Ra\xad\xbb\x98\x09\x02" +
-	"\xaf\x96\xf0\x87+\xfd]\xe3}\xb9\x1aNnZ-\x89" +
-	"\xfd\x97\xfb\xe0\xb6\xca\x11\xa4\x03\xa8\xcf\xc3\xcd\x9a\x9d'" +
-	"\x93\x94|t\\\xa7\xe4#4ys\xe3/\xb6\xd3\x04" +
-	"o\x9c\x9c\xa29\xddx\x80DZ\x8d{ghR6" +
-	"\xca\x04\xb6\x19\xa5\x90\xe6d\xe3n\xfa\xae\xc3X\xa0\xef" +
-	":\x0d\x97\x1ek\x0cI\x92k\x0d\xfb0@-\xa5\x17" +
-	"\x02\x15\x84Kv\x99F\xe8\xda\xefL\xce\xf5\x8ft\xab" +
-	"\xb0\xe4\x1f\x89\xf5\xdf\x9c\xeb\x03\x1e9V\x0c\xdd\x05;" +
-	",\xc7\x95g\x0e\xd2\xaeO\xe3\x1f\xdd\x0bv\x08X\xae" +
-	"\xff\xee.\x93\x8eX\xc9P\xb9v\xa8\xd5\xd7~k\xf5" +
-	"q\xc9/\xd0\x9e]\xc8\xcc\xba\xd2\x89C\x19\xb9\x8e\xa4" +
-	"\x1b\xd4\xb5\xbd\xd8s\x17i\xdcR\x90\x09\xa5T\x97m" +
-	"\x85)\xa4\x93\x81\xeb\xab\xe4\x1f3\xebk\xd9hS\xea" +
-	"\xdc\xc5\xd1\xf2\x1a\xb2\xd1\x9d\x02\xb0\xe69Z\x8a\xda\xb6" +
-	"\x96$\x1d\xef&\xb0\xc8\xd1\xfa\x0a\xa5c*I\xc7\xf2" +
-	"=\xf5d\x8e=[\xb9\xaa\xe4\xe8Z\xdb\x09\x0c;u" +
-	"\x93\xe9\xcf\x11\x08(kX\xa1\x14.\xda\xaa\x14B\xbd" +
-	"V\xc5*\x99\xf3%\x0cy\x01])\x97\xf4\xa3Wp" +
-	"\xe2\xfb\xafd(\x98\xacv\xcb\xfaj\xad\xf1\xd0C<" +
-	"\xdc\xc0\xd1\xda\xd1\xc0C\x1fm\xf9&\x8e\xd6\x17V\xb3" +
-	"\xbb+wx\xb5S\xcf\xcd\xab\x94\xff\xec\xe5\xe4\xa7+" +
-	"\x15R\x17\xc8t\xb9(\xf5U\xa5\xc3=\x90\x9c\xaf\xde" +
-	")}\xbe\x88\x1e\xe4\xc6\xd6q}\xbe\xae\xdd\x06p\xac" +
-	"P\x0aC\xaa>\xc5P:nAI@\x87F\x91\xc8" +
-	"\x9d\xf1\x88\xa3XV\xa6/\x00\xc8\xcc\xda\xae\xf7\x07\x8b" +
-	"\x1fEf\xb42\x1f\xd5\xc6\xa3\x153\xdbN\x00\xeb\x9b" +
-	"\x1c\xad'\x1a\"\xf4\xf8T\xc3 \xc70q\xfd\x99\x83" +
-	"\x0d\x83\x1cgI\xa6>O\xf3\xd9s\x1c\xad\x97\xeb3" +
-	"\xdbK\xf4\xf5\x8f8Z\x17\x1af\xb6\xf3$\xf8:G" +
-	"\xeb\x97\x0c\x87\xec\x82r\x17e\xedvL\xfew\xe1\xd1" +
-	"^k\x98\x0a\x94\xed\xedq=\xe02\xc26`\xd8\x06" +
-	"\x0d\xb3\x9et\xf6\xb8\x9e\x8c\xa0\xb6\xe2U\xe6d\xc0\xa8" +
-	":\xd2\xd1=\xbb\xaea\x09G\xa5\xb2]/\x82\xfa\xcc" +
-	"W\xfb7pe\xe6[\xcd\x0d\xbb,\x0b.G~u" +
-	"\x8c\x1f\x1dJ|\xd0=@-\x06\xbb\xe9\x94\x8cr\xb4" +
-	"&\x19VC0Al\xe78Zw6\x84`?\x91" +
-	"\xb8\x8f\xa3u\x17k\xd8rCMY\x1dk\xff\x17\x00" +
-	"\x00\xff\xff\x80\xa7\xaf\xe3"
+const schema_b526ba661d550a59 = "x\xda\x9cy\x7fp\x1c\xc5\x95\xff{=\xbbZ\xc9\x92" +
+	"\xbc^\xf7Z\xfe\xa9\xaf\xb0\x03\xf9\x82\x0f\x07\xdb2\x17" +
+	"\xec@\xd6\xb2\x84c\xab$\xd0j$\x1b\xbbp\x1d\xa3" +
+	"\x9d\x964\xf6hf5\xd3+y}\xb8\x8c\x1d\xbb\x0a" +
+	"sP\x9c\xb981T\\\xb1\x03\xae\xc3w\x84\x1f9" +
+	"\xb8\"\x14\xa9\xe4\\\xbe*\xf0A\x15\xa4rW\xc7\x8f" +
+	";~\\\xae 9R$wp\x97p\xa1\xe6\xea\xf5" +
+	"\xec\xce\xac\xe5M\xb0\xf8gg\xe7\xd3\x9f~\xfd\xfa\xf5" +
+	"\xeb\xd7\xaf\xdf\xac\xde4gcbM\xeb\xc2V`\xf9" +
+	"\x13\xc9\x86 \xb3g\xc7\xbb\x8e|\xfc.\xc8,\xc5`" +
+	"\xc7\x9c\xe1\xf6\xd1\xe7\xbe\xf8\x0c$R\x00\x9d\x93\x0d;" +
+	"\x91\x1fiH\x01\xf0\x83\x0d)\xc0\xe0\x89\x8f\xfa\xd6\xef" +
+	"\xfc\xd5\xb9\x83u\xb8\x82\xb8e\xc5-)\xeem\xdd\xe7" +
+	"\xee\x1f\xf9\xe2w\x8e@f)\x8b\xb9\x80\x9d;\x1az" +
+	"\x91O\x10\xb3\xd3j\x08\x12$\xf6\xc2\x9f\xdc\xb5\xb6i" +
+	"\xe7\xbd\x90_\x8a5\xdc$\x12\xc7h\xde\x84|\xb2\x99" +
+	"\x04O4?\x01\x18\xcc\x7f\x1a\xc7\xc6^x\xf9\xdbu" +
+	"\x94hm\x19A\xbe\xbc\x85\xb8\xed-\xa4\xc4\xaa7:" +
+	"\xb4\x9e\xd4\xf8\xe9:\\l\xd9\x89|\x81\xe2f\x14\xd7" +
+	"\xd6:7\xfef\xa4\xe1a\xd2B\xab\xd1\xa2\x91\xd8\x1f" +
+	"7\xefD\xdeD\xec\xced\xcb_2\xc0\xe0\xd0\xc7\x1f" +
+	"\x7f\xa1\xf3?~\xfd\x08\xd1\x9bj\xe8jb\xab\xd2K" +
+	"\x90\xdf\x94\xa6\xbf\xeb\xd3\x1f&\x01\x83\x9e\xee\x1f_\xf8" +
+	"\xde[\x0f\x9f\xb9\xc4\x1c\xbf]p\x08yk\x1b\xe9\xd1" +
+	"\xd4\xf6e\xc0`\xf8\x91\xfck\xd7N\xbd|\xa6\xde\xfc" +
+	"\xdav\"_\xae\xb8\xedm\xa4\xf3\xe3o\x14\x8e\xff\xd9" +
+	"\xa3\x1f\xfe\xf5%R\xb1m\x03\xf2\x8cb\xb6\xb6\xdd\x0a" +
+	"\x18\xecz\xe6\xbd5\xf7\x7f\xfa\xd6\xe3u\xa4.'\xa9" +
+	"\xd7+\xee\x1a%5\xf7\xd6/\xf6\xf7_\xb1\xfd\xc9:" +
+	"\xdc\x05m#\xc8\xafQ\xdc\xab\x14\xf7\x05\xec[n\x15" +
+	"\x9cg\xebp\x9bHn\xbb\xe2.V\xdc\xf3\xff\xb6\xf2" +
+	"\xa33#\x9f<7\xd3\xc2\x1a\xb1?]\xb0\xaf\xa2q" +
+	"gk\xdbv\x04\x0c\xc6\x9f\xff\x8bo\xe8_\xe9\xf9a" +
+	"\x1d\xd1\xd6\xc2\x11\xe4\xfb\x17\x92\xe8\xf2B\x12}X[" +
+	"\xf7\xb6\xb8\xf1\xcasu\xb8\xbb\x88;\xa9\xb8\x13\x8a\xfb" +
+	"\xe1\xdf|\xf5\xd5\x1bn\xda\xfd\x02\xa9QCN2b" +
+	"\xe7\x17\xceGn,\x0c;v\x90\x1a\x1bvl/\xda" +
+	"\xff\xf8\x9d\x7f\xa8\xb7A\x16\x8d ?\xb2Hm\x90E" +
+	"$\xfa\x95C\xa7\xc6\xfe\xf7\xf5o\xbe\\\x87+\x88[" +
+	"V\xdc\x92\xe2^y\xaey\xd7\x9f>T~e\xa6\xd7" +
+	"+5v,\xda\x80\xdcRl\xb1\xe8=\xc0\xa0\xfd|" +
+	"\xe1\xcd7\xe5\xa1W/Y\xe9\xad\x8b7!\xdf\xb1\x98" +
+	":\x0d/Vv[\xbf\xfe\xf9\xd3/\xdd\xf3?\xff4" +
+	"\xc3\xccJ\x8b\x9b\x97\xf4\"\xdf\xb1\x84\xe4\x0e/!\xb9" +
+	"\xd7\xf7\xf5|\xfd\xc4\xf6o\xbe^G\xe3\xf5Kw\"" +
+	"\xef_J\xdc\xadKI\xe3\xfbG\x9e|\xb1K\x9cx" +
+	"\xa3\xae\xc6\xab\x96\x8e \xef\"v\xe7MK\x95\xe1\xd6" +
+	"\x7f\xfd\xc2C\xdfJ~\xeb_g\xd0\x95\xec\x1d\xcb6" +
+	"!\xb7\x96\xa9\xf9-\x9b\x06\x0c\xfe\xfb\x81s77\x0f" +
+	"\xaf~\x7f\xe6\xa2(\xdfxq\xd9>\xe4\xff\xa2\xd8\xaf" +
+	"-#\xad\xcf9?j\xdev\xfe\xf6\xff\xaa\xa3\xf53" +
+	"\xed;\x91\xbf\xd8N\xdc\xbfo'\xad\x17\x9f<\xb9\xb5" +
+	"\xe9\x83\xb6\x8f\xeap\xcf\x12\xf7\x87\x8a\xfb\x03\xc5}8" +
+	"Q\xfc\xf4+\x87\xef\xfbm\x1d\xeeI\xe2>\xa5\xb8\xdf" +
+	"S\xdc\x95\xef_u\xcb{\xb7\x7f\xf5\x93KV\xe4X" +
+	"\xfb\x08\xf23\x8ay\xba\xfd\x00`\xf0]\x7f\xe0\x85\x0b" +
+	"\xbb\x1e\xfe\x84\xe6\xd6P3\xb7$\xc9}\xa7\xfd\x10\xf2" +
+	"\x8f\x89\xdd\xf9\xeb\xf6\x1fi\x80A\xe6o\xa7\xef\xfee" +
+	"\xd7\xc8\xef\xea(1\xbcb\x04\xb9\xb5B\x99m\x05)" +
+	"q\xe0\xc4\x93\xef\xe9'\xee\x0ef\x9aM\x85\xce\xad+" +
+	"\x9eCn(\xf6\xae\x15O\xc0\xaa\xa0 <a\xd8\xd7" +
+	"\x15\x12%_\xba\x13\xd7\x15\xd4\xe3K\x05\xa3\xe8\x147" +
+	"t\xab\x97A\xe1\x0boJh\xe6\xba\x01\xc4\x01-1" +
+	"\x9b.\xab/\xa3K\xbfQ4\xb7:\xc5\x92\x1c*\x17" +
+	"\x05\xc0\x00b\xfe\xa7\xc8hO\xb0^\x00\x9c\xcb'\xd9" +
+	"\xf7\x010\xcd'\xd9w\x01p\x1e\x9fd\x7f\x05\x80\x19" +
+	">\xc9\xce\x01\xe0|>\xc9^\x07\xc0f^b#\x00" +
+	"\xc8\xf9$\xbb\x00\x80Y^RO\xe4e\xb6\x0f\x00\x19" +
+	"/\xb1\xdd\x00\xb8\x80O\xaa\xf76>\xc1~\x01\x80\x0b" +
+	"\xf9$\xfb\x09\x00.\xe2%\xf6.\x00.\xe6e\xf5\xbe" +
+	"\x84\xefg\x0f\x01\xe0R\xbe_\x8d\xbb\x8c\xefW\xf2\xda" +
+	"\xf9A\xf5\xfe\xff\xf8A\xa5\x97VyO\xf0\x83J\x9f" +
+	"\x0e~P\xc9\xbd\x82\x1fQ\xf2\x96w\x1ee\x1b\x10\x00" +
+	"\x93\xfc\x18{\x0e\x00\x1b\xf81\xa5@+\xbf\x8f\xed\x04" +
+	"\xc0\x16~T)\xb6\x82\x1fQ\x1d\xbf\xc0\x8f*\xc1W" +
+	"V\x9eWu\x1ee\xf3\x11\x00\xe7\xf0c\xec\x1e\x00\xfc" +
+	"\"?\xc6\xfe\x13\x00\xff\x7f\xe7q\xb6\x82\x1a\xae\xe6'" +
+	"\x95\x09\xae\xe9<\xcd\x18\x01+;\xcf\x84\x7f\xfe\x88\x9f" +
+	"e\x0f\x00\xe0\xb5\xfc\xac\xea\xba\x8a\x9fU\xc6KU\xde" +
+	"\x1b\xf9Yv\x08\x00\x9b\xf8\x19\xf5\xfc\x12?\xadt\xb8" +
+	"\xae\xf2\xbe\x9a\x9fV:\xae\xe1'\x95\xcek\xf9\x83\xaa" +
+	"\x7f'\x7f\x90y\x00\xb8\x8e\x1f'\xdd\x03\xd3\x9dvl" +
+	"\xd70\x01 \xf0\x85\x1c2\xbc1\x81\xb2\xcf\x90\xc23" +
+	"\xec\x8e\xaeBA\xd8\x84\xebE!L\xec\xb3&,y" +
+	"\xeb\xe8h\xca\x17r\x06\xda\xed:i\xe9\xb9\x8a\xdco" +
+	"\x14\xbbKIoJ\xa8\xf6n\xd7\xa1\x06\xf0\x85\xdcf" +
+	"\xf9\x96\xebt\x97.j\xd2\xc2N}\xeeX\x9f\x80\xd4" +
+	"T8\x9eb\xb2\x90\xaat\"\x95\xba\x00f\xb6\xf5[" +
+	"N\xd8\xbc\x0d \xf0\x04\xcdD\x17\x90\x93\xd2r\xc6\xfc" +
+	"\xc07\xa6\x84.\xa4\x84t\xf8*\xe4\xcd\x8e1bC" +
+	".\x1c~\xa6\xb0a_\xa8v\xa1\xa7\xab\xcdj*\xec" +
+	"\xa2\xb6\xa2\x10hF\xb3gj\xf65\xad\xa9J\xcf-" +
+	"\xaem\xf61\xc3\x97\xba\x10\x8e\xa2\x12\x13e\x8d\x95\x15" +
+	"\xda+4o\xcfL\xb0\xab\x90\xaa\x18^\xa1,D\x87" +
+	"\xac\x09q\xeb\xe8\xa8/dh\x88\x1e1j\x94\xd0\x96" +
+	"}\x86#\xb6[)S\x8eG*c\xd5n\x1d\xcap" +
+	"\x01\x19\x86\xe8X\xb2%Y\xc4J\x91A\x08\x1d\x14\x05" +
+	"791!\x1cS\x98\xaa\xc5\x19\xf3i\xadt\xdb\x9d" +
+	"\xeeq\xa7\x9d\xcd\xaew\x8b\xd8\x1b*\xd0\x97\xa6\xc9\xc6" +
+	"s\x1f.^\xd4j\xa5*\xad4w]\xab\xceYn" +
+	"\x1f\xb7l\xd1=n8c\x963\xa6\x8b\\HW\xa3" +
+	"\x0f\x08\xcfG\xcb\x97\xc2\x91\xd4\x10.[\xc1p\x0a\xc2" +
+	"\xeeq!\x17\xfaf\xc5=z}\xd0\\\xa7\xf2\xa2\xbb" +
+	"\x90.y\x05\xa1\x16u\xaf\x14\x1es\x0c;2\xf3E" +
+	"\xee\xa8\x9a\xb1\xda\xdc\xd1w\xd1\x1cB\xef\x1d\xf0\xac\x0e" +
+	"\xd7\xb3d9\xc2\xb5P\x0c)-\x06\xc5d\xc9\xf2\x84" +
+	"O\xbb\xa1\x8820\xe8)\xf5\"VG\x0b\x97c\xc0" +
+	"\x13\xbe\xcf\xbef\xf8CnW\x85q\xd1x]\xe6\xee" +
+	"\x92\xaf\x91\xf9\xc3\xd5\xace\xc5\xb6S \x93\xf1Th" +
+	"\xd5]\xad$\xa3!\x9a\xd4\x10\xb7N\x09\xcf\xb3L\x11" +
+	"\x13i\xd5\xba]\xc7\xb4\xa4\xe5\xce4Fu\x07\xea\xe3" +
+	"\x86\xe9Nw\x1b\x9e.\x0d\x89\"\xa8B8\xdd\xef\x9a" +
+	"\xc2\xde\xb6\x16\xa0\x06\xfbZ\xd1\xefs\x0bF\x9a\x04\x12" +
+	"\xdc\xeb\xbb\xce\x00\x1ar|\xb3\xed\x1a\x95Y+\xac\xc1" +
+	"\x90\xe3Cb\xaf\x84*`\xc8\xf1Mn\xed\x90\x15Q" +
+	"$\x89\xd6#\xed9\x86\x1d\x14\xc6Ea\xcff\xd7c" +
+	"\xc3E\xd3\x90\xc2\x87\x92z\xf6\x1bZ\xd1\x8f#Sn" +
+	"\xd00\xad\x92\xda\xbe\xfd\x82\x96R\x05\x0eG\x14\"\xbd" +
+	"\xfa\x8d\xa2\xeeB\x07\xf9\x83\x1f\x98\xc2\x16R\x0c\x0aH" +
+	"\x8fQ\xf3g\x1d`7OY\x05\x92\xa3\x8e\xaf\x16-" +
+	"\x01\x90@\x80\xcc\xcd+\x01\xf2\x1b5\xcc\xf71D\xcc" +
+	"\"a[\x07\x01\xf2[4\xcc\x0f1\xcc0\xcc\"\x03" +
+	"\xc8\xe4\x09\x1c\xd00\x7f;\xc3\xf4\xa8e\x0bl\x01\x86" +
+	"-\x80\x81o\xed\x13\x9b\xcaR\x00\xfa\xd8\x04\x0c\x9b\x00" +
+	"\x03A\xa3\x09\xb3\x0bPb\x12\x18\xd2m\xe2\xb2O\xe5" +
+	"\x94\xb9f\xed\xecO\xf2\xf5\x97y\x92\xd3\xb2P\x10\xb8" +
+	"U+I\xb2\xc5\xea\xaa-x\x17\xde\x03\xa0\xf7\xa0\x86" +
+	"\xfa\x002\xccT\xec\xc1\xfb\xb1\x17@\xef#\xfc6\xc2" +
+	"\x19S&\xe1\xc3\xb8\x12@\x1f \xdc&\\\xd3\xb2\xa8" +
+	"\x01pK\xf1\xc7\x09\x97\xc8\x10\x13YL\x00\xf0I<" +
+	"\x04\xa0\x17\x09\xbe\x93\xe8I\xccb\x92.\x038\x02\xa0" +
+	"\xef%\xfc0\xe1\x0d\x89,6Pv\xae\xd49L\xf8" +
+	")\xc2S\x98EJ\x8bN\xe2C\x00\xfa)\xc2\x1f#" +
+	"\xbc\x91e\xb1\x11\x80\x9f\xc5}\x00\xfa\xa3\x84?Mx" +
+	"S2\x8bM\x00\xfc)\x1c\x04\xd0\x9f$\xfc%\xc2\xe7" +
+	"4dq\x0e\x00\x7f\x11=\x00\xfd\x05\xc2\x7fFxs" +
+	"*\x8b\xcd\x00\xfc\x1d\xdc\x04\xa0\xbfI\xf8\xfb\xc80r" +
+	"P\x1c\xf0\xdc1\xda\x98\x94\xe3\xc4\xf9! \xce#/" +
+	"\xa8\xc6T\x80\xaag\xa4\x8b\x86\x1c\xc7\xb9\x80\x03\x1a\xe2" +
+	"\xbc8\xc5\x07$0(\xba\xbe\xda\xc2\xa0\xe4E\x89w" +
+	"E\x9e\xed\xba\xc5AC\x0a\xec\x9a\x12\x9e1&\x00\xe7" +
+	"\x00\xc395-\x90\xea\xb7\x9c\x08\xb5\x1c_\x1a\xb6-" +
+	"\xd0\x1c\x14\xb4!H\xcbh\xe8\xe8\x12W\x19:&\xeb" +
+	"\xa1\xf3j\"\xf6]\xd3\xf2\xf7\xe4K\xae\x84\x9cAn" +
+	"=\xc3\xa9Ia\xf4c\xd1Q\x1d\xa0\"\xda\x11\x867" +
+	"R\x1e\x14\xd0\xa1\xb4\x88\x89\xd1E$$\x1e\x980\x8a" +
+	"=\x864p^|\xa9\xaaL\xbd\xea\xc1\xda\xef\xf1\xe0" +
+	"\xaa\xe7\xda\x91\xe7\xfe;\xa3%{\x9bi\xa8\x7f\xc0j" +
+	"<\xf7\xe7l\x03\x80\xfe3\xc2\x7f\xc5j<\xf7\x97\x94" +
+	"\xb8\xea\x1f\x10\xfe\x1b\xc6\x10+\x8e\xfb1\xa5R\xfaG" +
+	"L\xc3A\x8da&\x81\xa1\xe3~J\x99\x95\xfe;b" +
+	"7\x12\x9ed\xa1\xe3&\xb5\xe7\x00\xf4FMC=K" +
+	"x\x83\x16:nF\xa3Q[\x08_Dx*\x11:" +
+	"\xee\x02\x8d\xc4g\x09\xbf\x82\xf0F-t\xdcv\x8d\x1c" +
+	"\xfa\x0a\xc2\xaf%\xbc)\x11:\xee5\x1a9\xe8\xd5\x84" +
+	"\xaf#|N2t\xdc5\xda\x03\x00\xfa:\xc27\x12" +
+	"\xde\xdc\x10:\xeeM\xdaOh\xff\x12>@x\xcb\xdb" +
+	"Yl\xa1\xfd\xab\xf4\xd9B\xf8\x10\xe1\xad\xedYl\x05" +
+	"\xe0ym-\xedk\xc2o#|\xee;Y\x9cK\xfb" +
+	"Z\xe99D\xf8\x1d\x84\xa7\x1b\xb3\x98\xa6\xfb\x88v\x01" +
+	"@7\x09/\x12>\xaf)\x8b\xf3\xe8\x86\xae\x91}l" +
+	"\xc2\xf7\x12\x9e\x99\x93\xc5\x0c]\x0f\xd4\xbc\xf6\x12~\x98" +
+	"\xf0\xf9\xe9,\xce\xa7\x8d\xad\xd1\x86\xbf\x8b\xf0{\x09\xe7" +
+	"\xcdY\xe4\x00\xfc\xa8\xf6}\x00\xfd^\xc2O\x10\x9em" +
+	"\xc9b\x16\x80\x1f\xd7(\x10\x9c \xfc\x11\xc2\x17\xb4f" +
+	"q\x01]\xd4\x94}N\x11\xfe\x18\xe1m\xcb\xb2\xd8F" +
+	"\x81@\xf1\x1f#\xfcY\xc2\x17\xbe\x9b\xc5\x85\x00\xfc\x19" +
+	"\xa5\xcf\xb3\x84\x9f'|Q{\x16\x17\x01\xf0\xbf\xd3v" +
+	"\x03\xe8?&\xfc%\xc2\x177fq1\x05\x08e\x9f" +
+	"\xf3\x84\xbfB\xf8\x92d\x16\x97\x00\xf0\x97\x95\x9e\xaf\x10" +
+	"\xfe\xa6\xc6\xf0\xc0\xb4Q\xbe\xc5\x98\x88\x0e\x84\xdc\xb4Q" +
+	"\x1e\x14\xa3\xd5\xd7\xc0s\x0d\x93\xdak\"C\xe0W\x0e" +
+	"m\xd0,\x19ma\xa7\x92aA.<\xcf/i\xc0" +
+	"\x10\xef\xb1r\xbe\xa4\xdc\xa9J\xc8\x8d\x1b\xfb\x0c\xcf\x8c" +
+	"\xa4\x13\x7f\x8b\xb1\xcf\x00\xad\x0e\x88\x9e\xd9cQ\x7f-" +
+	"\x16\x10\x18\xe6\x94\xe5\xbb^\x19:\xc2l\xa9v\xe4." +
+	"s\xcaBj\x0cU\xb8\xa4\x8dU\xdb*r\x0b\x18+" +
+	"\xe6:b\xbbQF\x04\x86\x08\xd8a\x1b\x8e\xf0\xb1\x01" +
+	"\x186\x00\x06\xd2\xb2E\x1f\x9d\xfc\x9a0\xab\x94\xc82" +
+	"\xcc\x92zilL\xf8R\x98J8\xc4\x11\xd0\xaf4" +
+	"@\xce\xbcX]\xe1Kk\x82\x82\xa69\xe8\x1a\xe6v" +
+	"\xcb\xd4\xe4x\xd4H\xeb@)\x12\xa4\xc4^\x89\xe9\xb8" +
+	"\x0e\x07\x88\xe90\xee\x85V\xdd\xec\xb9\x13\xdb\x8drw" +
+	"\x87p(\x0d\xa9\xf6\x9f\xaa\xdcj\xb0z\xad\xa9\xd1h" +
+	"\x82\x92qoJ\xcc\xb4\xdf\xb4Q\xd6\x85-\x0aHq" +
+	"3\xbc>c:\xaeBTF\xae\xce\x19\xad0-\x94" +
+	"\xb5\x06\x19\xb7\xc6\xc6\xa7\x8dr7\xa4m\xc3\xf71\x1d" +
+	"W\x95\xc2\xde\x1d\xd3Fy\xab\x19g\x19\x95\xccpF" +
+	"\"\x1c\xb9A5\xb8&\xeb\x04\xd78\x95\x0c\xaf\x1fJ" +
+	"a\x8a\xb4\x8d*\x05\xcal\x00@\xcc4m\x02 \x07" +
+	"\x94V\xe1@Qx\x05\xe1\xc8\xd9\xe4)\xd7\xcf\xc8S" +
+	"\xeaEyZ\xbd\xee\x9c\xebH\xb1WE\xfa\x165~" +
+	"\xfb&5\xfe\x82\x95\x00\xc82\xad\x9b\x00\x0e\x8czB" +
+	"L\x1b\xe5t\xc1\x92\xe5\x03%g\x8f\xe3N;\xb3Q" +
+	"f\xcdl\xf3\xac\x94\xb9\xe6sTY\xbe|\xd9U\x96" +
+	"\xf0\\6s\xe1!Ns_\x14\xe5\xaa\x0fR\xae\xfa" +
+	"\x0d\x0d\xf3\xa7\xe2#.s\xb2\x17 \xffm\x0d\xf3\x8f" +
+	"2\xc4\xf0x\xcb\x9cY\x0b\x90?\xa5a\xfe1\xca\xca" +
+	"P\x1dn\x99\xb3\x94\xc0>\xaaa\xfei:\xda\x98:" +
+	"\xda2O\x8d\x00\xe4\x9f\xd40\xff||\xaee~@" +
+	"\xe0\xb3\x1a\xe6\xcf3L;5\x91-\x18-\xd9\xf6\x8c" +
+	"P\xd6A\xb9\xb0\x8f\x8d\xc0\xb0\xf1\xf7$\xc3\xa6!\x8d" +
+	"m\xc2\xf3!e\xb9N$\xab\x9a\x83@\xca\xec\xfa|" +
+	"I\xf2\xf5\x9fc\xf1\xd6\\\x86\xf7\xa9\x95@e\xfcl" +
+	"d\xfc\xfdd\xfc\xbd\x1a\xe6\x0f\xd7\x18\xff \x19\xfaN" +
+	"\x0d\xf3w\xc7\xc6?\xb2\x02 \x7f\x97\x86\xf9{\xc9\xf8" +
+	"\xf3B\xe3\x1f\xa5\xde\x875\xcc\xffy\x9cWd\xee\xa3" +
+	"\xa5\xbbW\xc3\xfc\x09\x86iY.\x0aL\xc7_`*" +
+	"\x9b{\x94n`\xd5p\x92\xf2\xa5\x17%\x97#\xaek" +
+	"GAbw\xe52V{\xc6\xcc\xc6,\xab?\x87)" +
+	";/\xd3\x94\xfd\xb90\xd3#{\xce\x8b\xeci\x90E" +
+	"n\xd70?N\xf6L\x84\xf6\x14\x1e@\xde\xd40_" +
+	"\x8c\xed9q\x0f@\xbe\xa8a\xfe\xce\x1ag.\xef\x8c" +
+	"W#Mw\xc9\xc8\x81F]o\xc2\x90\xdb\x04tx" +
+	"\x14\xae1\x05\x0cS\x80\xc1\x98p\x84gH\x17=r" +
+	"E\x95v\xc7\xa7\xb1KW\xca-\x06h\xfe\xf8e\x99" +
+	"oK%&SDV\x01\xf2\x06\xa5\xea\xe90@=" +
+	"\xd8\xab\x02\xd4q\xaa\x19j\x99ck\x010\x919:" +
+	"\x08\x80\xc9\xcc\x11\xa24d\xf6\x8f\x00`*S&\xb0" +
+	"1S\xf2\x00\xb0)3I\xfd\xe6d&\xa8_s\xc6" +
+	"\xa2GKF\x10\xb35c\xec\x06\x88\x02\xdc\x84+]" +
+	"o\xda(\x03@\xfc?\xddg9{:\xa4Wr\xf6" +
+	"\x04\xea\xb7\xcfr\x00\xf7\x1c(z\xd6\x84\xe1\x95\x83\xca" +
+	"\xb3\x0fR\x96C\x85(:(\x0c\x0f\xb0\x1c\xff\xef(" +
+	"\x93\x8c@\x0aOZ\x86\xa7\xc4G\xff\x95\xf8\xa0\xe4\x14" +
+	"h\xce\x16\xa4G-a\x06\x9e\xf0-S\xd0\x91j\x19" +
+	"v`[ST\xf8\x91\x90\xf6\x84\x90\x9f\x19\xec\xc8_" +
+	"\x07\\\xcb\x91\x003\\\x83\xf6\xc5\x1d\x1a\xe6\xed\x9a\xad" +
+	"fQ\xf8\x1a\xd70/)\x8fO\x84\xbe19X\xeb" +
+	"\x1b\xc9\x8ao\xec\x8b}#\xb0\x0di\xc9\x92)\x80l" +
+	"\x0a\x0c\x9b\xd5\x05\xca\x19#\x10PDX\xa1\xe4M\x19" +
+	"\xb2\xe4A\x9c\xbc\x042\xac8\x0a\xc8\xd9.\x1d0Q" +
+	"\xc3,\"\x7f\xe7eF\xfe[*\xd7\xa6\xb1\x94\xe5^" +
+	"V\xe4\xe9\x8d\xa3Lu\xa7\x1c][\x13d\xaa;\xe5" +
+	"\xbe}q\x90\xc9$6\x86\x91\xe7\xf8`|\x92\xcc6" +
+	"\xc2Gq;gn\xae\xdf\x80q\xf2\xf2\x99>P\xbd" +
+	"\x03\xab,#\x9a\xf254\xbb\xab5\xcc\xaf\xab\x99\xf2" +
+	"\x1aRz\xb5\x86\xf9\x1bg\xb3\xae\x9f\xa1@O\xe5r" +
+	"\xbf9gXv\xc9\x13\x7f(HU\xf4\x10\xbdq\x90" +
+	"\xaa\xde(3\x13kc\xf7\xac\\'\xd5~\xaez\xe7" +
+	"\xc5%#\xbbR\"\xab\xb5\xb2\xf0<7\x8a\xee\x81!" +
+	"\xa5\x98(J\x9f\x18U\x03\xcf\xc2\xed>G\xfd\xe8\x86" +
+	"\xd9w\xf9\xe3\xcb\xe8\xb2\xbd\x92\x01\xab\x048U.*" +
+	"\x0b\x876\xbb>\x0c\x97\xab\x06U\xb8\xa45G-s" +
+	"U\xaf\x0a\x97\xcbW\x02\x1c(\x94<\x8f\xb2\xcb\xa2'" +
+	"L\xaa\xa7\x01\x9aT5\xf1\xad\x11[\x00@ *\xa5" +
+	",\x00H\x8f\x1a\x96\xfd\x07\x93\xdb\xda\xd5\x8e*9\xa4" +
+	"LOTFx\x197T+B?\xad-\x80\xbd\xaa" +
+	"*H\xaf\x10\xfe&\xc65A\xfe\x1a\xd2E\xf9\x9f\xa3" +
+	"\x0a\x92\xc6\xc2:\xc2;\xaa\xd2\xf56\xe1\x1f\xd4\x14\xc0" +
+	"~\xae\xc4\xbcOp\x82\xd5\x14\xc0\x90\xbe\xe4\x0cRy" +
+	"a\x19\xc1\x0d,,#,\xa6\xafe\xfa\"\xc2\xaf$" +
+	"<\xa5\x85e\x84\xe5\xf4aH\xbf\x92\xf0\xd5\xac\xa6\x8c" +
+	"\xb0J\x15;\xae&|\x1d\xe1M,,#\xac!\xf1" +
+	"\xfaj\xc2od5\xf5\xaf\xf5\xaa\xacq\x03\xe1=\xac" +
+	"\xa6\xfe\xd5\xa5\xaa \x1b\x09\xef#\xbc\x85\x85e\x84\xad" +
+	"J\x9f-\x84\x0f\x11\xde\x9a\xa8\x94\x11\xe8\xeb\x9a>D" +
+	"\xf8\x1d\x84\xcfM\x86e\x84]j\xdc\xdb\x09\x1f'<" +
+	"\xad\x85e\x04\xa1\xe4\x98\x84\xdfE\xf8\xbcDXF\xd8" +
+	"\xaf\xf8w\x12~\x821\xcc\x19\x05iM\x898\x94\xa8" +
+	"\xaf\x05\x17\x87\x17\xe9J\xc3\xdel\xd9\xa0\xd5\xc4\xa1\xa8" +
+	"^'\xc2\x00\x15o\xa0h\xdb\xc5\x05,\xdaosk" +
+	"\x9a\xb0GH\xc3\xb2\xfd\x9a\xe2Y\xf4\xb9\xb6R\xe1\x0a" +
+	"\x8b\xd7\xdd\xe3\x90\x12\x85=\x91*!\xeaw\xe1\x94a" +
+	"\xd9\x86r\xd0\xea\xb8\xb9\xa2Q\xf2knt5\x0a\xaa" +
+	"\x02\x1bD\x99\xf2\x08\xbd\x0e\x08\x0fr\xba:\x93\xe3\x8b" +
+	"\xad4\x14\x02\x9a\xe9c\x02\x18&\xc23\x8b\xb6\xc7f" +
+	"H\xd5\xc6\x96*\x8a\x96-\x06EAX\xa9)aF" +
+	"#\xd4\xb6R\xed/\x1e\x9b6\x10Y\x0cR\x14\xd4\xa3" +
+	"\xd9G\xdf\xf8\xab\xb3w\xa6\x84g\x8dZ\xb1ugX" +
+	"r6)\xe4\xcc\x00\xf2Y\xfb\xb6\xfai\xa1'\x17\xae" +
+	"\xd2\x8cZ>\xc5\xdb\x1e\x0d\xf3\x03q-\xbf\x9f\x12\xc5" +
+	">\x0d\xf3\xb7\xd5\xd4\xf2\x87\x0f\x01\xe4\x874\xcc\xdf\xc1" +
+	"\xea\xc6\xe2Y\xfa\xd5\xff\x0d\x00\x98\x06\xe1y"
 
 func RegisterSchema(reg *schemas.Registry) {
 	reg.Register(&schemas.Schema{
@@ -2909,6 +3853,7 @@ func RegisterSchema(reg *schemas.Registry) {
 			0x80ae746ee2596b11,
 			0x81c2f05a394cf4af,
 			0x859f26628fc24358,
+			0x8c5a0932805fcaaf,
 			0x9ccdc8676701b412,
 			0xa1680744031fdb2d,
 			0xa30662f84033036c,
@@ -2919,15 +3864,19 @@ func RegisterSchema(reg *schemas.Registry) {
 			0xaedffd8f31e7b55d,
 			0xb057204d7deadf3f,
 			0xb86e6369214c01c8,
+			0xbafa62a5f42ae3c4,
 			0xbd443b539493bc68,
 			0xc2243c65e0340384,
 			0xc86a3d38d13eb3ef,
 			0xcb9fd56c7057593a,
 			0xcd96dafb67a082d0,
+			0xd0799a7b5d0bc224,
 			0xd18274dcdc63c41d,
 			0xd6f78acca1bc3939,
 			0xda96579883444c35,
+			0xdb986541c9b0628f,
 			0xde9705979aca8339,
+			0xe830550b45c292f6,
 			0xf35cc4560bbf6ec2,
 			0xf416ec09499d9d19,
 			0xf98d843bfd7004a3,
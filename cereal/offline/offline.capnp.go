@@ -119,12 +119,12 @@ type Way capnp.Struct
 const Way_TypeID = 0xa4b9c59286b69600
 
 func NewWay(s *capnp.Segment) (Way, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 80, PointerCount: 7})
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 96, PointerCount: 10})
 	return Way(st), err
 }
 
 func NewRootWay(s *capnp.Segment) (Way, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 80, PointerCount: 7})
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 96, PointerCount: 10})
 	return Way(st), err
 }
 
@@ -387,12 +387,129 @@ func (s Way) SetMaxSpeedBackwardConditional(v string) error {
 	return capnp.Struct(s).SetText(6, v)
 }
 
+func (s Way) Layer() int8 {
+	return int8(capnp.Struct(s).Uint8(44))
+}
+
+func (s Way) SetLayer(v int8) {
+	capnp.Struct(s).SetUint8(44, uint8(v))
+}
+
+func (s Way) Bridge() bool {
+	return capnp.Struct(s).Bit(329)
+}
+
+func (s Way) SetBridge(v bool) {
+	capnp.Struct(s).SetBit(329, v)
+}
+
+func (s Way) Tunnel() bool {
+	return capnp.Struct(s).Bit(330)
+}
+
+func (s Way) SetTunnel(v bool) {
+	capnp.Struct(s).SetBit(330, v)
+}
+
+func (s Way) ExtraTags() (Tag_List, error) {
+	p, err := capnp.Struct(s).Ptr(7)
+	return Tag_List(p.List()), err
+}
+
+func (s Way) HasExtraTags() bool {
+	return capnp.Struct(s).HasPtr(7)
+}
+
+func (s Way) SetExtraTags(v Tag_List) error {
+	return capnp.Struct(s).SetPtr(7, v.ToPtr())
+}
+
+// NewExtraTags sets the extraTags field to a newly
+// allocated Tag_List, preferring placement in s's segment.
+func (s Way) NewExtraTags(n int32) (Tag_List, error) {
+	l, err := NewTag_List(capnp.Struct(s).Segment(), n)
+	if err != nil {
+		return Tag_List{}, err
+	}
+	err = capnp.Struct(s).SetPtr(7, l.ToPtr())
+	return l, err
+}
+func (s Way) NodeCurvatures() (capnp.Float32List, error) {
+	p, err := capnp.Struct(s).Ptr(8)
+	return capnp.Float32List(p.List()), err
+}
+
+func (s Way) HasNodeCurvatures() bool {
+	return capnp.Struct(s).HasPtr(8)
+}
+
+func (s Way) SetNodeCurvatures(v capnp.Float32List) error {
+	return capnp.Struct(s).SetPtr(8, v.ToPtr())
+}
+
+// NewNodeCurvatures sets the nodeCurvatures field to a newly
+// allocated capnp.Float32List, preferring placement in s's segment.
+func (s Way) NewNodeCurvatures(n int32) (capnp.Float32List, error) {
+	l, err := capnp.NewFloat32List(capnp.Struct(s).Segment(), n)
+	if err != nil {
+		return capnp.Float32List{}, err
+	}
+	err = capnp.Struct(s).SetPtr(8, l.ToPtr())
+	return l, err
+}
+func (s Way) NodeDeltas() (capnp.Int32List, error) {
+	p, err := capnp.Struct(s).Ptr(9)
+	return capnp.Int32List(p.List()), err
+}
+
+func (s Way) HasNodeDeltas() bool {
+	return capnp.Struct(s).HasPtr(9)
+}
+
+func (s Way) SetNodeDeltas(v capnp.Int32List) error {
+	return capnp.Struct(s).SetPtr(9, v.ToPtr())
+}
+
+// NewNodeDeltas sets the nodeDeltas field to a newly
+// allocated capnp.Int32List, preferring placement in s's segment.
+func (s Way) NewNodeDeltas(n int32) (capnp.Int32List, error) {
+	l, err := capnp.NewInt32List(capnp.Struct(s).Segment(), n)
+	if err != nil {
+		return capnp.Int32List{}, err
+	}
+	err = capnp.Struct(s).SetPtr(9, l.ToPtr())
+	return l, err
+}
+func (s Way) NameIndex() uint32 {
+	return capnp.Struct(s).Uint32(80)
+}
+
+func (s Way) SetNameIndex(v uint32) {
+	capnp.Struct(s).SetUint32(80, v)
+}
+
+func (s Way) RefIndex() uint32 {
+	return capnp.Struct(s).Uint32(84)
+}
+
+func (s Way) SetRefIndex(v uint32) {
+	capnp.Struct(s).SetUint32(84, v)
+}
+
+func (s Way) HazardIndex() uint32 {
+	return capnp.Struct(s).Uint32(88)
+}
+
+func (s Way) SetHazardIndex(v uint32) {
+	capnp.Struct(s).SetUint32(88, v)
+}
+
 // Way_List is a list of Way.
 type Way_List = capnp.StructList[Way]
 
 // NewWay creates a new list of Way.
 func NewWay_List(s *capnp.Segment, sz int32) (Way_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 80, PointerCount: 7}, sz)
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 96, PointerCount: 10}, sz)
 	return capnp.StructList[Way](l), err
 }
 
@@ -404,6 +521,106 @@ func (f Way_Future) Struct() (Way, error) {
 	return Way(p.Struct()), err
 }
 
+type Tag capnp.Struct
+
+// Tag_TypeID is the unique identifier for the type Tag.
+const Tag_TypeID = 0xbb56bdae5a266fcf
+
+func NewTag(s *capnp.Segment) (Tag, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	return Tag(st), err
+}
+
+func NewRootTag(s *capnp.Segment) (Tag, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	return Tag(st), err
+}
+
+func ReadRootTag(msg *capnp.Message) (Tag, error) {
+	root, err := msg.Root()
+	return Tag(root.Struct()), err
+}
+
+func (s Tag) String() string {
+	str, _ := text.Marshal(0xbb56bdae5a266fcf, capnp.Struct(s))
+	return str
+}
+
+func (s Tag) EncodeAsPtr(seg *capnp.Segment) capnp.Ptr {
+	return capnp.Struct(s).EncodeAsPtr(seg)
+}
+
+func (Tag) DecodeFromPtr(p capnp.Ptr) Tag {
+	return Tag(capnp.Struct{}.DecodeFromPtr(p))
+}
+
+func (s Tag) ToPtr() capnp.Ptr {
+	return capnp.Struct(s).ToPtr()
+}
+func (s Tag) IsValid() bool {
+	return capnp.Struct(s).IsValid()
+}
+
+func (s Tag) Message() *capnp.Message {
+	return capnp.Struct(s).Message()
+}
+
+func (s Tag) Segment() *capnp.Segment {
+	return capnp.Struct(s).Segment()
+}
+func (s Tag) Key() (string, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.Text(), err
+}
+
+func (s Tag) HasKey() bool {
+	return capnp.Struct(s).HasPtr(0)
+}
+
+func (s Tag) KeyBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.TextBytes(), err
+}
+
+func (s Tag) SetKey(v string) error {
+	return capnp.Struct(s).SetText(0, v)
+}
+
+func (s Tag) Value() (string, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.Text(), err
+}
+
+func (s Tag) HasValue() bool {
+	return capnp.Struct(s).HasPtr(1)
+}
+
+func (s Tag) ValueBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.TextBytes(), err
+}
+
+func (s Tag) SetValue(v string) error {
+	return capnp.Struct(s).SetText(1, v)
+}
+
+// Tag_List is a list of Tag.
+type Tag_List = capnp.StructList[Tag]
+
+// NewTag creates a new list of Tag.
+func NewTag_List(s *capnp.Segment, sz int32) (Tag_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2}, sz)
+	return capnp.StructList[Tag](l), err
+}
+
+// Tag_Future is a wrapper for a Tag promised by a client call.
+type Tag_Future struct{ *capnp.Future }
+
+func (f Tag_Future) Struct() (Tag, error) {
+	p, err := f.Future.Ptr()
+	return Tag(p.Struct()), err
+}
+
 type Coordinates capnp.Struct
 
 // Coordinates_TypeID is the unique identifier for the type Coordinates.
@@ -490,12 +707,12 @@ type Offline capnp.Struct
 const Offline_TypeID = 0xcb5ff253617678e0
 
 func NewOffline(s *capnp.Segment) (Offline, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 40, PointerCount: 1})
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 48, PointerCount: 3})
 	return Offline(st), err
 }
 
 func NewRootOffline(s *capnp.Segment) (Offline, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 40, PointerCount: 1})
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 48, PointerCount: 3})
 	return Offline(st), err
 }
 
@@ -594,12 +811,67 @@ func (s Offline) SetOverlap(v float64) {
 	capnp.Struct(s).SetUint64(32, math.Float64bits(v))
 }
 
+func (s Offline) Version() uint16 {
+	return capnp.Struct(s).Uint16(40)
+}
+
+func (s Offline) SetVersion(v uint16) {
+	capnp.Struct(s).SetUint16(40, v)
+}
+
+func (s Offline) Strings() (capnp.TextList, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return capnp.TextList(p.List()), err
+}
+
+func (s Offline) HasStrings() bool {
+	return capnp.Struct(s).HasPtr(1)
+}
+
+func (s Offline) SetStrings(v capnp.TextList) error {
+	return capnp.Struct(s).SetPtr(1, v.ToPtr())
+}
+
+// NewStrings sets the strings field to a newly
+// allocated capnp.TextList, preferring placement in s's segment.
+func (s Offline) NewStrings(n int32) (capnp.TextList, error) {
+	l, err := capnp.NewTextList(capnp.Struct(s).Segment(), n)
+	if err != nil {
+		return capnp.TextList{}, err
+	}
+	err = capnp.Struct(s).SetPtr(1, l.ToPtr())
+	return l, err
+}
+func (s Offline) Header() (Header, error) {
+	p, err := capnp.Struct(s).Ptr(2)
+	return Header(p.Struct()), err
+}
+
+func (s Offline) HasHeader() bool {
+	return capnp.Struct(s).HasPtr(2)
+}
+
+func (s Offline) SetHeader(v Header) error {
+	return capnp.Struct(s).SetPtr(2, capnp.Struct(v).ToPtr())
+}
+
+// NewHeader sets the header field to a newly
+// allocated Header struct, preferring placement in s's segment.
+func (s Offline) NewHeader() (Header, error) {
+	ss, err := NewHeader(capnp.Struct(s).Segment())
+	if err != nil {
+		return Header{}, err
+	}
+	err = capnp.Struct(s).SetPtr(2, capnp.Struct(ss).ToPtr())
+	return ss, err
+}
+
 // Offline_List is a list of Offline.
 type Offline_List = capnp.StructList[Offline]
 
 // NewOffline creates a new list of Offline.
 func NewOffline_List(s *capnp.Segment, sz int32) (Offline_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 40, PointerCount: 1}, sz)
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 48, PointerCount: 3}, sz)
 	return capnp.StructList[Offline](l), err
 }
 
@@ -610,81 +882,224 @@ func (f Offline_Future) Struct() (Offline, error) {
 	p, err := f.Future.Ptr()
 	return Offline(p.Struct()), err
 }
+func (p Offline_Future) Header() Header_Future {
+	return Header_Future{Future: p.Future.Field(2, nil)}
+}
+
+type Header capnp.Struct
+
+// Header_TypeID is the unique identifier for the type Header.
+const Header_TypeID = 0x8c8cc7b0437beaf5
+
+func NewHeader(s *capnp.Segment) (Header, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
+	return Header(st), err
+}
+
+func NewRootHeader(s *capnp.Segment) (Header, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
+	return Header(st), err
+}
+
+func ReadRootHeader(msg *capnp.Message) (Header, error) {
+	root, err := msg.Root()
+	return Header(root.Struct()), err
+}
+
+func (s Header) String() string {
+	str, _ := text.Marshal(0x8c8cc7b0437beaf5, capnp.Struct(s))
+	return str
+}
 
-const schema_da3a0d9284ca402f = "x\xda\xa4\x95\xcd\x8b\x1cE\x18\xc6\x9f\xb7\xbagz?" +
-	"f\xd3[\xa9\x0eD\xc9\xb2Q\x14bD7_\x07]" +
-	"\"Y\x12\x0d\xc92bz\x1b\xd9D\x0cRn\xf7d" +
-	"{\xa7\xa7z\xec\xe9\xd9\xdd\xf1\x83\x88\xa8\x10\x11\xd4\xa0" +
-	"b B\"\x11\x14<\x8b\x1e\x02\x1e\xfc@\xc5\x83\xd7" +
-	"\x9cD\xfc\x0b<\x89\x07[\xdeN\xe6#\xb8\xe8\xc1\xcb" +
-	"T\xd5\xef}\xea\xad\x97\x9a\xf7\xa9\xde\x97\x88\x05{\xff" +
-	"\xd4w\x02\xc2\xdf]\xa9\x16\x7f\xba\x7f\xbc\xf0[\xfd\xa9" +
-	"\xb7 gD1\xb7\xf0\xc3\xab\x17\xa7\xe6o\x00t\xf0" +
-	"$-\x91:K\x0ep\xf0\x0c-\x13\xa8\xf8\xf9\xf8Z" +
-	"\xed\xdb\xe5\xfb/\xc2\x9f\xa1\x11\xad\xcd\x92\x93b\x91\xd4" +
-	"Y\xe1\x00\xea\x8c\xd8\x00\xfd\xf5\xfe\xe7\xaf_\xfc\xe6\xcb" +
-	"k\xfe\x0cM\x0c\xa5\x15\x87\xb5\xd7\xc5\x04\xa9\x9fX{" +
-	"\xf0{1g\x83\x8a_6\xd7u\xf0\xfb3?r\xe6" +
-	"\xca\x88\xbc<=v\xf6\x92\xea\x95;\xbb\xce2\xe1\x81" +
-	"b%\xca\"\x9d\xcc\xa5v\xa3\x91\xc4&\x9aKo\x8e" +
-	"\x0f\xae\xe8\xb6i\xcf\x9f\x88\xcf\xadn\xe8\xde\xb1Dw" +
-	"\xa8s\x8a\xc8\x7f\x88\x04 \xaf\x1e\x05\x88\xe4\xa5E\x80" +
-	"\x84|o\x0d K\xbes\x00 [^X\x02\xa8\"" +
-	"_cIU\xbe\xf4,@\x8e\xec1\x1c\x93\xdd\x0c\xa0" +
-	"q\xf9\x1c\xef\x9b\x90-\xde7)c\x1ej2b\xe5" +
-	"\x94\xd4k\xc0\xf9\xaei\x9at\xc3\x14\xad4O\xb3\x0d" +
-	"\xdd\x030\x9c\xbb\xf5\xd84g\xf3\xack\x9aE\xf9[" +
-	"\x8f\x0d\xa8y\xbe\x9d\xc5-\x9d\xf5\x8a[c\x1dNl" +
-	"\x9aE'ZIM\xa83Po8\x9f\xedq\x8e\"" +
-	"\x8f\xb2<\xd6Y\x99~0/\xd3\x17]\xb3\x92\xe8N" +
-	"'\x86\xdb\x88\xa3\xb0\xc8\xa2N\x1cF&\x87\x13\xeb\xa4" +
-	"H\xe2\xf5\xd8\x9c\x0br\xb8Y\x14\xe5\xffq\x85\xc7\xd2" +
-	"4\x0bc\xa3\xf3\xa8\x03\xf0\x15\x8eY6`\x13 \xef" +
-	"[\x04\xfc=\x16\xf9\x87\x04I\"\x8f\x18\xee_\x02\xfc" +
-	"}\x16\xf9\x87\x05\x15\x89\xce\xe3\xbc\x1bF\xe0\x9b\x82\xa0" +
-	"IP\x91\xa4\xe6\x1cCP4`\xfd\x12\xac-KX" +
-	"\xe6\x1b\xe4\xa3\xeb\xfd\xa3UE\xec\x05\x96\x84EAM" +
-	"\x0c\x0fW\xe3\xe2n \xb0\x99O\x0bA$<\xfe\xbf" +
-	"\xd5\x94X\x04\x82\x1a\xe3\x9d,\xb7\xc8#\x0bP;\xc4" +
-	"<\x10L3\xdf\xc5\xdc\x16\x1e\xd9\x80\xba\xa3\xe4\x1e\xf3" +
-	"\xdd\xcc+\x96G\x15@\xcd\x94|'\xf3{\x98Wm" +
-	"\x8f\xaa\x80\xba\xab\xe4\xbb\x98\xefa\xee\x08\x8f\xdbU\xdd" +
-	"+\x0e\x00\xc1n\xe6\x87\x99\x8f\xed\xf1h\x0cP\x0f\x97" +
-	"\xfc\x10\xf3\x05\xe6\xe3U\x8f\xc6\x01\xf5\x88\xc8\x80\xe00" +
-	"\xf3\x13\xcc',\x8f&\x00\xf5X\x99\x7f\x81y]\x08" +
-	"\xda?y\x82<\x9a\x04\xd4\xc92\xf0(\x07N\xf1\x86" +
-	"\x9a\xe3Q\x0dP\x8f\x8bW\x80\xa0\xce\xfc4\xf3\xa91" +
-	"\x8f\xa6\x00\xf5\xa4x\x03\x08N3\x0f\x99o\x1b\xf7h" +
-	"\x1b\xa0\xb4\xb8\x13\x08\x9ef\xbe\xca\xdc\xdd\xe1\x91\x0b\xa8" +
-	"H\xac\x01A\xc8\xbc\xcd|\xda\xf6h\x1aP-\xf1\x11" +
-	"\x10\xb4\x99\xbf\xc8\\V<\x92\x80\xea\x89\x1b@\xf02" +
-	"\xf37\x99o\xafz\xb4\x1dP\x17\xc4\xaf@\xf06\xf3" +
-	"\xcbB\x90kt+\xa2\x1a\x04\xd5@N\x165\xfa\xf3" +
-	"\xa2\xa57\x83v\x14\x85#\x0ds\xa4\x15\x9b\xba\xceo" +
-	"[\xa6f\xb8\xd4\x9b\xb7E\xf5\xe6Ht\xd6\xa4a\xd4" +
-	"\xa1m\xa0S\x16\xd1\xf4\xf0\xd9\x021\x9cM\xb4\x89:" +
-	"T\x85\xa0*\xa8\xd0\xe1z\xdcI\xb3\x1ef\xcb\x1a\x06" +
-	"9W\xf5\xf3:\x0b\xfb5\x1eIM\xb4\xac{D\x10" +
-	"D#%\xd3q\xf6w\x16\x0e;}\x109\xaaW\x9a" +
-	"eh\x10\xb3\xe2\x90*\x10T\x01\x15\xab\xb7\x1e)\xb8" +
-	"lYr\x87\xef0\x88\xdc\xd1<\xc7R\x13\xc6y\x9c" +
-	":F'\xff\xb82q\xeb\xfc\xbe\xc8X[\x89\xfa\xa5" +
-	"l\x99\xea\xdfm\xf8D\xa3\xe1\xf2\x92\x9d\xb8s\xf0\x08" +
-	"\\\x9a\x07\xfcw-\xf2\xaf\x8c<\x02\x1f2\xfc\xc0\"" +
-	"\xff\x9a )n\xbaP^ex\xd9\"\xff\x13\xb6\xa0" +
-	"UZP~\xcc\xf0\x8aE\xfeg\x82\xc8.\xed'?" +
-	"\xdd\x0b\xf8\xd7,\xf2\xbfb\xef\xd9\xa5\xf7\xe4\xf5\xa3\x80" +
-	"\xff\x85E\xfe\xd7\xe2\x7f\xb5\x84\xbb\xa1{\xc3\x8e\xe8\x7f" +
-	"\x9bn\xf6\xc3\xf9t=\xca\x12\xdd\xeek\xff\x0e\x00\x00" +
-	"\xff\xff(\xca\x8aM"
+func (s Header) EncodeAsPtr(seg *capnp.Segment) capnp.Ptr {
+	return capnp.Struct(s).EncodeAsPtr(seg)
+}
+
+func (Header) DecodeFromPtr(p capnp.Ptr) Header {
+	return Header(capnp.Struct{}.DecodeFromPtr(p))
+}
+
+func (s Header) ToPtr() capnp.Ptr {
+	return capnp.Struct(s).ToPtr()
+}
+func (s Header) IsValid() bool {
+	return capnp.Struct(s).IsValid()
+}
+
+func (s Header) Message() *capnp.Message {
+	return capnp.Struct(s).Message()
+}
+
+func (s Header) Segment() *capnp.Segment {
+	return capnp.Struct(s).Segment()
+}
+func (s Header) DataTimestamp() int64 {
+	return int64(capnp.Struct(s).Uint64(0))
+}
+
+func (s Header) SetDataTimestamp(v int64) {
+	capnp.Struct(s).SetUint64(0, uint64(v))
+}
+
+func (s Header) GeneratorVersion() (string, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.Text(), err
+}
+
+func (s Header) HasGeneratorVersion() bool {
+	return capnp.Struct(s).HasPtr(0)
+}
+
+func (s Header) GeneratorVersionBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(0)
+	return p.TextBytes(), err
+}
+
+func (s Header) SetGeneratorVersion(v string) error {
+	return capnp.Struct(s).SetText(0, v)
+}
+
+func (s Header) SourceHash() (string, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.Text(), err
+}
+
+func (s Header) HasSourceHash() bool {
+	return capnp.Struct(s).HasPtr(1)
+}
+
+func (s Header) SourceHashBytes() ([]byte, error) {
+	p, err := capnp.Struct(s).Ptr(1)
+	return p.TextBytes(), err
+}
+
+func (s Header) SetSourceHash(v string) error {
+	return capnp.Struct(s).SetText(1, v)
+}
+
+// Header_List is a list of Header.
+type Header_List = capnp.StructList[Header]
+
+// NewHeader creates a new list of Header.
+func NewHeader_List(s *capnp.Segment, sz int32) (Header_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2}, sz)
+	return capnp.StructList[Header](l), err
+}
+
+// Header_Future is a wrapper for a Header promised by a client call.
+type Header_Future struct{ *capnp.Future }
+
+func (f Header_Future) Struct() (Header, error) {
+	p, err := f.Future.Ptr()
+	return Header(p.Struct()), err
+}
+
+const schema_da3a0d9284ca402f = "x\xda\xa4\x96_l\x1cW\x15\xc6\xbfo\xeezwm" +
+	"\xaf\xbb\x9e\xdeq\x9a\xa4\xb1\x0cQ\x04!\x10\xe2$E" +
+	"\x0aQ$\xa7qZ9\x96+2\x9e\xb4)\x11\x88\xde" +
+	"x\xae\xed\x89\xd73fv\xd6\xf6\xb6 K\x88\"\xa8" +
+	"\"\x01\x11E\xad\x04R\x83\xfa\xc2\x03\xff^\x10\x15\xf0" +
+	"\x08\x08x\x80J\xf0\xd0>!\x04R\x8b\x8a\xd4\xa2T" +
+	"JQ`\xd0\x99\xcd\xfe)\x89\xca\x03/\xbbw~\xe7" +
+	"\xdcs\xce\x9c{\xe7\xbbw\xfa\xbb\xeat\xe9\xe8\xd8\x96" +
+	"\x82\xe3\x1f\x1c*\xe7o\xff\xed\xa9\xd9\x1f\xfe\xea\xeaU" +
+	"\xf8\x93d~\xe4\xf4o\xbexm\xec\xe4\xab\x18r*" +
+	"\xc0\xf1\xeb\xdcO\xfd#V\x00\xfd=\xfe\x00\xcc\xffY" +
+	"\xbf\xf9\xd4_\x16.}\x15\xee\xa4\xd3w\x06\x8f?\xea" +
+	",RG\xc5$\xeb\\$\x98\xbf\xfc\xf0\x95\xda//" +
+	"~\xf8\x9a\x04\x1e\xf0-\x89\xcb\xa3j\x9e:R\x12\xd7" +
+	"\xaa-\xf0\xdf\xdf\xfc\xf1\x97\xae\xfd\xe2\xa5\x17\xfdI\xd6" +
+	"\x06j\x18\x11\xdf_\xab\x11\xeaW\xc4\xf7\xf8\x1f\xd5\x07" +
+	"\xcb`\xfe\xbb\xe4\x03\x97\xbe\xff\xf3\xc7~\x0aw\xf2\x8e" +
+	"\x8a_\x19\x19\xa1~]&\xea\xbf\x8el\x81\xf9\x9f\xb6" +
+	"7M\xf0\x8f\xcf\xfcV\xca(\x0fx\x17\x01\x1f\x1c=" +
+	"D\xed\x8f\xca\xf0\x91\xd1\x9c8\x9c/\xd9\xd4\x9a\xc6\x91" +
+	"D-/7\xa2\xd8\x1eI:\xff\x1f]2\x1b\xf1\xc6" +
+	"\xc99k*\xa1M\xcf\x93~M\x95\x80\x12\x01\xf7\xa1" +
+	"\x14\xf0\xcf*\xfa\xe7\x1d\x92\x1e\x85=\xf2\x0c\xe0\x9fW" +
+	"\xf4?\xe5\xd0u\xe8\xd1\x01\xdcO^\x02\xfc\xc7\x15\xfd" +
+	"\xd0a\x1e\x9a\xcc\\\x88\xd6-\xa6\x9a\x99Y\xdf\xe0\x10" +
+	"\x1c\x0e\x81\xf9\x8a\x8dmj\xb2\x84\xe9c6mFI" +
+	"\x0c\xb0\x06\x8750o&\xadt\xc9\xce\x19\xa8\xe6j" +
+	"\x0fv+.\xdd\xbd\xe2heu\xcb\xb4g\x1b\xa6\xc9" +
+	"\xa6\xd4}\xa2(\xe5\xfa\x19\x80t\x9f\x9f\x07\xe8\xb8\xcf" +
+	"^\x01\xa8\xdc\xaf\x1f\x03Xr\xbf\xb2\x08p\xc8}Z" +
+	"\\\xca\xee\xe7/\x03\xac\xb8m\x81U\xb7\x95\x02\x1cv" +
+	"?+\xf3F\xdcu\x997\xeaF\xf2Ws\xadx\x8e" +
+	"\xb9\xe6\x0a\xb0\xd3\x8a\xd7\xe2d+\xce\xd7\x93,I\xb7" +
+	"L\x1b@\x7f\\_\x88\xe2\xb5\xa9,m\xc5ky\xf1" +
+	"\xbb\x10\xc5\xe0\xda\xceF\x1a\xad\x9b\xb4\x9d\xdf\xfe_@" +
+	"%\x8a\xd7\xf2\xa6]J\xe2\xd0\xa4`\xbb?\x9ejK" +
+	"\x8c<\xb3i\x16\x99\xb4\x08\xdf\x1b\x17\xe1\xf3V\xbc\xd4" +
+	"0\xcdf\x84\xfard\xc3<\xb5\xcd(\xb4q\x86J" +
+	"d\x1ay#\xda\x8c\xe2\x95 C=\xb56\xfb\x1f-" +
+	"\x9cM\x924\x8cb\x93\xd9& -\xac\xf6\x96\xfeC" +
+	"\xf3\x80\x7fP\xd1\x7f\xc0\xa1\xdb]\xfb\xa3\x8b\x80?\xad" +
+	"\xe8\x9fr\x987L\x16e\xad\xd0B:\x05\x87\xa3`" +
+	"\xdeH\xe2\x15\x81\xa0\xed\xb1\xf7\xdew\x17M\xbb\x93z" +
+	"\xa3\x9bZS\x1d\x02\x82[\x8ebPU\xfd\xeczH" +
+	"\xed\x07\x16\x95bPS\x0e\xe9\x14[O\x0f\xaby " +
+	"\xa8\x0a\xf6\xc4[\xd1\xa3\x02\xb4\xabN\x02AM\xf8n" +
+	"\xe1%\xc7c\x09\xd0\x13\x05\x1f\x17\xbeO\xf8\x90\xf28" +
+	"\x04\xe8=\x05\xf7\x84\xbfOx\xb9\xe4\xb1\x0c\xe8\xc9\x82" +
+	"\xef\x16~@x\xc5\xf1\x0a\xd5x\xbf:\x06\x04\xfb\x84" +
+	"\x9f\x10^=\xe8\xb1\x0a\xe8\x8f\x15|Z\xf8)\xe1\xc3" +
+	"e\x8f\xc3\x80\xfe\xb8J\x81\xe0\x84\xf0\xb3\xc2G\x94\xc7" +
+	"\x11@?X\xc4?%|N9<::G\x8f\xa3" +
+	"\x80~\xa80\x9c\x16\xc3\x82L\xa8U<\xd6\x00}N" +
+	"}\x01\x08\xe6\x84_\x10>V\xf58\x06h_=\x03" +
+	"\x04\x17\x84?!\xfc\x9ea\x8f\xf7\x00\xfa\xd3j/\x10" +
+	"<.<\x14^\x9f\xf0X\x07\xb4QW\x80\xe0\x09\xe1" +
+	"\x0d\xe1\xe3%\x8f\xe3\x80\x8e\xd4w\x80\xa0!|[\xb8" +
+	";\xe4\xd1\x05tK\xbd\x0a\x04\x9f\x13\xfee\xe1\xf7\x96" +
+	"=\xde\x0b\xe8\xa7\xd5\x9f\x81\xe0\xaa\xf0\xe7\x84\xeb\x8fx" +
+	"\xd4\x80~\xb6h\xc4\xd7\x84\x7fK^\xcc;G\x8f\x1e" +
+	"\xa0\x9f/^\xec\x1bbxA\x0c\x13\xf3\xf48\x01\xe8" +
+	"o\x17\x86\xe7\xc4\xf0\xa2D\xdaU\xf1\xb8\x0b\xd0\xd7\xd5" +
+	"\"\x10\xbc \xfcg\xc2\xef\xabz\xbc\x0f\xd0/\xa9'" +
+	"\x81\xe0'\xc2\xff |\xf7\xb0\xc7\xdd\x80~Y]\x02" +
+	"\x82\xdf\x0b\x7fC\xf8\x1e\xcf\xe3\x1e@\xbf^\xc4yM" +
+	"\xf8\x0d\xe1{'<\xee\x05\xf4[\xc5\x16zS\xf8-" +
+	"\xe1\xf7\xef\xf2x?\xa0\xdfQ\x97\x81\xe0\xa6\xf0R\xc9" +
+	"a=6\xeb\xb6+N\x95\xd4.w\xc7\xf9\xba\xd9\x0e" +
+	"6\xac\x0d\x07>\x85\x99\xf5(^0\xd9\xbb\x1e\x93\xb8" +
+	"\xffh\xb6\xdfe5\xdb\x03\xd6\xa98\x09m\x93\xf7\x80" +
+	"\xe7\x159\xde?o@\x81S\x0d\x13\xdb&\xcbp(" +
+	"g\x86\x097\xa3f\x92\xb61U\xd4\xd0\x8b\xb9j\x9e" +
+	"4i\xd8\xadq&\x89\xedE\xd3&\xe1\x90\x03%\xf3" +
+	"aQ\xae4\xec\x7f\xc3=\xcb\x19\xb3\xb4V\x98z6" +
+	"\x15\x85=5_\xbd-\xbf\xa8\x8b\x18\xb1\xde?@A" +
+	"\xd6\x07\xe3\xcc&q\x18eQR\x89M\xe3\x8e\x969" +
+	"\xb7\xf3w\x9dbu7\xa7n)w\x0b5\xd50m" +
+	"\x9b\xd2\x81C\x07\x9c\xb9\x9cF\xe1\x8a\xed\xbe\xe7L\xd6" +
+	"\x8ac\xdb\xe8\xbd\xb6\xdd\xceRs\xc1\xac\x80\x03\xfd\xed" +
+	"\x9d\xba\x9d\xfe\xe6\xd2\xfe\xd9V\xba\x89\x19\x93\xb5\xd2\xfe" +
+	"J\x8c\xc0\xe9\xd9\xcf\xdaF\x06ez\xb6R\xd7f\xd6" +
+	"\xed\xb98\xb4\xe06\xabpX\x05\xf3\xd4.\x0b\xda\x06" +
+	"\xd0c\x9d\xd59\x17\xa3\x12\xda\xbe\xe7{k\xa5\xd4\xfd" +
+	"\xdf2\xbd\x1f\xf0\x0f(\xfa\xd3\x032}\xf8X_\xbb" +
+	"+k\xb6\xdd\xeb\xd4\xa6i\xb4\xec\x1d\xc7\xeb\xdd\x93}" +
+	"by\xb9.\x8f\x92\xef@/\xdf\xdfO\x02\xfek\x8a" +
+	"\xfe\x8d\x81|o\x09|C\xd1\xbf)W\x82\x8e.\xbb" +
+	"o\x0b|S\xd1\xbf%\xa2\xac\x0aQv\xdf\x11xC" +
+	"q\x91\x0eY*\x04\xd9\xfd\xd7!\xc0\xbf)\xf2M\x91" +
+	"\xe3RG\x8e]\x9e\x11\xf9\xa6\xc8\xb7\xf0\xb2\xd7\x91\xe3" +
+	"\x89\x82\x8f\x0b\xdf'\xbc\xc2\x8e\x1c\xef)\xb8'|Z" +
+	"x\xd5\xe9\xc8\xf1a\x8a\xa6\x1c\x14\xfe\x00\x9d\xff\xeb\xb3" +
+	"\xaco\x99v\x7f\xd7t/v\x9d=\xb3\x93l\xda\xb4" +
+	"a6\xba\xbe;\x9b\x9d+\x0e+pX\x01w\x9aY" +
+	"\x1a\xc5+\xbd\xe9\xb5\xcev\x99Y\xb5&\xb4)\xc7\xfb" +
+	"\x97U\x90\xe3\xe0\x7f\x06\x00\x85\xbeK\x88"
 
 func RegisterSchema(reg *schemas.Registry) {
 	reg.Register(&schemas.Schema{
 		String: schema_da3a0d9284ca402f,
 		Nodes: []uint64{
+			0x8c8cc7b0437beaf5,
 			0x8f5a4ce47bf80ffa,
 			0x922b57c60c6a46d1,
 			0xa4b9c59286b69600,
+			0xbb56bdae5a266fcf,
 			0xcb5ff253617678e0,
 		},
 		Compressed: true,
@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/urfave/cli/v3"
+	"pfeifer.dev/mapd/cereal"
+	"pfeifer.dev/mapd/cereal/custom"
 	"pfeifer.dev/mapd/maps"
 	m "pfeifer.dev/mapd/math"
 	"pfeifer.dev/mapd/params"
+	ms "pfeifer.dev/mapd/settings"
 )
 
 func Handle() {
@@ -68,6 +72,16 @@ func Handle() {
 						},
 						Value: "./map.osm.pbf",
 					},
+					&cli.StringFlag{
+						Category: "Inputs and Outputs",
+						Name:     "profile",
+						Usage:    "A json generator profile with the roads and tags to keep, the roads of scripts/filter_planet.sh when empty",
+					},
+					&cli.StringFlag{
+						Category: "Inputs and Outputs",
+						Name:     "changes",
+						Usage:    "An osm change file (.osc or .osc.gz) already applied to --input-file. Only the areas it affects in a previous generation in --output-directory are rewritten",
+					},
 					&cli.StringFlag{
 						Category: "Inputs and Outputs",
 						Aliases: []string{
@@ -77,6 +91,42 @@ func Handle() {
 						Name:  "output-directory",
 						Value: fmt.Sprintf("%s/offline", params.GetBaseOpPath()),
 					},
+					&cli.IntFlag{
+						Category: "Inputs and Outputs",
+						Name:     "format",
+						Usage:    "The offline file format, 2 for smaller files that need a newer mapd to read",
+						Value:    int(maps.OFFLINE_FORMAT_V1),
+					},
+					&cli.BoolFlag{
+						Category: "Inputs and Outputs",
+						Name:     "unpacked",
+						Usage:    "Writes unpacked map files that mapd reads in place from memory, they are larger and need a newer mapd to read",
+						Value:    false,
+					},
+					&cli.BoolFlag{
+						Category: "Inputs and Outputs",
+						Name:     "pack",
+						Usage:    "Writes the tiles of every group into a single pack file instead of a file per tile, packs need a newer mapd to read",
+						Value:    false,
+					},
+					&cli.BoolFlag{
+						Category: "Inputs and Outputs",
+						Name:     "legacy",
+						Usage:    "Writes format 1 map files without a version, header or curvatures, laid out like files from before them",
+						Value:    false,
+					},
+					&cli.BoolFlag{
+						Category: "Inputs and Outputs",
+						Name:     "split-areas",
+						Usage:    "Splits areas with more roads than --ways-per-file into quarters, split areas need a newer mapd to read",
+						Value:    false,
+					},
+					&cli.IntFlag{
+						Category: "Inputs and Outputs",
+						Name:     "ways-per-file",
+						Usage:    "The most roads a map file holds before its area is split when --split-areas is set",
+						Value:    ms.WAYS_PER_FILE,
+					},
 					&cli.BoolFlag{
 						Category: "Inputs and Outputs",
 						Name:     "generate-empty-files",
@@ -86,9 +136,53 @@ func Handle() {
 						},
 						Value: false,
 					},
+					&cli.Float64Flag{
+						Category: "Geometry",
+						Name:     "simplify",
+						Usage:    "Drops road nodes within this many meters of the simplified road while keeping the nodes curves need for their radius, off when 0",
+						Value:    0,
+					},
+					&cli.Float64Flag{
+						Category: "Geometry",
+						Name:     "resample",
+						Usage:    "Adds nodes along the curve to curved road segments longer than this many meters, off when 0",
+						Value:    0,
+					},
+					&cli.IntFlag{
+						Category: "Performance",
+						Name:     "workers",
+						Usage:    "The number of map files written in parallel, one per cpu when 0",
+						Value:    0,
+					},
+					&cli.IntFlag{
+						Category: "Performance",
+						Name:     "memory-limit",
+						Usage:    "Megabytes of scanned roads kept in memory before they are spilled to disk, and of node locations read for a pbf without them on its ways",
+						Value:    maps.DEFAULT_MEMORY_MB,
+					},
+					&cli.StringFlag{
+						Category: "Performance",
+						Name:     "tmp-directory",
+						Usage:    "The directory roads are spilled to, the system temporary directory when empty",
+					},
 				},
 				Usage: "Triggers a generation of map data from the pbf given by --input-file",
 				Action: func(ctx context.Context, cmd *cli.Command) error {
+					profile, err := maps.LoadProfile(cmd.String("profile"))
+					if err != nil {
+						return err
+					}
+					format := cmd.Int("format")
+					if format < int(maps.OFFLINE_FORMAT_V1) || format > int(maps.OFFLINE_FORMAT_LATEST) {
+						return fmt.Errorf("unknown offline file format %d", format)
+					}
+					waysPerFile := 0
+					if cmd.Bool("split-areas") {
+						waysPerFile = cmd.Int("ways-per-file")
+						if waysPerFile <= 0 {
+							return fmt.Errorf("ways per file must be positive, got %d", waysPerFile)
+						}
+					}
 					offlineSettings := maps.OfflineSettings{
 						Box: m.Box{
 							MinPos: m.NewPosition(cmd.Float64("minlat"), cmd.Float64("minlon")),
@@ -98,11 +192,82 @@ func Handle() {
 						InputFile:          cmd.String("input-file"),
 						OutputDirectory:    cmd.String("output-directory"),
 						GenerateEmptyFiles: cmd.Bool("generate-empty-files"),
+						Workers:            cmd.Int("workers"),
+						MemoryLimit:        int64(cmd.Int("memory-limit")) * 1024 * 1024,
+						TmpDirectory:       cmd.String("tmp-directory"),
+						Profile:            profile,
+						Simplify:           cmd.Float64("simplify"),
+						Resample:           cmd.Float64("resample"),
+						Format:             uint16(format),
+						WaysPerFile:        waysPerFile,
+						Pack:               cmd.Bool("pack"),
+						Unpacked:           cmd.Bool("unpacked"),
+						Legacy:             cmd.Bool("legacy"),
+					}
+					if cmd.String("changes") != "" {
+						maps.ApplyChanges(offlineSettings, cmd.String("changes"))
+						return nil
 					}
 					maps.GenerateOffline(offlineSettings)
 					return nil
 				},
 			},
+			{
+				Name:    "radius",
+				Aliases: []string{"r"},
+				Flags: []cli.Flag{
+					&cli.Float64Flag{
+						Name:  "radius",
+						Usage: "Download maps within this many kilometers",
+						Value: 50,
+					},
+					&cli.StringFlag{
+						Name:  "position",
+						Usage: "Center the download on lat,lon instead of the current or last known position",
+					},
+				},
+				Usage: "Download maps around the current position in an active instance of mapd",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.String("position") != "" {
+						_, _, err := ms.ParseDownloadCenter(cmd.String("position"))
+						if err != nil {
+							return err
+						}
+					}
+					pub := cereal.NewPublisher("mapdCli", cereal.MapdInCreator)
+					defer pub.Pub.Msgq.Close()
+					msg, input := pub.NewMessage(true)
+					input.SetType(custom.MapdInputType_downloadRadius)
+					input.SetFloat(float32(cmd.Float64("radius")))
+					err := input.SetStr(cmd.String("position"))
+					if err != nil {
+						return err
+					}
+					return pub.Send(msg)
+				},
+			},
+			{
+				Name: "manifest",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "directory",
+						Usage: "The directory containing the offline folder of compressed group archives",
+						Aliases: []string{
+							"d",
+						},
+						Value: ".",
+					},
+					&cli.StringFlag{
+						Name:  "data-version",
+						Usage: "The data version recorded in each manifest, defaults to the current UTC time",
+						Value: time.Now().UTC().Format(time.RFC3339),
+					},
+				},
+				Usage: "Writes a sha256 manifest next to every compressed group archive for download verification",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return ms.GenerateManifests(cmd.String("directory"), cmd.String("data-version"))
+				},
+			},
 		},
 		Name:  "Mapd",
 		Usage: "Start an instance of mapd",
@@ -16,6 +16,8 @@ type downloadModel struct {
 	rootPaths []downloadItem
 	state     downloadState
 	menu      ms.DownloadMenu
+	nearby    []downloadItem
+	loaded    string // key of the nearby regions currently in the list
 }
 
 type downloadState int
@@ -27,6 +29,7 @@ const (
 
 type downloadItem struct {
 	title, desc string
+	path        string // download path of a region, empty for a submenu
 }
 
 func (i downloadItem) Title() string {
@@ -51,24 +54,114 @@ func getDownloadModel() downloadModel {
 		dItem := downloadItem{title: k}
 		dItems = append(dItems, dItem)
 	}
-	items := []list.Item{}
-	for _, item := range dItems {
-		items = append(items, item)
+	// every region is listed after the submenus so they can be searched by
+	// their full name
+	regions := []downloadItem{}
+	for _, k := range keys {
+		for name, location := range menu[k] {
+			path := fmt.Sprintf("%s.%s", k, name)
+			regions = append(regions, downloadItem{title: location.FullName, desc: path, path: path})
+		}
 	}
+	sort.Slice(regions, func(i, j int) bool {
+		if regions[i].title != regions[j].title {
+			return regions[i].title < regions[j].title
+		}
+		return regions[i].path < regions[j].path
+	})
+	dItems = append(dItems, regions...)
 
 	listDelegate := list.NewDefaultDelegate()
 
-	m := downloadModel{list: list.New(items, listDelegate, 0, 0), menu: menu}
+	m := downloadModel{list: list.New([]list.Item{}, listDelegate, 0, 0), menu: menu}
 	m.list.Title = "Select Download Area"
 	m.rootPaths = dItems
+	m.list.SetItems(m.rootItems())
+	return m
+}
+
+// rootItems lists the regions around the vehicle first, then the submenus and
+// the rest of the regions.
+func (m downloadModel) rootItems() []list.Item {
+	items := []list.Item{}
+	nearby := map[string]bool{}
+	for _, item := range m.nearby {
+		items = append(items, item)
+		nearby[item.path] = true
+	}
+	for _, item := range m.rootPaths {
+		if !nearby[item.path] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func nearbyItems(out custom.MapdExtendedOut) (items []downloadItem, key string) {
+	regions, err := out.NearbyRegions()
+	if err != nil {
+		return nil, ""
+	}
+	for i := range regions.Len() {
+		region := regions.At(i)
+		name, _ := region.Name()
+		fullName, _ := region.FullName()
+		status := "not downloaded"
+		if region.Installed() {
+			status = "installed"
+		} else if region.InstalledFiles() > 0 {
+			status = fmt.Sprintf("%d of %d files installed", region.InstalledFiles(), region.Files())
+		}
+		desc := fmt.Sprintf("Region around you, %s, %s", name, status)
+		items = append(items, downloadItem{title: fullName, desc: desc, path: name})
+		key += fullName + desc + "\n"
+	}
+	return items, key
+}
+
+func (m downloadModel) sendDownload(path string, mm *uiModel) downloadModel {
+	m.state = showRootDownloadMenu
+	mm.state = showMenu
+	msg, input := mm.pub.NewMessage(true)
+
+	input.SetType(custom.MapdInputType_download)
+	err := input.SetStr(path)
+	if err != nil {
+		panic(err)
+	}
+
+	err = mm.pub.Send(msg)
+	if err != nil {
+		panic(err)
+	}
+
+	m.list.ResetFilter()
+	m.list.SetItems(m.rootItems())
+	m.list.ResetSelected()
 	return m
 }
 
 func (m downloadModel) Update(msg tea.Msg, mm *uiModel) (downloadModel, tea.Cmd) {
 	switch msg := msg.(type) {
+	case TickMsg:
+		if m.state == showRootDownloadMenu && mm.extendedDataValid && m.list.FilterState() == list.Unfiltered {
+			items, key := nearbyItems(mm.extendedData)
+			if key != m.loaded {
+				m.loaded = key
+				m.nearby = items
+				m.list.SetItems(m.rootItems())
+			}
+		}
+		return m, nil
 	case tea.KeyMsg:
 		if msg.Type == tea.KeyEnter && m.state == showRootDownloadMenu && m.list.FilterState() != list.Filtering {
-			it := m.list.SelectedItem().(downloadItem)
+			it, ok := m.list.SelectedItem().(downloadItem)
+			if !ok {
+				return m, nil
+			}
+			if it.path != "" {
+				return m.sendDownload(it.path, mm), nil
+			}
 			m.path = it.title
 
 			var subKeys []string
@@ -82,6 +175,7 @@ func (m downloadModel) Update(msg tea.Msg, mm *uiModel) (downloadModel, tea.Cmd)
 				dItem := downloadItem{title: m.menu[m.path][k].FullName, desc: k}
 				items = append(items, dItem)
 			}
+			m.list.ResetFilter()
 			m.list.SetItems(items)
 			m.list.ResetSelected()
 
@@ -89,29 +183,7 @@ func (m downloadModel) Update(msg tea.Msg, mm *uiModel) (downloadModel, tea.Cmd)
 			return m, nil
 		} else if msg.Type == tea.KeyEnter && m.state == showSubDownloadMenu && m.list.FilterState() != list.Filtering {
 			it := m.list.SelectedItem().(downloadItem)
-			m.state = showRootDownloadMenu
-			mm.state = showMenu
-			msg, input := mm.pub.NewMessage(true)
-
-			input.SetType(custom.MapdInputType_download)
-			path := fmt.Sprintf("%s.%s", m.path, it.desc)
-			err := input.SetStr(path)
-			if err != nil {
-				panic(err)
-			}
-
-			err = mm.pub.Send(msg)
-			if err != nil {
-				panic(err)
-			}
-
-			items := []list.Item{}
-			for _, item := range m.rootPaths {
-				items = append(items, item)
-			}
-			m.list.SetItems(items)
-			m.list.ResetSelected()
-			return m, nil
+			return m.sendDownload(fmt.Sprintf("%s.%s", m.path, it.desc), mm), nil
 		}
 	case tea.WindowSizeMsg:
 		h, v := docStyle.GetFrameSize()
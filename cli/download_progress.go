@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"pfeifer.dev/mapd/cereal/custom"
@@ -40,12 +42,73 @@ func (m downloadProgressModel) View() string {
 			}
 		}
 	}
-	return docStyle.Render(fmt.Sprintf(
-		"locations: %s\nactive %t\ncancelled %t\ntotal files: %d\ndownloaded files: %d",
+	filesLabel := "downloaded files"
+	if progress.UpdateCheck() {
+		filesLabel = "checked files"
+	}
+	view := fmt.Sprintf(
+		"locations: %s\nactive %t\npaused (metered connection) %t\ncancelled %t\ntotal files: %d\n%s: %d\nupdates available: %d\n",
 		locations,
 		progress.Active(),
+		progress.Paused(),
 		progress.Cancelled(),
 		progress.TotalFiles(),
+		filesLabel,
 		progress.DownloadedFiles(),
-	) + "\n")
+		progress.UpdatesAvailable(),
+	)
+	if progress.TotalFiles() > 0 {
+		view += fmt.Sprintf("\n%s %d/%d files\n",
+			progressBar(float64(progress.DownloadedFiles())/float64(progress.TotalFiles())),
+			progress.DownloadedFiles(),
+			progress.TotalFiles(),
+		)
+	}
+	if file, err := progress.CurrentFile(); err == nil && file != "" {
+		size := "unknown size"
+		if progress.CurrentFileSize() > 0 {
+			size = formatBytes(progress.CurrentFileSize())
+		}
+		view += fmt.Sprintf("current file: %s (%s of %s)\n", file, formatBytes(progress.CurrentFileReceived()), size)
+	}
+	if progress.DownloadedBytes() > 0 {
+		view += fmt.Sprintf("downloaded %s", formatBytes(progress.DownloadedBytes()))
+		if progress.Active() && progress.BytesPerSecond() > 0 {
+			view += fmt.Sprintf(" at %s/s", formatBytes(uint64(progress.BytesPerSecond())))
+		}
+		if progress.Active() && progress.EtaSeconds() >= 0 {
+			view += fmt.Sprintf(", %s remaining", time.Duration(progress.EtaSeconds())*time.Second)
+		}
+		view += "\n"
+	}
+	failures, err := progress.FailedFiles()
+	if err == nil && failures.Len() > 0 {
+		view += fmt.Sprintf("\nfailed files: %d\n", failures.Len())
+		// the most recent failures fit on screen
+		for i := max(failures.Len()-maxFailuresShown, 0); i < failures.Len(); i++ {
+			failure := failures.At(i)
+			file, _ := failure.File()
+			reason, _ := failure.Error()
+			view += fmt.Sprintf("  %s after %d attempts: %s\n", file, failure.Attempts(), reason)
+		}
+	}
+	unverified, err := progress.UnverifiedFiles()
+	if err == nil && unverified.Len() > 0 {
+		view += fmt.Sprintf("\ninstalled without a manifest, not verified: %d\n", unverified.Len())
+		for i := max(unverified.Len()-maxFailuresShown, 0); i < unverified.Len(); i++ {
+			file, _ := unverified.At(i)
+			view += fmt.Sprintf("  %s\n", file)
+		}
+	}
+	return docStyle.Render(view)
+}
+
+const (
+	progressBarWidth = 40
+	maxFailuresShown = 5
+)
+
+func progressBar(fraction float64) string {
+	filled := int(min(max(fraction, 0), 1) * progressBarWidth)
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", progressBarWidth-filled) + "]"
 }
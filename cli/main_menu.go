@@ -22,6 +22,7 @@ const (
 	showDownload
 	showOutput
 	showDownloadProgress
+	showManageMaps
 )
 
 var docStyle = lipgloss.NewStyle().Margin(1, 2)
@@ -41,6 +42,7 @@ type uiModel struct {
 	output            outputModel
 	download          downloadModel
 	downloadProgress  downloadProgressModel
+	manageMaps        manageMapsModel
 	pub               *cereal.Publisher[custom.MapdIn]
 	sub               *cereal.Subscriber[custom.MapdOut]
 	extendedSub       *cereal.Subscriber[custom.MapdExtendedOut]
@@ -50,6 +52,7 @@ type uiModel struct {
 type item struct {
 	title, desc string
 	state       mainState
+	input       *custom.MapdInputType // sent to mapd when the item is selected
 }
 
 func (i item) Title() string       { return i.title }
@@ -61,6 +64,9 @@ func initialModel() uiModel {
 		item{title: "Settings", desc: "Modify settings of an active instance of mapd", state: showSettings},
 		item{title: "Download", desc: "Trigger a download of maps in an active instance of mapd", state: showDownload},
 		item{title: "Download Progress", desc: "Watch the live download progress from mapd", state: showDownloadProgress},
+		item{title: "Check For Map Updates", desc: "Check whether newer map data is available for installed regions", state: showDownloadProgress, input: inputType(custom.MapdInputType_checkForUpdates)},
+		item{title: "Update Maps", desc: "Download newer map data for installed regions", state: showDownloadProgress, input: inputType(custom.MapdInputType_updateMaps)},
+		item{title: "Manage Maps", desc: "See installed regions and delete the ones you no longer need", state: showManageMaps},
 		item{title: "Watch", desc: "Watch the live output from mapd", state: showOutput},
 	}
 
@@ -68,11 +74,24 @@ func initialModel() uiModel {
 	pub := cereal.NewPublisher("mapdCli", cereal.MapdInCreator)
 	sub := cereal.NewSubscriber("mapdOut", cereal.MapdOutReader, true, false)
 	extendedSub := cereal.NewSubscriber("mapdExtendedOut", cereal.MapdExtendedOutReader, true, false)
-	m := uiModel{list: list.New(items, listDelegate, 0, 0), settings: getSettingsModel(), pub: &pub, sub: &sub, extendedSub: &extendedSub, download: getDownloadModel()}
+	m := uiModel{list: list.New(items, listDelegate, 0, 0), settings: getSettingsModel(), pub: &pub, sub: &sub, extendedSub: &extendedSub, download: getDownloadModel(), manageMaps: getManageMapsModel()}
 	m.list.Title = "Mapd Actions"
 	return m
 }
 
+func inputType(t custom.MapdInputType) *custom.MapdInputType {
+	return &t
+}
+
+func (m uiModel) sendInput(t custom.MapdInputType) {
+	msg, input := m.pub.NewMessage(true)
+	input.SetType(t)
+	err := m.pub.Send(msg)
+	if err != nil {
+		panic(err)
+	}
+}
+
 func (m uiModel) Init() tea.Cmd {
 	// Just return `nil`, which means "no I/O right now, please."
 	return tickEvery()
@@ -86,6 +105,9 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		if msg.Type == tea.KeyEnter && m.state == showMenu && m.list.FilterState() != list.Filtering {
 			it := m.list.SelectedItem().(item)
+			if it.input != nil {
+				m.sendInput(*it.input)
+			}
 			m.state = it.state
 			return m, nil
 		}
@@ -96,6 +118,7 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.download, _ = m.download.Update(msg, &m)
 		m.output, _ = m.output.Update(msg, &m)
 		m.downloadProgress, _ = m.downloadProgress.Update(msg, &m)
+		m.manageMaps, _ = m.manageMaps.Update(msg, &m)
 	case TickMsg:
 		extendedData, success := m.extendedSub.Read()
 		if success {
@@ -107,6 +130,8 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.output, _ = m.output.Update(msg, &m)
 		m.downloadProgress, _ = m.downloadProgress.Update(msg, &m)
 		m.settings, _ = m.settings.Update(msg, &m)
+		m.manageMaps, _ = m.manageMaps.Update(msg, &m)
+		m.download, _ = m.download.Update(msg, &m)
 		return m, tickEvery()
 	}
 
@@ -120,6 +145,8 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.download, cmd = m.download.Update(msg, &m)
 	case showDownloadProgress:
 		m.downloadProgress, cmd = m.downloadProgress.Update(msg, &m)
+	case showManageMaps:
+		m.manageMaps, cmd = m.manageMaps.Update(msg, &m)
 	default:
 		m.list, cmd = m.list.Update(msg)
 	}
@@ -136,6 +163,8 @@ func (m uiModel) View() string {
 		return m.download.View()
 	case showDownloadProgress:
 		return m.downloadProgress.View()
+	case showManageMaps:
+		return m.manageMaps.View()
 	}
 	return docStyle.Render(m.list.View())
 }
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"pfeifer.dev/mapd/cereal/custom"
+)
+
+type manageMapsState int
+
+const (
+	showInstalledRegions manageMapsState = iota
+	confirmDeleteRegion
+)
+
+type manageMapsModel struct {
+	list     list.Model
+	state    manageMapsState
+	selected regionItem
+	loaded   string // key of the regions currently in the list
+}
+
+type regionItem struct {
+	title, desc string
+	name        string
+	exit        bool
+}
+
+func (i regionItem) Title() string       { return i.title }
+func (i regionItem) Description() string { return i.desc }
+func (i regionItem) FilterValue() string { return i.title }
+
+var exitRegionItem = regionItem{title: "Back", desc: "Return to the main menu", exit: true}
+
+func getManageMapsModel() manageMapsModel {
+	m := manageMapsModel{list: list.New([]list.Item{exitRegionItem}, list.NewDefaultDelegate(), 0, 0)}
+	m.list.Title = "Installed Maps"
+	return m
+}
+
+func formatBytes(size uint64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func regionItems(out custom.MapdExtendedOut) (items []list.Item, key string) {
+	regions, err := out.InstalledRegions()
+	if err != nil {
+		return []list.Item{exitRegionItem}, ""
+	}
+	for i := range regions.Len() {
+		region := regions.At(i)
+		name, _ := region.Name()
+		fullName, _ := region.FullName()
+		version, _ := region.DataVersion()
+		if version == "" {
+			version = "unknown"
+		}
+		title := fullName
+		if fullName != name {
+			title = fmt.Sprintf("%s (%s)", fullName, name)
+		}
+		desc := fmt.Sprintf("%d files, %s, data version %s, installed %s",
+			region.Files(),
+			formatBytes(region.SizeBytes()),
+			version,
+			time.Unix(region.InstalledAt(), 0).Format(time.DateOnly),
+		)
+		items = append(items, regionItem{title: title, desc: desc, name: name})
+		key += title + desc + "\n"
+	}
+	return append(items, exitRegionItem), key
+}
+
+func (m manageMapsModel) Update(msg tea.Msg, mm *uiModel) (manageMapsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case TickMsg:
+		if m.state == showInstalledRegions && mm.extendedDataValid && m.list.FilterState() == list.Unfiltered {
+			items, key := regionItems(mm.extendedData)
+			size := formatBytes(mm.extendedData.InstalledSizeBytes())
+			title := fmt.Sprintf("Installed Maps (%s)", size)
+			if quota := mm.extendedData.DiskQuotaBytes(); quota > 0 {
+				title = fmt.Sprintf("Installed Maps (%s of %s)", size, formatBytes(quota))
+			}
+			key += title
+			if key != m.loaded {
+				m.loaded = key
+				m.list.SetItems(items)
+				m.list.Title = title
+			}
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyEnter && m.state == showInstalledRegions && m.list.FilterState() != list.Filtering {
+			it, ok := m.list.SelectedItem().(regionItem)
+			if !ok {
+				return m, nil
+			}
+			if it.exit {
+				mm.state = showMenu
+				return m, nil
+			}
+			m.selected = it
+			m.state = confirmDeleteRegion
+			m.list.Title = "Delete " + it.title + "? Files shared with other regions are kept"
+			m.list.SetItems(boolList)
+			m.list.ResetSelected()
+			return m, nil
+		} else if msg.Type == tea.KeyEnter && m.state == confirmDeleteRegion && m.list.FilterState() != list.Filtering {
+			if m.list.SelectedItem().(settingsItem).title == "Yes" {
+				msg, input := mm.pub.NewMessage(true)
+				input.SetType(custom.MapdInputType_deleteRegion)
+				err := input.SetStr(m.selected.name)
+				if err != nil {
+					panic(err)
+				}
+				err = mm.pub.Send(msg)
+				if err != nil {
+					panic(err)
+				}
+			}
+			m.state = showInstalledRegions
+			m.loaded = ""
+			m.list.Title = "Installed Maps"
+			m.list.SetItems([]list.Item{exitRegionItem})
+			m.list.ResetSelected()
+			return m, nil
+		}
+	case tea.WindowSizeMsg:
+		h, v := docStyle.GetFrameSize()
+		m.list.SetSize(msg.Width-h, msg.Height-v)
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m manageMapsModel) View() string {
+	return docStyle.Render(m.list.View())
+}
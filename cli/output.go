@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"math"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"pfeifer.dev/mapd/cereal/custom"
@@ -61,7 +62,7 @@ func (m outputModel) View() string {
 	}
 	latRange := maxLat - minLat
 	lonRange := maxLon - minLon
-	gHeight := m.height - 15
+	gHeight := m.height - 16
 	gWidth := m.width
 	if gHeight < gWidth/2 {
 		gWidth = gHeight * 2
@@ -118,7 +119,7 @@ func (m outputModel) View() string {
 
 	roadname, _ := m.output.RoadName()
 	return docStyle.Render(fmt.Sprintf(
-		"name: %s\nsuggested speed: %f\nspeed limit: %f\nspeed limit suggested speed: %f\nnext speed limit: %f\nnext speed limit distance: %f\nvision curve speed: %f\nmap curve speed: %f\ndistance from center: %f\nlanes: %d\nhighway class: %s\nselection type: %s\nloop rate average: %f\nloop rate min: %f\n\n%s",
+		"name: %s\nsuggested speed: %f\nspeed limit: %f\nspeed limit suggested speed: %f\nnext speed limit: %f\nnext speed limit distance: %f\nvision curve speed: %f\nmap curve speed: %f\ndistance from center: %f\nlanes: %d\nhighway class: %s\nselection type: %s\nloop rate average: %f\nloop rate min: %f\nmap data date: %s\n\n%s",
 		roadname,
 		m.output.SuggestedSpeed(),
 		m.output.SpeedLimit(),
@@ -133,6 +134,16 @@ func (m outputModel) View() string {
 		m.output.WaySelectionType().String(),
 		m.extendedOutput.LoopRateAverage(),
 		m.extendedOutput.LoopRateMin(),
+		mapDataDate(m.extendedOutput),
 		string(grid),
 	) + "\n")
 }
+
+// mapDataDate is the replication date of the loaded map data.
+func mapDataDate(out custom.MapdExtendedOut) string {
+	mapData, err := out.MapData()
+	if err != nil || mapData.Date() == 0 {
+		return "unknown"
+	}
+	return time.Unix(mapData.Date(), 0).UTC().Format(time.DateOnly)
+}
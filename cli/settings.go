@@ -195,6 +195,59 @@ var settingsList = []list.Item{
 		jsonPath:    "default_lane_width",
 		value:       func() string { return fmt.Sprintf("%f meters", ms.Settings.DefaultLaneWidth) },
 	},
+	settingsItem{
+		title:       "Live Download Maps",
+		desc:        "When enabled mapd downloads missing map data for the area it is driving in",
+		MessageType: custom.MapdInputType_setJsonPathBool,
+		Type:        Bool,
+		state:       settingsInput,
+		jsonPath:    "live_download_enabled",
+		value:       func() string { return fmt.Sprintf("%t", ms.Settings.LiveDownloadEnabled) },
+	},
+	settingsItem{
+		title:       "Pause Downloads On Metered Connection",
+		desc:        "When enabled map downloads wait while the connection is metered and continue once it is not",
+		MessageType: custom.MapdInputType_setJsonPathBool,
+		Type:        Bool,
+		state:       settingsInput,
+		jsonPath:    "download.pause_on_metered",
+		value:       func() string { return fmt.Sprintf("%t", ms.Settings.DownloadSettings.PauseOnMetered) },
+	},
+	settingsItem{
+		title:       "Map Sources",
+		desc:        "Comma separated priority list of map servers, file:// urls or directories to download map data from",
+		MessageType: custom.MapdInputType_setMapSources,
+		Type:        String,
+		state:       settingsInput,
+		value:       func() string { return strings.Join(ms.Settings.DownloadSettings.Sources, ",") },
+	},
+	settingsItem{
+		title:       "Map Disk Quota (MB)",
+		desc:        "Maximum disk space for offline map data. The least recently driven areas are removed to make room. 0 is no limit",
+		MessageType: custom.MapdInputType_setJsonPathFloat,
+		Type:        Float,
+		state:       settingsInput,
+		jsonPath:    "download.disk_quota_mb",
+		value:       func() string { return fmt.Sprintf("%f", ms.Settings.DownloadSettings.DiskQuotaMB) },
+	},
+	settingsItem{
+		title:       "Parallel Map Downloads",
+		desc:        "How many map files are downloaded at the same time",
+		MessageType: custom.MapdInputType_setJsonPathFloat,
+		Type:        Float,
+		state:       settingsInput,
+		jsonPath:    "download.workers",
+		value:       func() string { return fmt.Sprintf("%d", ms.Settings.DownloadSettings.Workers) },
+	},
+	settingsItem{
+		title:       "Map Download Bandwidth Limit (Mbit/s)",
+		desc:        "Maximum bandwidth used by all map downloads together. 0 is no limit",
+		MessageType: custom.MapdInputType_setJsonPathFloat,
+		Type:        Float,
+		state:       settingsInput,
+		jsonPath:    "download.bandwidth_limit_mbps",
+		value:       func() string { return fmt.Sprintf("%f", ms.Settings.DownloadSettings.BandwidthMbps) },
+	},
 	settingsItem{
 		title: "Personalities",
 		desc:  "Configure per-personality settings for relaxed, standard, and aggressive driving",
@@ -8,6 +8,7 @@ import (
 	"pfeifer.dev/mapd/cereal"
 	"pfeifer.dev/mapd/cereal/custom"
 	m "pfeifer.dev/mapd/math"
+	"pfeifer.dev/mapd/params"
 	ms "pfeifer.dev/mapd/settings"
 	"pfeifer.dev/mapd/utils"
 )
@@ -16,8 +17,11 @@ type ExtendedState struct {
 	DownloadProgress ms.DownloadProgress
 	Pub              cereal.Publisher[custom.MapdExtendedOut]
 	LoopRate         utils.LoopRateTracker
+	Inventory        ms.InventoryCache
 	lastSend         time.Time
 	state            *State
+	nearbyRegions    []ms.NearbyRegion
+	lastRegionCheck  time.Time
 }
 
 func (s *ExtendedState) Send() error {
@@ -29,6 +33,9 @@ func (s *ExtendedState) Send() error {
 		s.setPath(out)
 		s.setPosition(out)
 		s.setLoopRate(out)
+		s.setInventory(out)
+		s.setNearbyRegions(out)
+		s.setMapData(out)
 		s.Pub.Publish(msg)
 		return nil
 	}
@@ -132,6 +139,102 @@ func (s *ExtendedState) setSettings(out custom.MapdExtendedOut) {
 	}
 }
 
+func (s *ExtendedState) setInventory(out custom.MapdExtendedOut) {
+	inventory := s.Inventory.Get(params.GetBaseOpPath())
+	summaries := inventory.Summary()
+	regions, err := out.NewInstalledRegions(int32(len(summaries)))
+	if err != nil {
+		slog.Warn("failed to create installed regions in extended state", "error", err)
+		return
+	}
+	for i, summary := range summaries {
+		r := regions.At(i)
+		if err := r.SetName(summary.Name); err != nil {
+			slog.Warn("failed to set installed region name", "error", err)
+		}
+		if err := r.SetFullName(summary.FullName); err != nil {
+			slog.Warn("failed to set installed region full name", "error", err)
+		}
+		if err := r.SetDataVersion(summary.DataVersion); err != nil {
+			slog.Warn("failed to set installed region data version", "error", err)
+		}
+		r.SetFiles(uint32(summary.Groups))
+		r.SetSizeBytes(uint64(summary.Size))
+		r.SetInstalledAt(summary.InstalledAt.Unix())
+	}
+	out.SetInstalledSizeBytes(uint64(inventory.Size()))
+
+	quota := ms.OfflineDiskQuota()
+	out.SetDiskQuotaBytes(uint64(quota.Limit()))
+	evictions := quota.Evictions()
+	recent, err := out.NewEvictions(int32(len(evictions)))
+	if err != nil {
+		slog.Warn("failed to create evictions in extended state", "error", err)
+		return
+	}
+	for i, eviction := range evictions {
+		e := recent.At(i)
+		if err := e.SetFile(eviction.File); err != nil {
+			slog.Warn("failed to set evicted file", "error", err)
+		}
+		e.SetSizeBytes(uint64(eviction.Size))
+		e.SetEvictedAt(eviction.EvictedAt.Unix())
+	}
+}
+
+func (s *ExtendedState) setMapData(out custom.MapdExtendedOut) {
+	if !s.state.Data.Loaded {
+		return
+	}
+	header := s.state.Data.Header()
+	mapData, err := out.NewMapData()
+	if err != nil {
+		slog.Warn("failed to create map data in extended state", "error", err)
+		return
+	}
+	if !header.DataTimestamp.IsZero() {
+		mapData.SetDate(header.DataTimestamp.Unix())
+	}
+	mapData.SetFormatVersion(s.state.Data.Format())
+	if err := mapData.SetGeneratorVersion(header.GeneratorVersion); err != nil {
+		slog.Warn("failed to set map generator version", "error", err)
+	}
+	if err := mapData.SetSourceHash(header.SourceHash); err != nil {
+		slog.Warn("failed to set map source hash", "error", err)
+	}
+}
+
+func (s *ExtendedState) setNearbyRegions(out custom.MapdExtendedOut) {
+	if time.Since(s.lastRegionCheck) >= ms.NEARBY_REGIONS_INTERVAL {
+		s.lastRegionCheck = time.Now()
+		lat, lon, err := ms.Settings.LastPosition()
+		if err != nil {
+			slog.Debug("no position to find the regions around the vehicle", "error", err)
+			s.nearbyRegions = nil
+		} else {
+			inventory := s.Inventory.Get(params.GetBaseOpPath())
+			s.nearbyRegions = ms.RegionsAt(ms.GetDownloadMenu(), inventory, lat, lon)
+		}
+	}
+	regions, err := out.NewNearbyRegions(int32(len(s.nearbyRegions)))
+	if err != nil {
+		slog.Warn("failed to create nearby regions in extended state", "error", err)
+		return
+	}
+	for i, region := range s.nearbyRegions {
+		r := regions.At(i)
+		if err := r.SetName(region.Path); err != nil {
+			slog.Warn("failed to set nearby region name", "error", err)
+		}
+		if err := r.SetFullName(region.FullName); err != nil {
+			slog.Warn("failed to set nearby region full name", "error", err)
+		}
+		r.SetFiles(uint32(region.Files))
+		r.SetInstalledFiles(uint32(region.InstalledFiles))
+		r.SetInstalled(region.Installed())
+	}
+}
+
 func (s *ExtendedState) setDownloadProgress(out custom.MapdExtendedOut) {
 	p, err := out.NewDownloadProgress()
 	if err != nil {
@@ -141,6 +244,48 @@ func (s *ExtendedState) setDownloadProgress(out custom.MapdExtendedOut) {
 	p.SetCancelled(s.DownloadProgress.Canceled)
 	p.SetTotalFiles(uint32(s.DownloadProgress.TotalFiles))
 	p.SetDownloadedFiles(uint32(s.DownloadProgress.DownloadedFiles))
+	p.SetUpdateCheck(s.DownloadProgress.UpdateCheck)
+	p.SetUpdatesAvailable(uint32(s.DownloadProgress.UpdatesAvailable))
+	p.SetPaused(s.DownloadProgress.Paused)
+	p.SetDownloadedBytes(uint64(s.DownloadProgress.DownloadedBytes))
+	p.SetBytesPerSecond(float32(s.DownloadProgress.BytesPerSecond))
+	p.SetEtaSeconds(int32(s.DownloadProgress.EtaSeconds))
+	p.SetCurrentFileReceived(uint64(s.DownloadProgress.CurrentFileReceived))
+	p.SetCurrentFileSize(uint64(s.DownloadProgress.CurrentFileSize))
+	err = p.SetCurrentFile(s.DownloadProgress.CurrentFile)
+	if err != nil {
+		panic(err)
+	}
+	failures, err := p.NewFailedFiles(int32(len(s.DownloadProgress.FailedFiles)))
+	if err != nil {
+		panic(err)
+	}
+	for i, failure := range s.DownloadProgress.FailedFiles {
+		f := failures.At(i)
+		err := f.SetFile(failure.File)
+		if err != nil {
+			panic(err)
+		}
+		err = f.SetLocation(failure.Location)
+		if err != nil {
+			panic(err)
+		}
+		err = f.SetError(failure.Error)
+		if err != nil {
+			panic(err)
+		}
+		f.SetAttempts(uint32(failure.Attempts))
+	}
+	unverified, err := p.NewUnverifiedFiles(int32(len(s.DownloadProgress.UnverifiedFiles)))
+	if err != nil {
+		panic(err)
+	}
+	for i, file := range s.DownloadProgress.UnverifiedFiles {
+		err := unverified.Set(i, file)
+		if err != nil {
+			panic(err)
+		}
+	}
 	l, err := p.NewLocations(int32(len(s.DownloadProgress.LocationsToDownload)))
 	if err != nil {
 		panic(err)
@@ -13,6 +13,7 @@ require (
 	github.com/pfeiferj/gomsgq v0.1.11
 	github.com/pkg/errors v0.9.1
 	github.com/urfave/cli/v3 v3.5.0
+	golang.org/x/sys v0.37.0
 )
 
 require (
@@ -39,7 +40,6 @@ require (
 	github.com/sahilm/fuzzy v0.1.1 // indirect
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
 	golang.org/x/sync v0.11.0 // indirect
-	golang.org/x/sys v0.37.0 // indirect
 	golang.org/x/text v0.3.8 // indirect
 	google.golang.org/protobuf v1.27.1 // indirect
 )
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"time"
+
+	"pfeifer.dev/mapd/cereal/log"
+	"pfeifer.dev/mapd/maps"
+	m "pfeifer.dev/mapd/math"
+	ms "pfeifer.dev/mapd/settings"
+)
+
+// UpcomingMaps remembers the group the look ahead point was last checked in,
+// so the installed map data is only looked up again once the point moves into
+// another group or a live download of the group could be retried.
+type UpcomingMaps struct {
+	group     string
+	checkedAt time.Time
+}
+
+// Request asks for a live download of the map data the vehicle is heading
+// into when it is not installed yet.
+func (u *UpcomingMaps) Request(location log.GpsLocationData, data *maps.Offline) {
+	if !ms.Settings.LiveDownloadEnabled {
+		return
+	}
+	pos := m.PosFromLocation(location)
+	lookahead := max(float64(location.Speed())*ms.LIVE_DOWNLOAD_LOOKAHEAD_TIME.Seconds(), ms.LIVE_DOWNLOAD_MIN_LOOKAHEAD)
+	ahead := pos.Project(lookahead, float64(location.BearingDeg()))
+	box := data.Box()
+	if box.PosInside(ahead) {
+		return
+	}
+	group := ms.GroupForPosition(ahead.Lat(), ahead.Lon())
+	if group == u.group && time.Since(u.checkedAt) < ms.LIVE_DOWNLOAD_RETRY_DELAY {
+		return
+	}
+	u.group = group
+	u.checkedAt = time.Now()
+	if maps.BoundsFileExists(ahead) {
+		return
+	}
+	ms.Settings.RequestLiveDownload(ahead.Lat(), ahead.Lon())
+}
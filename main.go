@@ -2,6 +2,7 @@ package main
 
 import (
 	"log/slog"
+	"os"
 	"time"
 
 	"github.com/pkg/errors"
@@ -27,6 +28,7 @@ func main() {
 	state := State{}
 	state.Init()
 	var lastMapLoadAttempt time.Time
+	reloadMaps := false
 
 	extendedState := ExtendedState{
 		Pub:   cereal.NewPublisher("mapdExtendedOut", cereal.MapdExtendedOutCreator),
@@ -95,6 +97,9 @@ func main() {
 		if success {
 			extendedState.DownloadProgress = progress
 		}
+		if ms.Settings.LiveDownloadInstalled() {
+			reloadMaps = true
+		}
 
 		carData, carStateSuccess := car.Read()
 		if carStateSuccess {
@@ -116,19 +121,30 @@ func main() {
 		if gpsSuccess {
 			state.DistanceSinceLastPosition = 0
 			state.Position = m.PosFromLocation(location)
+			state.Altitude.Update(location)
+			ms.Settings.SetLastPosition(location.Latitude(), location.Longitude())
 			pos := m.PosFromLocation(location)
 			box := state.Data.Box()
 			mapLoadTime := time.Now()
-			if !box.PosInside(pos) || (!state.Data.Loaded && mapLoadTime.Sub(lastMapLoadAttempt) >= mapLoadRetryDelay) {
-				state.Data, err = maps.FindWaysAroundPosition(pos)
+			if !box.PosInside(pos) || reloadMaps || (!state.Data.Loaded && mapLoadTime.Sub(lastMapLoadAttempt) >= mapLoadRetryDelay) {
+				var data maps.Offline
+				data, err = maps.FindWaysAroundPosition(pos)
+				state.SwapData(data)
 				lastMapLoadAttempt = mapLoadTime
+				reloadMaps = false
+				if errors.Is(err, os.ErrNotExist) && ms.Settings.LiveDownloadEnabled {
+					ms.Settings.RequestLiveDownload(pos.Lat(), pos.Lon())
+				}
 				if err != nil {
 					slog.Debug("", "error", errors.Wrap(err, "Could not find ways around location"))
 					continue
 				}
+				ms.RecordMapAccess(pos.Lat(), pos.Lon())
 			}
 
-			state.CurrentWay, err = GetCurrentWay(state.CurrentWay, state.NextWays, &state.Data, location)
+			state.UpcomingMaps.Request(location, &state.Data)
+
+			state.CurrentWay, err = GetCurrentWay(state.CurrentWay, state.NextWays, &state.Data, location, &state.Altitude)
 			if err != nil {
 				slog.Debug("could not get current way", "error", err)
 			}
@@ -137,6 +153,7 @@ func main() {
 			if err != nil {
 				slog.Debug("could not get next way", "error", err)
 			}
+			state.CloseRetiredData()
 
 			state.Curvatures, err = GetStateCurvatures(&state)
 			if err != nil {
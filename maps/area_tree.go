@@ -0,0 +1,244 @@
+package maps
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	m "pfeifer.dev/mapd/math"
+)
+
+// Areas with more ways than the way budget are split into quarters until the
+// quarters fit, at most MAX_SPLIT_DEPTH times. Every group directory keeps a
+// tile index of the areas that were split so mapd can find the file of a
+// position. Areas that were not split keep their single file.
+const (
+	MAX_SPLIT_DEPTH = 4
+	TILE_INDEX_FILE = "index"
+	tileIndexMagic  = "MAPDTIX1"
+)
+
+// quarter returns one quarter of a box, 0 is the south west quarter, 1 the
+// south east, 2 the north west and 3 the north east.
+func quarter(box m.Box, quadrant uint8) m.Box {
+	midLat := (box.MinPos.Lat() + box.MaxPos.Lat()) / 2
+	midLon := (box.MinPos.Lon() + box.MaxPos.Lon()) / 2
+	minLat, maxLat := box.MinPos.Lat(), midLat
+	if quadrant >= 2 {
+		minLat, maxLat = midLat, box.MaxPos.Lat()
+	}
+	minLon, maxLon := box.MinPos.Lon(), midLon
+	if quadrant%2 == 1 {
+		minLon, maxLon = midLon, box.MaxPos.Lon()
+	}
+	return m.Box{MinPos: m.NewPosition(minLat, minLon), MaxPos: m.NewPosition(maxLat, maxLon)}
+}
+
+// leafBox is the box of the quarters along path inside box.
+func leafBox(box m.Box, path []uint8) m.Box {
+	for _, quadrant := range path {
+		box = quarter(box, quadrant)
+	}
+	return box
+}
+
+// areaLeaf is a file of a split area with the quarters that lead to it.
+type areaLeaf struct {
+	Area
+	path []uint8
+}
+
+// splitArea splits an area into quarters until every leaf holds at most
+// s.WaysPerFile ways. An area within the budget is its only leaf.
+func splitArea(area Area, s OfflineSettings) []areaLeaf {
+	leaves := []areaLeaf{}
+	var split func(area Area, path []uint8)
+	split = func(area Area, path []uint8) {
+		if s.WaysPerFile <= 0 || len(area.Ways) <= s.WaysPerFile || len(path) >= MAX_SPLIT_DEPTH {
+			leaves = append(leaves, areaLeaf{Area: area, path: path})
+			return
+		}
+		for quadrant := range uint8(4) {
+			q := Area{Box: quarter(area.Box, quadrant)}
+			overlapBox := q.OverlapBox(s.Overlap)
+			for _, way := range area.Ways {
+				if way.Box.Overlapping(overlapBox) {
+					q.Ways = append(q.Ways, way)
+				}
+			}
+			split(q, append(slices.Clone(path), quadrant))
+		}
+	}
+	split(area, nil)
+	return leaves
+}
+
+// tileIndex holds the leaves of the split areas of a group by area index.
+type tileIndex map[int][][]uint8
+
+func tileIndexPath(box m.Box, s OfflineSettings) string {
+	return filepath.Join(groupDirectory(box, s), TILE_INDEX_FILE)
+}
+
+// readTileIndex reads the tile index of a group, groups without one have no
+// split areas.
+func readTileIndex(path string) (tileIndex, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tileIndex{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read tile index")
+	}
+	if len(data) < len(tileIndexMagic) || string(data[:len(tileIndexMagic)]) != tileIndexMagic {
+		return nil, errors.New("tile index has an unknown format")
+	}
+	data = data[len(tileIndexMagic):]
+	next := func() uint64 {
+		value, n := binary.Uvarint(data)
+		if n <= 0 {
+			err = errors.New("tile index is truncated")
+			data = nil
+			return 0
+		}
+		data = data[n:]
+		return value
+	}
+	index := tileIndex{}
+	areas := next()
+	for range areas {
+		area := int(next())
+		leaves := next()
+		for range leaves {
+			depth := next()
+			if err != nil || depth > MAX_SPLIT_DEPTH || uint64(len(data)) < depth {
+				return nil, errors.New("tile index is truncated")
+			}
+			index[area] = append(index[area], slices.Clone(data[:depth]))
+			data = data[depth:]
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// loadedTileIndex is a tile index read by the loader along with the file it was
+// read from, so an index replaced by a download is read again.
+type loadedTileIndex struct {
+	tiles   tileIndex
+	size    int64
+	modTime time.Time
+}
+
+var (
+	loadedTileIndexes     = map[string]loadedTileIndex{}
+	loadedTileIndexesLock sync.Mutex
+)
+
+// loadTileIndex returns the tile index at path without reading it again while
+// the file is unchanged.
+func loadTileIndex(path string) (tileIndex, error) {
+	info, err := os.Stat(path)
+	loadedTileIndexesLock.Lock()
+	defer loadedTileIndexesLock.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		delete(loadedTileIndexes, path)
+		return tileIndex{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not stat tile index")
+	}
+	if loaded, ok := loadedTileIndexes[path]; ok && loaded.size == info.Size() && loaded.modTime.Equal(info.ModTime()) {
+		return loaded.tiles, nil
+	}
+	tiles, err := readTileIndex(path)
+	if err != nil {
+		return nil, err
+	}
+	loadedTileIndexes[path] = loadedTileIndex{tiles: tiles, size: info.Size(), modTime: info.ModTime()}
+	return tiles, nil
+}
+
+// write stores the index of a group, or removes it when no area is split.
+func (t tileIndex) write(path string) error {
+	if len(t) == 0 {
+		err := os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return errors.Wrap(err, "could not remove tile index")
+	}
+	areas := make([]int, 0, len(t))
+	for area := range t {
+		areas = append(areas, area)
+	}
+	slices.Sort(areas)
+	buf := []byte(tileIndexMagic)
+	buf = binary.AppendUvarint(buf, uint64(len(areas)))
+	for _, area := range areas {
+		buf = binary.AppendUvarint(buf, uint64(area))
+		buf = binary.AppendUvarint(buf, uint64(len(t[area])))
+		for _, path := range t[area] {
+			buf = binary.AppendUvarint(buf, uint64(len(path)))
+			buf = append(buf, path...)
+		}
+	}
+	tmpPath := path + ".tmp"
+	err := os.WriteFile(tmpPath, buf, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "could not write tile index")
+	}
+	return errors.Wrap(os.Rename(tmpPath, path), "could not replace tile index")
+}
+
+// leaf returns the file of an area that covers pos.
+func (t tileIndex) leaf(area int, pos m.Position) Area {
+	box := areaBox(area/longitudeAreas, area%longitudeAreas)
+	for _, path := range t[area] {
+		leaf := leafBox(box, path)
+		if leaf.PosInside(pos) {
+			return Area{Box: leaf}
+		}
+	}
+	return Area{Box: box}
+}
+
+// updateTileIndex records the leaves an area was just written to and removes
+// the files of an earlier split of the area that were not written again.
+func updateTileIndex(index tileIndex, area int, leaves []areaLeaf, s OfflineSettings) error {
+	box := areaBox(area/longitudeAreas, area%longitudeAreas)
+	written := map[string]bool{}
+	for _, leaf := range leaves {
+		written[GenerateBoundsFileName(leaf.Area, s)] = true
+	}
+	stale := []m.Box{box}
+	for _, path := range index[area] {
+		stale = append(stale, leafBox(box, path))
+	}
+	for _, staleBox := range stale {
+		name := GenerateBoundsFileName(Area{Box: staleBox}, s)
+		if written[name] {
+			continue
+		}
+		err := os.Remove(name)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return errors.Wrap(err, "could not remove stale offline file")
+		}
+	}
+
+	if len(leaves) == 1 && len(leaves[0].path) == 0 {
+		delete(index, area)
+		return nil
+	}
+	index[area] = nil
+	for _, leaf := range leaves {
+		index[area] = append(index[area], leaf.path)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package maps
+
+import (
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/paulmach/osm"
+	m "pfeifer.dev/mapd/math"
+)
+
+// denseWays places short ways in the south west corner of an area, so only
+// that corner needs to be split.
+func denseWays(count int) []*osm.Way {
+	rng := rand.New(rand.NewSource(1))
+	ways := make([]*osm.Way, 0, count)
+	for i := range count {
+		lat := 40 + rng.Float64()*0.1
+		lon := -84 + rng.Float64()*0.1
+		ways = append(ways, osmWay(osm.WayID(i+1), "Dense",
+			osm.WayNode{ID: osm.NodeID(2 * i), Lat: lat, Lon: lon},
+			osm.WayNode{ID: osm.NodeID(2*i + 1), Lat: lat + 0.001, Lon: lon + 0.001},
+		))
+	}
+	return ways
+}
+
+func TestDenseAreasAreSplit(t *testing.T) {
+	ways := denseWays(400)
+	settings := OfflineSettings{
+		Box:             m.Box{MinPos: m.NewPosition(40, -84), MaxPos: m.NewPosition(40.25, -83.75)},
+		Overlap:         0.001,
+		OutputDirectory: t.TempDir(),
+		TmpDirectory:    t.TempDir(),
+		WaysPerFile:     100,
+	}
+	if err := generate(settings, scanWays(ways)); err != nil {
+		t.Fatal(err)
+	}
+	area := Area{Box: settings.Box}
+	if _, err := os.Stat(GenerateBoundsFileName(area, settings)); !os.IsNotExist(err) {
+		t.Error("expected the file of the split area to be left out")
+	}
+	tiles, err := readTileIndex(tileIndexPath(settings.Box, settings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, _ := areaForPosition(m.NewPosition(40.1, -83.9))
+	if len(tiles[index]) <= 4 {
+		t.Fatalf("expected the dense corner to be split further, got %d leaves", len(tiles[index]))
+	}
+
+	for _, path := range tiles[index] {
+		leaf := Area{Box: leafBox(settings.Box, path)}
+		data, err := os.ReadFile(GenerateBoundsFileName(leaf, settings))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if o := ReadOffline(data); o.Ways.Len() > settings.WaysPerFile && len(path) < MAX_SPLIT_DEPTH {
+			t.Errorf("leaf %v holds %d ways", leaf.Box, o.Ways.Len())
+		}
+	}
+	for _, way := range ways {
+		pos := m.NewPosition(way.Nodes[0].Lat, way.Nodes[0].Lon)
+		leaf := tiles.leaf(index, pos)
+		data, err := os.ReadFile(GenerateBoundsFileName(leaf, settings))
+		if err != nil {
+			t.Fatal(err)
+		}
+		o := ReadOffline(data)
+		found := false
+		for i := range o.Ways.Len() {
+			w := o.Ways.At(i)
+			found = found || w.Id() == int64(way.ID)
+		}
+		if !found {
+			t.Fatalf("way %d is not in the file covering its first node", way.ID)
+		}
+	}
+
+	// generating without splitting replaces the leaves with a single file
+	settings.WaysPerFile = 0
+	if err := generate(settings, scanWays(ways)); err != nil {
+		t.Fatal(err)
+	}
+	files := readOutput(t, settings.OutputDirectory)
+	if len(files) != 2 {
+		t.Errorf("expected the area file and the way index, got %d files", len(files))
+	}
+	if _, err := os.Stat(GenerateBoundsFileName(area, settings)); err != nil {
+		t.Error("expected the file of the area")
+	}
+}
+
+func TestTileIndexRoundTrip(t *testing.T) {
+	path := t.TempDir() + "/" + TILE_INDEX_FILE
+	tiles := tileIndex{7: {{0, 1}, {0, 2}, {1}, {2}, {3}, {0, 0}, {0, 3}}}
+	if err := tiles.write(path); err != nil {
+		t.Fatal(err)
+	}
+	read, err := readTileIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read[7]) != len(tiles[7]) {
+		t.Fatalf("expected %d leaves, got %d", len(tiles[7]), len(read[7]))
+	}
+	if err := (tileIndex{}).write(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected an empty index to be removed")
+	}
+}
+
+func TestFindWaysAroundPositionLoadsLeaf(t *testing.T) {
+	defaults := DEFAULT_SETTINGS
+	DEFAULT_SETTINGS.OutputDirectory = t.TempDir()
+	t.Cleanup(func() { DEFAULT_SETTINGS = defaults })
+	ways := denseWays(400)
+	settings := OfflineSettings{
+		Box:             m.Box{MinPos: m.NewPosition(40, -84), MaxPos: m.NewPosition(40.25, -83.75)},
+		Overlap:         0.001,
+		OutputDirectory: DEFAULT_SETTINGS.OutputDirectory,
+		TmpDirectory:    t.TempDir(),
+		WaysPerFile:     100,
+	}
+	if err := generate(settings, scanWays(ways)); err != nil {
+		t.Fatal(err)
+	}
+
+	way := ways[0]
+	pos := m.NewPosition(way.Nodes[0].Lat, way.Nodes[0].Lon)
+	o, err := FindWaysAroundPosition(pos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer o.Close()
+	box := o.Box()
+	if !box.PosInside(pos) || box.Equals(settings.Box) {
+		t.Fatalf("expected the leaf covering the position, got %v", box)
+	}
+	found := false
+	for i := range o.Ways.Len() {
+		w := o.Ways.At(i)
+		found = found || w.Id() == int64(way.ID)
+	}
+	if !found {
+		t.Error("expected the leaf to hold the way at the position")
+	}
+	if _, ok := loadedTileIndexes[tileIndexPath(settings.Box, settings)]; !ok {
+		t.Error("expected the tile index to be kept for the next lookup")
+	}
+
+	// generating without splitting removes the index, so the whole area loads
+	settings.WaysPerFile = 0
+	if err := generate(settings, scanWays(ways)); err != nil {
+		t.Fatal(err)
+	}
+	whole, err := FindWaysAroundPosition(pos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer whole.Close()
+	if box := whole.Box(); !box.Equals(settings.Box) {
+		t.Errorf("expected the area once it is no longer split, got %v", box)
+	}
+}
@@ -0,0 +1,171 @@
+package maps
+
+import (
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/paulmach/osm"
+	"github.com/pkg/errors"
+)
+
+// osmChanges holds the ids of the ways and nodes an osm change file touches.
+type osmChanges struct {
+	ways  map[osm.WayID]bool
+	nodes map[osm.NodeID]bool
+}
+
+func readChanges(path string) (osmChanges, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return osmChanges{}, errors.Wrap(err, "could not open change file")
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return osmChanges{}, errors.Wrap(err, "could not decompress change file")
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return parseChanges(r)
+}
+
+func parseChanges(r io.Reader) (osmChanges, error) {
+	var change osm.Change
+	err := xml.NewDecoder(r).Decode(&change)
+	if err != nil {
+		return osmChanges{}, errors.Wrap(err, "could not parse change file")
+	}
+	changes := osmChanges{ways: map[osm.WayID]bool{}, nodes: map[osm.NodeID]bool{}}
+	for _, o := range []*osm.OSM{change.Create, change.Modify, change.Delete} {
+		if o == nil {
+			continue
+		}
+		for _, way := range o.Ways {
+			changes.ways[way.ID] = true
+		}
+		for _, node := range o.Nodes {
+			changes.nodes[node.ID] = true
+		}
+	}
+	return changes, nil
+}
+
+// touches checks whether a way or one of its nodes was changed.
+func (c osmChanges) touches(way *osm.Way) bool {
+	if c.ways[way.ID] {
+		return true
+	}
+	for _, node := range way.Nodes {
+		if c.nodes[node.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyChanges rewrites only the areas an osm change file affects. The input
+// pbf must already contain the changes and the output directory must hold the
+// previous generation of the same bounds along with its way index.
+func ApplyChanges(s OfflineSettings, changesFile string) {
+	slog.Info("Applying Changes to Offline Map", "changes", changesFile)
+	memory := startMemoryMonitor()
+	changes, err := readChanges(changesFile)
+	if err != nil {
+		slog.Error("could not read osm changes", "error", err)
+		panic("failed to read changes, exiting")
+	}
+	s.Header = NewTileHeader()
+	err = applyChanges(s, changes, scanPbf(s.InputFile, s.profile(), s.Header, s.MemoryLimit))
+	if err != nil {
+		slog.Error("could not apply osm changes", "error", err)
+		panic("failed to apply changes, exiting")
+	}
+	syncOutputDirectory(s)
+	slog.Info("Done Applying Changes to Offline Map", "peak_heap_mb", memory.Stop()/1024/1024)
+}
+
+// applyChanges scans the input twice. The first pass finds where the changed
+// ways are now, which together with where the index says they were gives the
+// affected areas. The second pass buckets every way of those areas so they
+// are written just like a full generation would.
+func applyChanges(s OfflineSettings, changes osmChanges, scan wayScan) error {
+	index, err := readWayIndex(wayIndexPath(s))
+	if err != nil {
+		return errors.Wrap(err, "changes need the way index of a full generation")
+	}
+
+	selection := newAreaSelection(s)
+	affected := map[int]bool{}
+	changed := map[int64]bool{}
+	for id := range changes.ways {
+		changed[int64(id)] = true
+		for _, area := range index.lookup(int64(id)) {
+			affected[int(area)] = true
+		}
+	}
+
+	profile := s.profile()
+	geometry := newWayGeometry(s)
+	updated := &wayIndex{}
+	slog.Info("Finding Changed Ways", "ways", len(changes.ways), "nodes", len(changes.nodes))
+	err = scan(func(way *osm.Way) error {
+		if !changes.touches(way) {
+			return nil
+		}
+		id := int64(way.ID)
+		// ways with moved nodes are not in the change file
+		if !changed[id] {
+			changed[id] = true
+			for _, area := range index.lookup(id) {
+				affected[int(area)] = true
+			}
+		}
+		tmpWay := profile.tmpWay(way)
+		geometry.apply(&tmpWay)
+		areas := selection.overlapping(tmpWay.Box)
+		for _, area := range areas {
+			affected[area] = true
+		}
+		updated.add(id, areas)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	selection.restrict(affected)
+	buckets, err := newWayBuckets(s, selection)
+	if err != nil {
+		return err
+	}
+	defer buckets.Close()
+	geometry = newWayGeometry(s)
+	slog.Info("Scanning Ways", "areas", len(selection.selected))
+	err = scan(func(way *osm.Way) error {
+		if len(selection.overlapping(wayBox(way))) == 0 {
+			return nil
+		}
+		tmpWay := profile.tmpWay(way)
+		geometry.apply(&tmpWay)
+		_, err := buckets.Add(tmpWay)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	geometry.report()
+	slog.Info("Writing Areas", "ways", buckets.ways, "groups", len(buckets.Groups()))
+	err = writeGroups(buckets, s)
+	if err != nil {
+		return err
+	}
+	return index.merge(changed, updated).write(wayIndexPath(s))
+}
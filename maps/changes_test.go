@@ -0,0 +1,132 @@
+package maps
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paulmach/osm"
+	m "pfeifer.dev/mapd/math"
+)
+
+func osmWay(id osm.WayID, name string, nodes ...osm.WayNode) *osm.Way {
+	return &osm.Way{ID: id, Nodes: nodes, Tags: osm.Tags{{Key: "highway", Value: "primary"}, {Key: "name", Value: name}}}
+}
+
+func scanWays(ways []*osm.Way) wayScan {
+	return func(visit func(way *osm.Way) error) error {
+		for _, way := range ways {
+			if err := visit(way); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+const testChanges = `<?xml version="1.0" encoding="UTF-8"?>
+<osmChange version="0.6">
+  <modify>
+    <way id="1" version="2"><nd ref="12"/><nd ref="13"/><tag k="highway" v="primary"/></way>
+  </modify>
+  <delete>
+    <way id="2" version="3"/>
+  </delete>
+  <modify>
+    <node id="31" version="2" lat="40.8" lon="-82.6"/>
+  </modify>
+  <create>
+    <way id="4" version="1"><nd ref="40"/><nd ref="41"/><tag k="highway" v="primary"/></way>
+  </create>
+</osmChange>`
+
+func TestApplyChangesMatchesFullGeneration(t *testing.T) {
+	before := []*osm.Way{
+		osmWay(1, "Moved", osm.WayNode{ID: 10, Lat: 40.1, Lon: -83.9}, osm.WayNode{ID: 11, Lat: 40.2, Lon: -83.8}),
+		osmWay(2, "Deleted", osm.WayNode{ID: 20, Lat: 41.1, Lon: -83.1}, osm.WayNode{ID: 21, Lat: 41.2, Lon: -83.05}),
+		osmWay(3, "Node moved", osm.WayNode{ID: 30, Lat: 40.6, Lon: -82.6}, osm.WayNode{ID: 31, Lat: 40.65, Lon: -82.55}),
+		osmWay(5, "Unchanged", osm.WayNode{ID: 50, Lat: 40.9, Lon: -82.9}, osm.WayNode{ID: 51, Lat: 41.3, Lon: -82.4}),
+	}
+	after := []*osm.Way{
+		osmWay(1, "Moved", osm.WayNode{ID: 12, Lat: 41.4, Lon: -83.4}, osm.WayNode{ID: 13, Lat: 41.45, Lon: -83.3}),
+		osmWay(3, "Node moved", osm.WayNode{ID: 30, Lat: 40.6, Lon: -82.6}, osm.WayNode{ID: 31, Lat: 40.8, Lon: -82.6}),
+		osmWay(4, "Created", osm.WayNode{ID: 40, Lat: 40.3, Lon: -82.45}, osm.WayNode{ID: 41, Lat: 40.35, Lon: -82.4}),
+		before[3],
+	}
+	settings := OfflineSettings{
+		Box:          m.Box{MinPos: m.NewPosition(40, -84), MaxPos: m.NewPosition(42, -82)},
+		Overlap:      0.01,
+		TmpDirectory: t.TempDir(),
+	}
+
+	incremental := settings
+	incremental.OutputDirectory = t.TempDir()
+	if err := generate(incremental, scanWays(before)); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	files := readOutput(t, incremental.OutputDirectory)
+	for name := range files {
+		if err := os.Chtimes(filepath.Join(incremental.OutputDirectory, name), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changes, err := parseChanges(strings.NewReader(testChanges))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.ways) != 3 || !changes.nodes[31] {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	if err := applyChanges(incremental, changes, scanWays(after)); err != nil {
+		t.Fatal(err)
+	}
+
+	full := settings
+	full.OutputDirectory = t.TempDir()
+	if err := generate(full, scanWays(after)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := readOutput(t, full.OutputDirectory)
+	actual := readOutput(t, incremental.OutputDirectory)
+	if len(actual) != len(expected) {
+		t.Errorf("expected %d files, got %d", len(expected), len(actual))
+	}
+	for name, data := range expected {
+		if !bytes.Equal(actual[name], data) {
+			t.Errorf("%s differs from a full generation", name)
+		}
+	}
+
+	rewritten := 0
+	for name := range actual {
+		info, err := os.Stat(filepath.Join(incremental.OutputDirectory, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.ModTime().After(old) && name != WAY_INDEX_FILE {
+			rewritten++
+		}
+	}
+	// the old and new area of way 1, the area of way 2, the area way 3 was in
+	// and the one its moved node reaches now, and the area of way 4
+	if rewritten != 6 {
+		t.Errorf("expected 6 areas to be rewritten, got %d", rewritten)
+	}
+}
+
+func TestApplyChangesNeedsWayIndex(t *testing.T) {
+	settings := OfflineSettings{
+		Box:             m.Box{MinPos: m.NewPosition(40, -84), MaxPos: m.NewPosition(42, -82)},
+		OutputDirectory: t.TempDir(),
+	}
+	err := applyChanges(settings, osmChanges{}, scanWays(nil))
+	if err == nil {
+		t.Error("expected changes without a previous generation to fail")
+	}
+}
@@ -3,12 +3,15 @@ package maps
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"math"
 	"os"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"capnproto.org/go/capnp/v3"
 	"github.com/paulmach/osm"
@@ -37,12 +40,17 @@ type TmpWay struct {
 	Box              m.Box
 	OneWay           bool
 	HighwayClass     offline.HighwayClass
+	Layer            int8
+	Bridge           bool
+	Tunnel           bool
 	Nodes            []TmpNode
 	Id               int64
 
 	MaxSpeedConditional         string
 	MaxSpeedForwardConditional  string
 	MaxSpeedBackwardConditional string
+
+	ExtraTags []TmpTag
 }
 
 type Area struct {
@@ -63,6 +71,18 @@ type OfflineSettings struct {
 	InputFile          string
 	GenerateEmptyFiles bool
 	Overlap            float64
+	Workers            int               // parallel area writers, one per cpu when 0
+	MemoryLimit        int64             // bytes of bucketed ways and way index entries or node locations kept in memory, a default when 0
+	TmpDirectory       string            // where buckets are spilled, the system default when empty
+	Profile            *GeneratorProfile // ways and tags to keep, the default profile when nil
+	Simplify           float64           // simplification tolerance in meters, off when 0
+	Resample           float64           // max node spacing in meters on sparse curves, off when 0
+	Format             uint16            // file format, OFFLINE_FORMAT_V1 when 0
+	Header             *TileHeader       // provenance written to every file, the generator version alone when nil
+	WaysPerFile        int               // areas with more ways are split into quarters, never when 0
+	Pack               bool              // write a pack per group instead of a file per tile
+	Unpacked           bool              // write unpacked files that are read in place
+	Legacy             bool              // write format 1 files without a version, header or curvatures, like files from before them
 }
 
 var DEFAULT_SETTINGS = OfflineSettings{
@@ -75,18 +95,21 @@ func EnsureOfflineMapsDirectories(s OfflineSettings) {
 	}
 }
 
+// groupDirectory is the directory of the group a box is in
+func groupDirectory(box m.Box, s OfflineSettings) string {
+	p := box.GroupPos()
+	return fmt.Sprintf("%s/%d/%d", s.OutputDirectory, int(p.Lat()), int(p.Lon()))
+}
+
 // Creates a file for a specific bounding box
 func GenerateBoundsFileName(a Area, s OfflineSettings) string {
-	p := a.Box.GroupPos()
-	dir := fmt.Sprintf("%s/%d/%d", s.OutputDirectory, int(p.Lat()), int(p.Lon()))
+	dir := groupDirectory(a.Box, s)
 	return fmt.Sprintf("%s/%f_%f_%f_%f", dir, a.Box.MinPos.Lat(), a.Box.MinPos.Lon(), a.Box.MaxPos.Lat(), a.Box.MaxPos.Lon())
 }
 
 // Creates a file for a specific bounding box
 func CreateBoundsDir(a Area, s OfflineSettings) error {
-	p := a.Box.GroupPos()
-	dir := fmt.Sprintf("%s/%d/%d", s.OutputDirectory, int(p.Lat()), int(p.Lon()))
-	err := os.MkdirAll(dir, 0o775)
+	err := os.MkdirAll(groupDirectory(a.Box, s), 0o775)
 	return errors.Wrap(err, "could not create bounds directory")
 }
 
@@ -115,218 +138,462 @@ func generateAreas() []Area {
 	return areas
 }
 
-func GenerateOffline(s OfflineSettings) {
-	slog.Info("Generating Offline Map")
-	EnsureOfflineMapsDirectories(s)
-	file, err := os.Open(s.InputFile)
-	if err != nil {
-		slog.Error("could not open map pbf file", "error", err)
-		panic("failed to read maps, exiting")
+// wayBox is the bounding box of the nodes of an osm way.
+func wayBox(way *osm.Way) m.Box {
+	minLat := float64(90)
+	minLon := float64(180)
+	maxLat := float64(-90)
+	maxLon := float64(-180)
+	for _, n := range way.Nodes {
+		if n.Lat < minLat {
+			minLat = n.Lat
+		}
+		if n.Lon < minLon {
+			minLon = n.Lon
+		}
+		if n.Lat > maxLat {
+			maxLat = n.Lat
+		}
+		if n.Lon > maxLon {
+			maxLon = n.Lon
+		}
 	}
-	defer file.Close()
+	return m.Box{MinPos: m.NewPosition(minLat, minLon), MaxPos: m.NewPosition(maxLat, maxLon)}
+}
+
+// profile returns the generator profile of the settings.
+func (s OfflineSettings) profile() *GeneratorProfile {
+	if s.Profile == nil {
+		return DefaultProfile()
+	}
+	return s.Profile
+}
+
+// wayScan calls visit for every way of the input that has at least two nodes.
+type wayScan func(visit func(way *osm.Way) error) error
+
+// scanPbf visits the ways of a pbf the profile keeps. Ways of a pbf without
+// node locations on its ways get them from the nodes of the pbf, which are
+// read once into at most memoryLimit bytes and reused by later scans. The
+// header of the pbf and the hash of its first full scan are recorded in the
+// tile header when there is one.
+func scanPbf(path string, profile *GeneratorProfile, header *TileHeader, memoryLimit int64) wayScan {
+	checked := false
+	var locations *nodeLocations
+	return func(visit func(way *osm.Way) error) error {
+		if !checked {
+			pbfHeader, err := readPbfHeader(path)
+			if err != nil {
+				return err
+			}
+			if header != nil {
+				header.DataTimestamp = pbfHeader.ReplicationTimestamp
+			}
+			if !slices.Contains(pbfHeader.OptionalFeatures, "LocationsOnWays") {
+				slog.Info("Reading Node Locations")
+				locations, err = readNodeLocations(path, profile, memoryLimit)
+				if err != nil {
+					return err
+				}
+				slog.Info("Read Node Locations", "nodes", len(locations.ids), "missing", locations.missing)
+			}
+			checked = true
+		}
+		hash := newSourceHash(header)
+		err := scanPbfWays(path, profile, hash, func(way *osm.Way) error {
+			if locations != nil {
+				locations.resolve(way)
+			}
+			if len(way.Nodes) < 2 {
+				return nil
+			}
+			return visit(way)
+		})
+		if err == nil && hash != nil {
+			hash.finish()
+		}
+		return err
+	}
+}
 
+// openPbf opens a pbf for scanning, writing everything the scanner reads to
+// tee when it is not nil.
+func openPbf(path string, tee io.Writer) (*os.File, *osmpbf.Scanner, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not open map pbf file")
+	}
+	var reader io.Reader = file
+	if tee != nil {
+		reader = io.TeeReader(file, tee)
+	}
 	// The third parameter is the number of parallel decoders to use.
-	scanner := osmpbf.New(context.Background(), file, runtime.GOMAXPROCS(-1))
+	scanner := osmpbf.New(context.Background(), reader, runtime.GOMAXPROCS(-1))
 	scanner.SkipRelations = true
+	return file, scanner, nil
+}
+
+// readPbfHeader reads the header block of a pbf, which tells whether its ways
+// carry the locations of their nodes, like the output of osmium
+// add-locations-to-ways, and when its data was replicated.
+func readPbfHeader(path string) (*osmpbf.Header, error) {
+	file, scanner, err := openPbf(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 	defer scanner.Close()
+	header, err := scanner.Header()
+	return header, errors.Wrap(err, "could not read map pbf header")
+}
 
-	scannedWays := []TmpWay{}
-	areas := generateAreas()
-	index := 0
-	allMinLat := float64(90)
-	allMinLon := float64(180)
-	allMaxLat := float64(-90)
-	allMaxLon := float64(-180)
+// scanPbfWays visits the ways of a pbf the profile keeps, whether or not they
+// carry node locations. The whole file is read, so hash covers all of it
+// once the scan succeeds.
+func scanPbfWays(path string, profile *GeneratorProfile, hash *sourceHash, visit func(way *osm.Way) error) error {
+	var tee io.Writer
+	if hash != nil {
+		tee = hash.hash
+	}
+	file, scanner, err := openPbf(path, tee)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner.SkipNodes = true
+	scanner.FilterWay = profile.Includes
+	defer scanner.Close()
 
-	slog.Info("Scanning Ways")
 	for scanner.Scan() {
-		var way *osm.Way
-		switch o := scanner.Object(); o.(type) {
-		case *osm.Way:
-			way = o.(*osm.Way)
-		default:
-			way = nil
+		way, ok := scanner.Object().(*osm.Way)
+		if !ok {
+			continue
 		}
-		if way != nil && len(way.Nodes) > 1 {
-			tags := way.TagMap()
-			lanes, _ := strconv.ParseUint(tags["lanes"], 10, 8)
-			tmpWay := TmpWay{
-				Nodes:            make([]TmpNode, len(way.Nodes)),
-				Name:             tags["name"],
-				Ref:              tags["ref"],
-				Hazard:           tags["hazard"],
-				MaxSpeed:         ParseMaxSpeed(tags["maxspeed"]),
-				MaxSpeedForward:  ParseMaxSpeed(tags["maxspeed:forward"]),
-				MaxSpeedBackward: ParseMaxSpeed(tags["maxspeed:backward"]),
-				MaxSpeedAdvisory: ParseMaxSpeed(tags["maxspeed:advisory"]),
-				Lanes:            uint8(lanes),
-				OneWay:           tags["oneway"] == "yes",
-				Id:               int64(way.ID),
-				HighwayClass:     HighwayClassFromTag(tags["highway"]),
-
-				MaxSpeedConditional:         tags["maxspeed:conditional"],
-				MaxSpeedForwardConditional:  tags["maxspeed:forward:conditional"],
-				MaxSpeedBackwardConditional: tags["maxspeed:backward:conditional"],
-			}
-			index++
-
-			minLat := float64(90)
-			minLon := float64(180)
-			maxLat := float64(-90)
-			maxLon := float64(-180)
-			for i, n := range way.Nodes {
-				if n.Lat < minLat {
-					minLat = n.Lat
-				}
-				if n.Lon < minLon {
-					minLon = n.Lon
-				}
-				if n.Lat > maxLat {
-					maxLat = n.Lat
-				}
-				if n.Lon > maxLon {
-					maxLon = n.Lon
+		if err := visit(way); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(scanner.Err(), "could not scan map pbf file")
+}
+
+// GenerateOffline turns a pbf of roads into offline map files. The ways are
+// sorted into buckets per group in a single pass over the input, spilling the
+// buckets to disk once they outgrow the memory limit, and the groups are then
+// written in parallel.
+func GenerateOffline(s OfflineSettings) {
+	slog.Info("Generating Offline Map")
+	EnsureOfflineMapsDirectories(s)
+	memory := startMemoryMonitor()
+	s.Header = NewTileHeader()
+	err := generate(s, scanPbf(s.InputFile, s.profile(), s.Header, s.MemoryLimit))
+	if err != nil {
+		slog.Error("could not generate offline maps", "error", err)
+		panic("failed to generate maps, exiting")
+	}
+	syncOutputDirectory(s)
+	slog.Info("Done Generating Offline Map", "peak_heap_mb", memory.Stop()/1024/1024)
+}
+
+func generate(s OfflineSettings, scan wayScan) error {
+	buckets, err := newWayBuckets(s, newAreaSelection(s))
+	if err != nil {
+		return err
+	}
+	defer buckets.Close()
+	buckets.index = &wayIndexRuns{dir: buckets.dir}
+
+	profile := s.profile()
+	geometry := newWayGeometry(s)
+	slog.Info("Scanning Ways")
+	err = scan(func(way *osm.Way) error {
+		tmpWay := profile.tmpWay(way)
+		geometry.apply(&tmpWay)
+		_, err := buckets.Add(tmpWay)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	geometry.report()
+	slog.Info("Writing Areas", "ways", buckets.ways, "groups", len(buckets.Groups()), "spilled_bytes", buckets.spilled)
+	err = writeGroups(buckets, s)
+	if err != nil {
+		return err
+	}
+	return buckets.index.write(wayIndexPath(s))
+}
+
+// writeGroups writes the areas of every group with a pool of workers.
+func writeGroups(buckets *wayBuckets, s OfflineSettings) error {
+	workers := s.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(-1)
+	}
+	groups := make(chan int)
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for group := range groups {
+				if err := writeGroup(buckets, group, s); err != nil {
+					errs <- err
+					// keep draining so the producer is not blocked
+					for range groups {
+					}
+					return
 				}
-				tmpWay.Nodes[i].Latitude = n.Lat
-				tmpWay.Nodes[i].Longitude = n.Lon
 			}
-			tmpWay.Box.MinPos = m.NewPosition(minLat, minLon)
-			tmpWay.Box.MaxPos = m.NewPosition(maxLat, maxLon)
-			if minLat < allMinLat {
-				allMinLat = minLat
+		})
+	}
+	for _, group := range buckets.Groups() {
+		groups <- group
+	}
+	close(groups)
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+func writeGroup(buckets *wayBuckets, group int, s OfflineSettings) error {
+	ways, err := buckets.Ways(group)
+	if err != nil {
+		return err
+	}
+	overlapBox := s.Box.Overlap(s.Overlap)
+	var tiles tileIndex
+	tilesPath := ""
+	var pack *packBuilder
+	for _, index := range buckets.Areas(group) {
+		area := Area{Box: areaBox(index/longitudeAreas, index%longitudeAreas)}
+		haveWays := overlapBox.Overlapping(area.Box)
+		if !haveWays && !s.GenerateEmptyFiles {
+			continue
+		}
+		areaOverlapBox := area.OverlapBox(s.Overlap)
+		for _, way := range ways {
+			if way.Box.Overlapping(areaOverlapBox) {
+				area.Ways = append(area.Ways, way)
+			}
+		}
+		leaves := splitArea(area, s)
+		if s.Pack {
+			if pack == nil {
+				// areas of the group that are not written keep their tiles
+				pack, err = newPackBuilder(packPath(area.Box, s))
+				if err != nil {
+					return err
+				}
 			}
-			if minLon < allMinLon {
-				allMinLon = minLon
+			if err := pack.add(area.Box, leaves, s); err != nil {
+				return err
 			}
-			if maxLat > allMaxLat {
-				allMaxLat = maxLat
+			continue
+		}
+		if tiles == nil {
+			// areas of the group that are not written keep their split
+			tilesPath = tileIndexPath(area.Box, s)
+			tiles, err = readTileIndex(tilesPath)
+			if err != nil {
+				return err
 			}
-			if maxLon > allMaxLon {
-				allMaxLon = maxLon
+		}
+		for _, leaf := range leaves {
+			if err := writeArea(leaf.Area, s); err != nil {
+				return err
 			}
-			scannedWays = append(scannedWays, tmpWay)
+		}
+		if err := updateTileIndex(tiles, index, leaves, s); err != nil {
+			return err
 		}
 	}
-	if err := scanner.Err(); err != nil {
-		slog.Error("could not scan map pbf file", "error", err)
-		panic("failed to scan maps, exiting")
+	if pack != nil {
+		return pack.write()
 	}
+	if tiles == nil {
+		return nil
+	}
+	return tiles.write(tilesPath)
+}
 
-	slog.Info("Finding Bounds")
-	for _, area := range areas {
-		overlapBox := s.Box.Overlap(s.Overlap)
-		if !overlapBox.Contains(area.Box) {
-			continue
-		}
+// writeArea stores the ways of an area in its offline map file.
+func writeArea(area Area, s OfflineSettings) error {
+	data, err := marshalArea(area, s)
+	if err != nil {
+		return err
+	}
+	err = CreateBoundsDir(area, s)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(GenerateBoundsFileName(area, s), data, 0o644)
+	return errors.Wrap(err, "could not write offline data to file")
+}
 
-		haveWays := overlapBox.Overlapping(area.Box)
-		if !haveWays && !s.GenerateEmptyFiles {
-			continue
-		}
+// marshalArea builds the contents of an offline map file in the format of the
+// settings, a packed capnp message unless the settings ask for unpacked files.
+func marshalArea(area Area, s OfflineSettings) ([]byte, error) {
+	msg, err := buildArea(area, s)
+	if err != nil {
+		return nil, err
+	}
+	if s.Unpacked {
+		data, err := msg.Marshal()
+		return append([]byte(UNPACKED_MAGIC), data...), errors.Wrap(err, "could not marshal offline data")
+	}
+	data, err := msg.MarshalPacked()
+	return data, errors.Wrap(err, "could not marshal offline data")
+}
+
+// buildArea builds the capnp message of an area in the format of the settings.
+func buildArea(area Area, s OfflineSettings) (*capnp.Message, error) {
+	format := s.format()
+	arena := capnp.MultiSegment(nil)
+	msg, seg, err := capnp.NewMessage(arena)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create capnp arena for offline data")
+	}
+	rootOffline, err := offline.NewRootOffline(seg)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create capnp root for offline data")
+	}
 
-		arena := capnp.MultiSegment(nil)
-		msg, seg, err := capnp.NewMessage(arena)
+	ways, err := rootOffline.NewWays(int32(len(area.Ways)))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create ways in offline data")
+	}
+	rootOffline.SetMinLat(area.Box.MinPos.Lat())
+	rootOffline.SetMinLon(area.Box.MinPos.Lon())
+	rootOffline.SetMaxLat(area.Box.MaxPos.Lat())
+	rootOffline.SetMaxLon(area.Box.MaxPos.Lon())
+	rootOffline.SetOverlap(s.Overlap)
+	var curvatures [][]float32
+	if !s.Legacy {
+		rootOffline.SetVersion(format)
+		err = s.header().write(rootOffline)
 		if err != nil {
-			slog.Error("could not create capnp arena for offline data", "error", err)
-			panic("unexpected capnp error, exiting")
+			return nil, err
+		}
+		curvatures = areaCurvatures(area.Ways)
+	}
+	table := newStringTable()
+	for i, way := range area.Ways {
+		w := ways.At(i)
+		w.SetId(way.Id)
+		if format == OFFLINE_FORMAT_V2 {
+			w.SetNameIndex(table.index(way.Name))
+			w.SetRefIndex(table.index(way.Ref))
+			w.SetHazardIndex(table.index(way.Hazard))
+		} else {
+			err = setNames(w, way)
 		}
-		rootOffline, err := offline.NewRootOffline(seg)
 		if err != nil {
-			slog.Error("could not create capnp root for offline data", "error", err)
-			panic("unexpected capnp error, exiting")
+			return nil, err
 		}
-
-		for _, way := range scannedWays {
-
-			overlaps := way.Box.Overlapping(area.OverlapBox(s.Overlap))
-			if overlaps {
-				area.Ways = append(area.Ways, way)
-			}
+		w.SetMaxSpeed(way.MaxSpeed)
+		w.SetMaxSpeedForward(way.MaxSpeedForward)
+		w.SetMaxSpeedBackward(way.MaxSpeedBackward)
+		err := w.SetMaxSpeedConditional(way.MaxSpeedConditional)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not set way conditional max speed")
 		}
-
-		slog.Info("Writing Area")
-		ways, err := rootOffline.NewWays(int32(len(area.Ways)))
+		err = w.SetMaxSpeedForwardConditional(way.MaxSpeedForwardConditional)
 		if err != nil {
-			slog.Error("could not create ways in offline data", "error", err)
-			panic("unexpected capnp error, exiting")
+			return nil, errors.Wrap(err, "could not set way forward conditional max speed")
 		}
-		rootOffline.SetMinLat(area.Box.MinPos.Lat())
-		rootOffline.SetMinLon(area.Box.MinPos.Lon())
-		rootOffline.SetMaxLat(area.Box.MaxPos.Lat())
-		rootOffline.SetMaxLon(area.Box.MaxPos.Lon())
-		rootOffline.SetOverlap(s.Overlap)
-		for i, way := range area.Ways {
-			w := ways.At(i)
-			w.SetId(way.Id)
-			w.SetMinLat(way.Box.MinPos.Lat())
-			w.SetMinLon(way.Box.MinPos.Lon())
-			w.SetMaxLat(way.Box.MaxPos.Lat())
-			w.SetMaxLon(way.Box.MaxPos.Lon())
-			err := w.SetName(way.Name)
-			if err != nil {
-				slog.Error("could not set way name", "error", err)
-				panic("unexpected capnp error, exiting")
-			}
-			err = w.SetRef(way.Ref)
-			if err != nil {
-				slog.Error("could not set way ref", "error", err)
-				panic("unexpected capnp error, exiting")
-			}
-			err = w.SetHazard(way.Hazard)
-			if err != nil {
-				slog.Error("could not set way hazard", "error", err)
-				panic("unexpected capnp error, exiting")
-			}
-			w.SetMaxSpeed(way.MaxSpeed)
-			w.SetMaxSpeedForward(way.MaxSpeedForward)
-			w.SetMaxSpeedBackward(way.MaxSpeedBackward)
-			err = w.SetMaxSpeedConditional(way.MaxSpeedConditional)
-			if err != nil {
-				slog.Error("could not set way conditional max speed", "error", err)
-				panic("unexpected capnp error, exiting")
-			}
-			err = w.SetMaxSpeedForwardConditional(way.MaxSpeedForwardConditional)
+		err = w.SetMaxSpeedBackwardConditional(way.MaxSpeedBackwardConditional)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not set way backward conditional max speed")
+		}
+		w.SetAdvisorySpeed(way.MaxSpeedAdvisory)
+		w.SetLanes(way.Lanes)
+		w.SetOneWay(way.OneWay)
+		w.SetHighwayClass(way.HighwayClass)
+		w.SetLayer(way.Layer)
+		w.SetBridge(way.Bridge)
+		w.SetTunnel(way.Tunnel)
+		if format == OFFLINE_FORMAT_V2 {
+			err = setNodeDeltas(w, way.Nodes)
+		} else {
+			err = setNodes(w, way.Nodes)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if curvatures != nil {
+			nodeCurvatures, err := w.NewNodeCurvatures(int32(len(curvatures[i])))
 			if err != nil {
-				slog.Error("could not set way forward conditional max speed", "error", err)
-				panic("unexpected capnp error, exiting")
+				return nil, errors.Wrap(err, "could not create way node curvatures")
 			}
-			err = w.SetMaxSpeedBackwardConditional(way.MaxSpeedBackwardConditional)
-			if err != nil {
-				slog.Error("could not set way backward conditional max speed", "error", err)
-				panic("unexpected capnp error, exiting")
+			for j, curvature := range curvatures[i] {
+				nodeCurvatures.Set(j, curvature)
 			}
-			w.SetAdvisorySpeed(way.MaxSpeedAdvisory)
-			w.SetLanes(way.Lanes)
-			w.SetOneWay(way.OneWay)
-			w.SetHighwayClass(way.HighwayClass)
-			nodes, err := w.NewNodes(int32(len(way.Nodes)))
+		}
+		if len(way.ExtraTags) > 0 {
+			tags, err := w.NewExtraTags(int32(len(way.ExtraTags)))
 			if err != nil {
-				slog.Error("could not create way nodes", "error", err)
-				panic("unexpected capnp error, exiting")
+				return nil, errors.Wrap(err, "could not create way extra tags")
 			}
-			for j, node := range way.Nodes {
-				n := nodes.At(j)
-				n.SetLatitude(node.Latitude)
-				n.SetLongitude(node.Longitude)
+			for j, tag := range way.ExtraTags {
+				t := tags.At(j)
+				err = t.SetKey(tag.Key)
+				if err != nil {
+					return nil, errors.Wrap(err, "could not set extra tag key")
+				}
+				err = t.SetValue(tag.Value)
+				if err != nil {
+					return nil, errors.Wrap(err, "could not set extra tag value")
+				}
 			}
 		}
+	}
 
-		data, err := msg.MarshalPacked()
-		if err != nil {
-			slog.Error("could not marshal offline data", "error", err)
-			panic("unexpected capnp error, exiting")
-		}
-		err = CreateBoundsDir(area, s)
-		if err != nil {
-			slog.Error("could not create bounds directory", "error", err)
-			panic("unexpected file error, exiting")
-		}
-		err = os.WriteFile(GenerateBoundsFileName(area, s), data, 0o644)
+	if format == OFFLINE_FORMAT_V2 {
+		err = table.write(rootOffline)
 		if err != nil {
-			slog.Error("could not write offline data to file", "error", err)
-			panic("unexpected file error, exiting")
+			return nil, err
 		}
 	}
+	return msg, nil
+}
+
+// setNames stores the strings and box of a v1 way.
+func setNames(w offline.Way, way TmpWay) error {
+	w.SetMinLat(way.Box.MinPos.Lat())
+	w.SetMinLon(way.Box.MinPos.Lon())
+	w.SetMaxLat(way.Box.MaxPos.Lat())
+	w.SetMaxLon(way.Box.MaxPos.Lon())
+	err := w.SetName(way.Name)
+	if err != nil {
+		return errors.Wrap(err, "could not set way name")
+	}
+	err = w.SetRef(way.Ref)
+	if err != nil {
+		return errors.Wrap(err, "could not set way ref")
+	}
+	err = w.SetHazard(way.Hazard)
+	if err != nil {
+		return errors.Wrap(err, "could not set way hazard")
+	}
+	return nil
+}
+
+// setNodes stores the nodes of a v1 way, after its other fields like files
+// from before the v2 format.
+func setNodes(w offline.Way, wayNodes []TmpNode) error {
+	nodes, err := w.NewNodes(int32(len(wayNodes)))
+	if err != nil {
+		return errors.Wrap(err, "could not create way nodes")
+	}
+	for j, node := range wayNodes {
+		n := nodes.At(j)
+		n.SetLatitude(node.Latitude)
+		n.SetLongitude(node.Longitude)
+	}
+	return nil
+}
+
+func syncOutputDirectory(s OfflineSettings) {
 	f, err := os.Open(s.OutputDirectory)
 	if err != nil {
 		slog.Error("could not open bounds directory", "error", err)
@@ -342,16 +609,15 @@ func GenerateOffline(s OfflineSettings) {
 		slog.Error("could not close bounds directory", "error", err)
 		panic("unexpected file error, exiting")
 	}
-
-	slog.Info("Done Generating Offline Map")
 }
 
-func areaForPosition(pos m.Position) (Area, bool) {
+// areaForPosition returns the area index of a position.
+func areaForPosition(pos m.Position) (int, bool) {
 	latitude := pos.Lat()
 	longitude := pos.Lon()
 	if math.IsNaN(latitude) || math.IsNaN(longitude) ||
 		latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
-		return Area{}, false
+		return 0, false
 	}
 
 	latitudeIndex := int(math.Ceil(latitude/ms.AREA_BOX_DEGREES)) + latitudeAreas/2 - 1
@@ -363,11 +629,58 @@ func areaForPosition(pos m.Position) (Area, bool) {
 		longitudeIndex = 0
 	}
 
-	return Area{Box: areaBox(latitudeIndex, longitudeIndex)}, true
+	return latitudeIndex*longitudeAreas + longitudeIndex, true
+}
+
+// fileForPosition returns the area of the offline file covering pos, which is
+// a quarter of an area when the area was split.
+func fileForPosition(pos m.Position) (Area, bool) {
+	index, found := areaForPosition(pos)
+	if !found {
+		return Area{}, false
+	}
+	area := Area{Box: areaBox(index/longitudeAreas, index%longitudeAreas)}
+	tiles, err := loadTileIndex(tileIndexPath(area.Box, DEFAULT_SETTINGS))
+	if err != nil {
+		slog.Warn("could not load tile index", "error", err)
+		return area, true
+	}
+	return tiles.leaf(index, pos), true
+}
+
+// packForPosition returns the pack of the group of pos, nil when the group
+// has a file per tile.
+func packForPosition(pos m.Position) (*Pack, Area) {
+	index, found := areaForPosition(pos)
+	if !found {
+		return nil, Area{}
+	}
+	area := Area{Box: areaBox(index/longitudeAreas, index%longitudeAreas)}
+	pack, err := loadPack(packPath(area.Box, DEFAULT_SETTINGS))
+	if err != nil {
+		slog.Warn("could not load pack", "error", err)
+		return nil, area
+	}
+	return pack, area
 }
 
 func FindWaysAroundPosition(pos m.Position) (Offline, error) {
-	area, found := areaForPosition(pos)
+	if pack, area := packForPosition(pos); pack != nil {
+		tile, found := pack.find(pos)
+		if !found {
+			o := Offline{Loaded: false}
+			o.box.Set(area.Box)
+			return o, errors.Wrap(os.ErrNotExist, "could not find current offline data in pack")
+		}
+		slog.Info("Loading pack tile", "box", pack.Box(tile))
+		o := pack.Tile(tile)
+		if !o.Loaded {
+			o.box.Set(pack.Box(tile))
+		}
+		return o, nil
+	}
+
+	area, found := fileForPosition(pos)
 	if !found {
 		cBox := utils.Curry[m.Box]{}
 		cBox.Set(area.Box)
@@ -376,14 +689,28 @@ func FindWaysAroundPosition(pos m.Position) (Offline, error) {
 
 	boundsName := GenerateBoundsFileName(area, DEFAULT_SETTINGS)
 	slog.Info("Loading bounds file", "filename", boundsName)
-	data, err := os.ReadFile(boundsName)
-	o := ReadOffline(data)
+	o, err := LoadOffline(boundsName)
 	if !o.Loaded {
 		o.box.Set(area.Box)
 	}
 	return o, errors.Wrap(err, "could not read current offline data file")
 }
 
+// BoundsFileExists reports whether the offline data file covering pos is
+// installed.
+func BoundsFileExists(pos m.Position) bool {
+	if pack, _ := packForPosition(pos); pack != nil {
+		_, found := pack.find(pos)
+		return found
+	}
+	area, found := fileForPosition(pos)
+	if !found {
+		return false
+	}
+	_, err := os.Stat(GenerateBoundsFileName(area, DEFAULT_SETTINGS))
+	return err == nil
+}
+
 func ParseMaxSpeed(maxspeed string) float64 {
 	splitSpeed := strings.Split(maxspeed, " ")
 	if len(splitSpeed) == 0 {
@@ -409,3 +736,25 @@ func ParseMaxSpeed(maxspeed string) float64 {
 
 	return 0
 }
+
+const (
+	MIN_LAYER = -5 // lowest layer osm allows
+	MAX_LAYER = 5  // highest layer osm allows
+)
+
+// ParseLayer parses an osm layer tag. Layers are integers in the range -5 to 5
+// with 0 as the implied default when the tag is missing, malformed or out of
+// that range.
+func ParseLayer(layer string) int8 {
+	parsed, err := strconv.ParseInt(strings.TrimSpace(layer), 10, 8)
+	if err != nil || parsed < MIN_LAYER || parsed > MAX_LAYER {
+		return 0
+	}
+	return int8(parsed)
+}
+
+// bridge and tunnel tags use many values (yes, viaduct, culvert, building_passage, ...)
+// so anything other than a missing tag or an explicit no counts.
+func isStructureTag(value string) bool {
+	return value != "" && value != "no"
+}
@@ -0,0 +1,234 @@
+package maps
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pfeifer.dev/mapd/cereal/offline"
+	m "pfeifer.dev/mapd/math"
+)
+
+func testWays(count int) []TmpWay {
+	rng := rand.New(rand.NewSource(1))
+	ways := make([]TmpWay, 0, count)
+	for i := range count {
+		// start near area and group borders now and then
+		lat := 39.5 + rng.Float64()*3
+		lon := -84.5 + rng.Float64()*4
+		if i%7 == 0 {
+			lat = 40 + float64(rng.Intn(8))*0.25
+		}
+		way := TmpWay{
+			Id:       int64(i + 1),
+			Name:     "Road",
+			Ref:      "SR 1",
+			MaxSpeed: float64(rng.Intn(30)),
+			Lanes:    uint8(rng.Intn(4)),
+			Layer:    int8(rng.Intn(3) - 1),
+			OneWay:   i%2 == 0,
+			Bridge:   i%5 == 0,
+		}
+		if i%3 == 0 {
+			way.MaxSpeedConditional = "50 @ (22:00-06:00)"
+		}
+		// every other field is set now and then so a change to how any of them
+		// is written shows
+		if i%4 == 1 {
+			way.Hazard = "crossing"
+			way.MaxSpeedForward = float64(i % 30)
+			way.MaxSpeedBackward = float64(i % 20)
+		}
+		if i%9 == 0 {
+			way.MaxSpeedForwardConditional = "30 @ (Mo-Fr 07:00-09:00)"
+			way.MaxSpeedBackwardConditional = "40 @ wet"
+		}
+		way.MaxSpeedAdvisory = float64(i%5) * 4
+		way.HighwayClass = offline.HighwayClass(i % 10)
+		way.Tunnel = i%11 == 0
+		length := 2 + rng.Intn(10)
+		step := 0.01 + rng.Float64()*0.1
+		minLat, minLon, maxLat, maxLon := 90.0, 180.0, -90.0, -180.0
+		for range length {
+			way.Nodes = append(way.Nodes, TmpNode{Latitude: lat, Longitude: lon})
+			minLat, maxLat = min(minLat, lat), max(maxLat, lat)
+			minLon, maxLon = min(minLon, lon), max(maxLon, lon)
+			lat += step * (rng.Float64() - 0.3)
+			lon += step * (rng.Float64() - 0.3)
+		}
+		way.Box = m.Box{MinPos: m.NewPosition(minLat, minLon), MaxPos: m.NewPosition(maxLat, maxLon)}
+		ways = append(ways, way)
+	}
+	return ways
+}
+
+func readOutput(t *testing.T, dir string) map[string][]byte {
+	files := map[string][]byte{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		rel, _ := filepath.Rel(dir, path)
+		files[rel] = data
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// readReference reads the areas the generator wrote for testWays(500) before
+// it bucketed the ways, when every area was checked against every way. They
+// were written with the current offline schema so the struct sizes match.
+func readReference(t *testing.T) map[string][]byte {
+	f, err := os.Open("testdata/reference_areas.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[filepath.Clean(header.Name)] = data
+	}
+}
+
+// checkSameArea compares every field of the decoded areas, the nodes and
+// boxes of format 2 files only to the microdegree.
+func checkSameArea(t *testing.T, name string, expected []byte, actual []byte) {
+	t.Helper()
+	o1, o2 := ReadOffline(expected), ReadOffline(actual)
+	if !o1.Loaded || !o2.Loaded {
+		t.Errorf("area %s: could not load %v %v", name, o1.Loaded, o2.Loaded)
+		return
+	}
+	if o1.Box() != o2.Box() || o1.Overlap() != o2.Overlap() || o1.Ways.Len() != o2.Ways.Len() {
+		t.Errorf("area %s: expected %d ways in %v, got %d in %v", name, o1.Ways.Len(), o1.Box(), o2.Ways.Len(), o2.Box())
+		return
+	}
+	tolerance := 0.0
+	if o2.Format() == OFFLINE_FORMAT_V2 {
+		tolerance = 0.5/MICRODEGREES + 1e-12
+	}
+	near := func(a, b m.Position) bool {
+		return math.Abs(a.Lat()-b.Lat()) <= tolerance && math.Abs(a.Lon()-b.Lon()) <= tolerance
+	}
+	for i := range o1.Ways.Len() {
+		w1, w2 := o1.Ways.At(i), o2.Ways.At(i)
+		b1, b2 := w1.Box(), w2.Box()
+		if w1.Id() != w2.Id() || w1.WayName() != w2.WayName() || w1.WayRef() != w2.WayRef() || w1.Hazard() != w2.Hazard() ||
+			w1.MaxSpeed() != w2.MaxSpeed() || w1.MaxSpeedForward() != w2.MaxSpeedForward() ||
+			w1.MaxSpeedBackward() != w2.MaxSpeedBackward() || w1.AdvisorySpeed() != w2.AdvisorySpeed() ||
+			w1.MaxSpeedConditional() != w2.MaxSpeedConditional() ||
+			w1.MaxSpeedForwardConditional() != w2.MaxSpeedForwardConditional() ||
+			w1.MaxSpeedBackwardConditional() != w2.MaxSpeedBackwardConditional() ||
+			w1.Lanes() != w2.Lanes() || w1.OneWay() != w2.OneWay() || w1.HighwayClass() != w2.HighwayClass() ||
+			w1.Layer() != w2.Layer() || w1.Bridge() != w2.Bridge() || w1.Tunnel() != w2.Tunnel() ||
+			w1.Way.HasExtraTags() != w2.Way.HasExtraTags() ||
+			!near(b1.MinPos, b2.MinPos) || !near(b1.MaxPos, b2.MaxPos) || w1.Nodes.Len() != w2.Nodes.Len() {
+			t.Errorf("area %s: way %d differs from the reference", name, i)
+			continue
+		}
+		for j := range w1.Nodes.Len() {
+			if !near(w1.Nodes.At(j), w2.Nodes.At(j)) {
+				t.Errorf("area %s: way %d node %d differs from the reference", name, i, j)
+			}
+		}
+	}
+}
+
+// generateBucketed writes the areas of testWays(500) through buckets small
+// enough to spill several times.
+func generateBucketed(t *testing.T, s OfflineSettings) map[string][]byte {
+	t.Helper()
+	s.Box = m.Box{MinPos: m.NewPosition(40, -84), MaxPos: m.NewPosition(42, -81)}
+	s.Overlap = 0.01
+	s.OutputDirectory = t.TempDir()
+	s.TmpDirectory = t.TempDir()
+	s.MemoryLimit = 16 * 1024
+	s.Workers = 3
+	buckets, err := newWayBuckets(s, newAreaSelection(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, way := range testWays(500) {
+		if _, err := buckets.Add(way); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if buckets.spilled == 0 {
+		t.Error("expected the buckets to spill to disk")
+	}
+	if err := writeGroups(buckets, s); err != nil {
+		t.Fatal(err)
+	}
+	if err := buckets.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(s.TmpDirectory); len(entries) != 0 {
+		t.Error("expected the spilled buckets to be removed")
+	}
+	return readOutput(t, s.OutputDirectory)
+}
+
+// The areas written through buckets in the legacy layout have the same bytes
+// as the ones the generator wrote before.
+func TestBucketedGenerationMatchesReference(t *testing.T) {
+	expected := readReference(t)
+	actual := generateBucketed(t, OfflineSettings{Legacy: true})
+	if len(expected) != 8*12 {
+		t.Fatalf("expected 96 areas from the reference, got %d", len(expected))
+	}
+	if len(actual) != len(expected) {
+		t.Errorf("expected %d areas, got %d", len(expected), len(actual))
+	}
+	for name, data := range expected {
+		if !bytes.Equal(actual[name], data) {
+			t.Errorf("area %s differs from the reference", name)
+		}
+	}
+}
+
+func TestBucketedFormatsMatchReference(t *testing.T) {
+	expected := readReference(t)
+	for _, s := range []OfflineSettings{
+		{},
+		{Format: OFFLINE_FORMAT_V2},
+		{Format: OFFLINE_FORMAT_V2, Unpacked: true},
+	} {
+		actual := generateBucketed(t, s)
+		if len(actual) != len(expected) {
+			t.Errorf("format %d: expected %d areas, got %d", s.format(), len(expected), len(actual))
+		}
+		for name, data := range expected {
+			checkSameArea(t, name, data, actual[name])
+		}
+	}
+}
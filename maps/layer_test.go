@@ -0,0 +1,61 @@
+package maps
+
+import (
+	"testing"
+
+	"capnproto.org/go/capnp/v3"
+	"pfeifer.dev/mapd/cereal/offline"
+)
+
+func TestParseLayer(t *testing.T) {
+	cases := map[string]int8{
+		"":    0,
+		"1":   1,
+		"-1":  -1,
+		" 2 ": 2,
+		"-5":  -5,
+		"5":   5,
+		"6":   0,
+		"-6":  0,
+		"yes": 0,
+		"1;2": 0,
+		"900": 0,
+	}
+	for tag, expected := range cases {
+		if layer := ParseLayer(tag); layer != expected {
+			t.Errorf("ParseLayer(%q) = %d, expected %d", tag, layer, expected)
+		}
+	}
+}
+
+func TestWayLevel(t *testing.T) {
+	cases := []struct {
+		layer    int8
+		bridge   bool
+		tunnel   bool
+		expected int
+	}{
+		{0, false, false, 0},
+		{0, true, false, 1},
+		{0, false, true, -1},
+		{2, true, false, 2},
+		{-2, false, true, -2},
+	}
+	for _, c := range cases {
+		_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := offline.NewRootWay(seg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.SetLayer(c.layer)
+		w.SetBridge(c.bridge)
+		w.SetTunnel(c.tunnel)
+		way := NewWay(w)
+		if level := way.Level(); level != c.expected {
+			t.Errorf("layer=%d bridge=%v tunnel=%v: Level() = %d, expected %d", c.layer, c.bridge, c.tunnel, level, c.expected)
+		}
+	}
+}
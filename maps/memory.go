@@ -0,0 +1,47 @@
+package maps
+
+import (
+	"runtime"
+	"time"
+)
+
+const memorySampleInterval = time.Second / 4
+
+// memoryMonitor samples the heap while the generator runs to report its peak.
+type memoryMonitor struct {
+	stop chan struct{}
+	done chan struct{}
+	peak uint64
+}
+
+func startMemoryMonitor() *memoryMonitor {
+	mm := &memoryMonitor{stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(mm.done)
+		ticker := time.NewTicker(memorySampleInterval)
+		defer ticker.Stop()
+		for {
+			mm.sample()
+			select {
+			case <-ticker.C:
+			case <-mm.stop:
+				return
+			}
+		}
+	}()
+	return mm
+}
+
+func (mm *memoryMonitor) sample() {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	mm.peak = max(mm.peak, stats.HeapAlloc)
+}
+
+// Stop ends the sampling and returns the peak heap size in bytes.
+func (mm *memoryMonitor) Stop() uint64 {
+	close(mm.stop)
+	<-mm.done
+	mm.sample()
+	return mm.peak
+}
@@ -0,0 +1,140 @@
+package maps
+
+import (
+	"math"
+
+	m "pfeifer.dev/mapd/math"
+	ms "pfeifer.dev/mapd/settings"
+)
+
+const (
+	// nodes of a connected way the curvature window reaches into
+	CURVATURE_CONNECTION_NODES = 4
+	// curvatures averaged on each side of a node
+	CURVATURE_WINDOW = 2
+	// connected ways turning more than this are another road, not the
+	// continuation of a way
+	CURVATURE_MAX_CONNECTION_TURN = 45 * ms.TO_RADIANS
+)
+
+// areaCurvatures computes the curvature at every node of the ways of an area.
+// The window of the end nodes reaches into the way that continues a way most
+// straightly, so curvatures do not jump where one way ends and the next one
+// starts. Only the ways of the area are considered, which are the ways mapd
+// sees when it loads the area.
+func areaCurvatures(ways []TmpWay) [][]float32 {
+	ends := map[TmpNode][]int{}
+	for i, way := range ways {
+		if len(way.Nodes) < 2 {
+			continue
+		}
+		first, last := way.Nodes[0], way.Nodes[len(way.Nodes)-1]
+		ends[first] = append(ends[first], i)
+		if last != first {
+			ends[last] = append(ends[last], i)
+		}
+	}
+
+	curvatures := make([][]float32, len(ways))
+	for i, way := range ways {
+		if len(way.Nodes) < 2 {
+			continue
+		}
+		nodes := way.Nodes
+		before := continuation(ways, ends, i, nodes[0], nodes[1])
+		after := continuation(ways, ends, i, nodes[len(nodes)-1], nodes[len(nodes)-2])
+		path := make([]m.Position, 0, len(before)+len(nodes)+len(after))
+		for j := len(before) - 1; j >= 0; j-- {
+			path = append(path, nodePosition(before[j]))
+		}
+		for _, node := range nodes {
+			path = append(path, nodePosition(node))
+		}
+		for _, node := range after {
+			path = append(path, nodePosition(node))
+		}
+		averaged := pathCurvatures(path)
+		curvatures[i] = make([]float32, len(nodes))
+		for j := range nodes {
+			curvatures[i][j] = float32(averaged[len(before)+j])
+		}
+	}
+	return curvatures
+}
+
+// continuation returns the nodes of the way that continues way index past
+// end, leaving out end itself. inner is the node of the way next to end.
+func continuation(ways []TmpWay, ends map[TmpNode][]int, index int, end TmpNode, inner TmpNode) []TmpNode {
+	plane := newWayPlane(end)
+	from := plane.project(inner)
+	origin := planePoint{}
+	best := -1
+	bestTurn := CURVATURE_MAX_CONNECTION_TURN
+	bestReversed := false
+	for _, j := range ends[end] {
+		if j == index {
+			continue
+		}
+		nodes := ways[j].Nodes
+		reversed := nodes[0] != end
+		next := nodes[1]
+		if reversed {
+			next = nodes[len(nodes)-2]
+		}
+		turn := turnAngle(from, origin, origin, plane.project(next))
+		if turn < bestTurn || (turn == bestTurn && best >= 0 && ways[j].Id < ways[best].Id) {
+			best, bestTurn, bestReversed = j, turn, reversed
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	nodes := ways[best].Nodes
+	count := min(CURVATURE_CONNECTION_NODES, len(nodes)-1)
+	result := make([]TmpNode, count)
+	for k := range count {
+		if bestReversed {
+			result[k] = nodes[len(nodes)-2-k]
+		} else {
+			result[k] = nodes[1+k]
+		}
+	}
+	return result
+}
+
+// pathCurvatures is the three point curvature at every node of a path,
+// averaged over CURVATURE_WINDOW nodes on each side weighted by arc length
+// like GetAverageCurvatures. The end nodes only get the average of their
+// neighbors.
+func pathCurvatures(path []m.Position) []float64 {
+	raw := make([]m.Curvature, len(path))
+	for i := 1; i < len(path)-1; i++ {
+		raw[i] = m.CalculateCurvature(path[i-1], path[i], path[i+1])
+		if math.IsNaN(raw[i].Curvature) {
+			raw[i] = m.Curvature{Pos: path[i]}
+		}
+		// a straight node has no arc, weigh it by the length it covers
+		if math.IsNaN(raw[i].ArcLength) || math.IsInf(raw[i].ArcLength, 0) {
+			raw[i].ArcLength = float64(path[i-1].DistanceTo(path[i+1]))
+		}
+	}
+	averaged := make([]float64, len(path))
+	for i := range path {
+		sum, weights := 0.0, 0.0
+		first, last := max(1, i-CURVATURE_WINDOW), min(len(path)-2, i+CURVATURE_WINDOW)
+		for j := first; j <= last; j++ {
+			sum += raw[j].Curvature * raw[j].ArcLength
+			weights += raw[j].ArcLength
+		}
+		if weights > 0 {
+			averaged[i] = sum / weights
+		} else if i > 0 && i < len(path)-1 {
+			averaged[i] = raw[i].Curvature
+		}
+	}
+	return averaged
+}
+
+func nodePosition(node TmpNode) m.Position {
+	return m.NewPosition(node.Latitude, node.Longitude)
+}
@@ -0,0 +1,93 @@
+package maps
+
+import (
+	"math"
+	"testing"
+
+	"capnproto.org/go/capnp/v3"
+	"pfeifer.dev/mapd/cereal/offline"
+)
+
+func TestCurvaturesContinueAcrossWays(t *testing.T) {
+	// a 150 m radius curve split into two ways
+	nodes := arcNodes(150, 0, math.Pi/2, 12)
+	ways := []TmpWay{
+		{Id: 1, Nodes: nodes[:7]},
+		// drawn against the direction of the first way
+		{Id: 2, Nodes: reversedNodes(nodes[6:])},
+	}
+	curvatures := areaCurvatures(ways)
+	for i, way := range curvatures {
+		if len(way) != len(ways[i].Nodes) {
+			t.Fatalf("expected a curvature for every node of way %d", i)
+		}
+		for j, curvature := range way {
+			// the first and last node of the curve have no neighbor outside it
+			end := (i == 0 && j == 0) || (i == 1 && j == len(way)-1)
+			if end {
+				continue
+			}
+			if math.Abs(float64(curvature)*150-1) > 0.03 {
+				t.Errorf("way %d node %d: expected a radius of 150 m, got %.1f m", i, j, 1/curvature)
+			}
+		}
+	}
+}
+
+func TestCurvaturesIgnoreCrossingRoads(t *testing.T) {
+	plane := newWayPlane(testOrigin)
+	straight := []TmpNode{}
+	for i := range 5 {
+		straight = append(straight, plane.unproject(planePoint{x: float64(i) * 20}))
+	}
+	side := []TmpNode{straight[4], plane.unproject(planePoint{x: 80, y: 20}), plane.unproject(planePoint{x: 80, y: 40})}
+	curvatures := areaCurvatures([]TmpWay{{Id: 1, Nodes: straight}, {Id: 2, Nodes: side}})
+	for j, curvature := range curvatures[0] {
+		if curvature != 0 {
+			t.Errorf("node %d: expected a straight way next to a side road, got %f", j, curvature)
+		}
+	}
+}
+
+func TestWayReadsStoredCurvatures(t *testing.T) {
+	nodes := arcNodes(150, 0, math.Pi/4, 6)
+	data, err := encodeArea(Area{Ways: []TmpWay{{Id: 1, Nodes: nodes}}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := ReadOffline(data)
+	way := o.Ways.At(0)
+	if !way.HasCurvatures() {
+		t.Fatal("expected the way to have stored curvatures")
+	}
+	for i := 1; i < way.Curvatures.Len()-1; i++ {
+		if math.Abs(way.Curvatures.At(i)*150-1) > 0.03 {
+			t.Errorf("node %d: expected a radius of 150 m, got %.1f m", i, 1/way.Curvatures.At(i))
+		}
+	}
+
+	// files from before curvatures were stored
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := offline.NewWay(seg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.NewNodes(2); err != nil {
+		t.Fatal(err)
+	}
+	oldWay := NewWay(old)
+	if oldWay.HasCurvatures() {
+		t.Error("expected a way without stored curvatures")
+	}
+}
+
+func reversedNodes(nodes []TmpNode) []TmpNode {
+	reversed := make([]TmpNode, len(nodes))
+	for i, node := range nodes {
+		reversed[len(nodes)-1-i] = node
+	}
+	return reversed
+}
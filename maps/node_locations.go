@@ -0,0 +1,129 @@
+package maps
+
+import (
+	"math"
+	"slices"
+
+	"github.com/paulmach/osm"
+	"github.com/pkg/errors"
+)
+
+// nodeLocationSize is the memory a node takes in nodeLocations, its id,
+// latitude and longitude.
+const nodeLocationSize = 24
+
+// nodeLocations holds the locations of the nodes of the kept ways, sorted by
+// id, for pbfs whose ways do not carry them. They are kept in memory, so their
+// size is limited by the memory limit of the generator.
+type nodeLocations struct {
+	ids     []osm.NodeID
+	lats    []float64
+	lons    []float64
+	missing int
+}
+
+func newNodeLocations(ids []osm.NodeID) *nodeLocations {
+	ids = compactNodeIDs(ids)
+	l := &nodeLocations{
+		ids:     ids,
+		lats:    make([]float64, len(ids)),
+		lons:    make([]float64, len(ids)),
+		missing: len(ids),
+	}
+	for i := range l.lats {
+		l.lats[i] = math.NaN()
+	}
+	return l
+}
+
+func compactNodeIDs(ids []osm.NodeID) []osm.NodeID {
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+func (l *nodeLocations) index(id osm.NodeID) (int, bool) {
+	return slices.BinarySearch(l.ids, id)
+}
+
+func (l *nodeLocations) set(node *osm.Node) {
+	i, found := l.index(node.ID)
+	if !found || !math.IsNaN(l.lats[i]) {
+		return
+	}
+	l.lats[i] = node.Lat
+	l.lons[i] = node.Lon
+	l.missing--
+}
+
+// resolve sets the locations of the nodes of a way and drops the nodes whose
+// location is unknown, like ones cut off by an extract.
+func (l *nodeLocations) resolve(way *osm.Way) {
+	nodes := way.Nodes[:0]
+	for _, node := range way.Nodes {
+		i, found := l.index(node.ID)
+		if !found || math.IsNaN(l.lats[i]) {
+			continue
+		}
+		node.Lat = l.lats[i]
+		node.Lon = l.lons[i]
+		nodes = append(nodes, node)
+	}
+	way.Nodes = nodes
+}
+
+// readNodeLocations collects the nodes of the ways the profile keeps and then
+// reads their locations from the nodes of the pbf. It fails when the locations
+// take more than limit bytes, such pbfs need the locations added to their ways
+// first.
+func readNodeLocations(path string, profile *GeneratorProfile, limit int64) (*nodeLocations, error) {
+	if limit <= 0 {
+		limit = DEFAULT_MEMORY_MB * 1024 * 1024
+	}
+	// the ids of shared nodes are collected more than once, so they are
+	// compacted whenever the collected ids fill the limit
+	maxIDs := int(limit / 8)
+	ids := []osm.NodeID{}
+	err := scanPbfWays(path, profile, nil, func(way *osm.Way) error {
+		for _, node := range way.Nodes {
+			ids = append(ids, node.ID)
+		}
+		if len(ids) < maxIDs {
+			return nil
+		}
+		ids = compactNodeIDs(ids)
+		if int64(len(ids))*nodeLocationSize > limit {
+			return errNodeLocationsTooLarge(limit)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	locations := newNodeLocations(ids)
+	if int64(len(locations.ids))*nodeLocationSize > limit {
+		return nil, errNodeLocationsTooLarge(limit)
+	}
+
+	file, scanner, err := openPbf(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner.SkipWays = true
+	// only decode the nodes of kept ways
+	scanner.FilterNode = func(node *osm.Node) bool {
+		_, found := locations.index(node.ID)
+		return found
+	}
+	defer scanner.Close()
+	for scanner.Scan() {
+		if node, ok := scanner.Object().(*osm.Node); ok {
+			locations.set(node)
+		}
+	}
+	return locations, errors.Wrap(scanner.Err(), "could not scan map pbf nodes")
+}
+
+func errNodeLocationsTooLarge(limit int64) error {
+	return errors.Errorf("node locations of the map pbf need more than the memory limit of %d MB, add them to its ways with scripts/add_locations.sh or raise the limit", limit/1024/1024)
+}
@@ -11,32 +11,57 @@ import (
 	"capnproto.org/go/capnp/v3"
 )
 
+// ReadOffline reads offline data from packed or unpacked file contents.
+// Unpacked data is read in place, so data must not change while the offline
+// data or its ways are used.
 func ReadOffline(data []uint8) Offline {
-	msg, err := capnp.UnmarshalPacked(data)
+	var msg *capnp.Message
+	var err error
+	if isUnpacked(data) {
+		msg, err = capnp.Unmarshal(data[len(UNPACKED_MAGIC):])
+	} else {
+		msg, err = capnp.UnmarshalPacked(data)
+	}
 	if err != nil {
 		slog.Warn("could not unmarshal offline data", "error", err)
+		return Offline{Loaded: false}
+	}
+	return readOfflineMessage(msg, nil)
+}
+
+// readOfflineMessage reads the offline data of a message. The offline data
+// takes over the reference to the mapping it was read from when there is one.
+func readOfflineMessage(msg *capnp.Message, mapping *tileMapping) Offline {
+	offlineMaps, err := offline.ReadRootOffline(msg)
+	if err != nil {
+		slog.Warn("could not read offline message", "error", err)
+		return Offline{Loaded: false}
+	}
+	if !offlineMaps.IsValid() {
+		slog.Warn("could not read offline message", "reason", "root is not a struct")
+		return Offline{Loaded: false}
 	}
-	if err == nil {
-		offlineMaps, err := offline.ReadRootOffline(msg)
-		if err != nil {
-			slog.Warn("could not read offline message", "error", err)
-			return Offline{Loaded: false}
-		}
-		if !offlineMaps.IsValid() {
-			slog.Warn("could not read offline message", "reason", "root is not a struct")
-			return Offline{Loaded: false}
-		}
-		// allow us to read as much as we want
-		msg.ResetReadLimit(math.MaxUint64)
-		ways, err := offlineMaps.Ways()
-		if err != nil {
-			slog.Warn("Could not read ways from offline maps", "error", err)
-		}
-		o := Offline{offline: offlineMaps, waysRaw: ways, Loaded: true}
-		o.Ways.Init(o._wayAt, ways.Len())
-		return o
+	format := fileFormat(offlineMaps.Version())
+	if format > OFFLINE_FORMAT_LATEST {
+		slog.Warn("could not read offline message", "reason", "unsupported format", "version", format)
+		return Offline{Loaded: false}
+	}
+	if !offlineMaps.HasHeader() {
+		slog.Warn("offline data has no header, its data date is unknown", "version", format)
+	}
+	// allow us to read as much as we want
+	msg.ResetReadLimit(math.MaxUint64)
+	ways, err := offlineMaps.Ways()
+	if err != nil {
+		slog.Warn("Could not read ways from offline maps", "error", err)
+	}
+	strings, err := offlineMaps.Strings()
+	if err != nil {
+		slog.Warn("Could not read string table from offline maps", "error", err)
 	}
-	return Offline{Loaded: false}
+	o := Offline{offline: offlineMaps, waysRaw: ways, format: format, strings: strings, mapping: mapping, Loaded: true}
+	o.Ways.Init(o._wayAt, ways.Len())
+	return o
 }
 
 type Offline struct {
@@ -46,7 +71,11 @@ type Offline struct {
 	overlapBox u.Curry[m.Box]
 	Ways       u.CurryList[Way]
 	waysRaw    offline.Way_List
+	format     uint16
+	strings    capnp.TextList
 	overlap    u.Curry[float64]
+	header     u.Curry[TileHeader]
+	mapping    *tileMapping
 }
 
 func (o *Offline) _box() m.Box {
@@ -77,6 +106,36 @@ func (o *Offline) Overlap() float64 {
 	return o.overlap.Value(o._overlap)
 }
 
+// Format is the file format of the loaded data.
+func (o *Offline) Format() uint16 {
+	return o.format
+}
+
+func (o *Offline) _header() TileHeader {
+	return readTileHeader(o.offline)
+}
+
+// Header tells where the loaded data comes from, it is empty for files from
+// before headers.
+func (o *Offline) Header() TileHeader {
+	return o.header.Value(o._header)
+}
+
 func (o *Offline) _wayAt(index int) Way {
-	return NewWay(o.waysRaw.At(index))
+	return newWay(o.waysRaw.At(index), o.format, o.strings, o.mapping)
+}
+
+// Holds tells if w was read in place from the same mapping as the offline
+// data, so the offline data must stay open while w is used.
+func (o *Offline) Holds(w Way) bool {
+	return o.mapping != nil && w.mapping == o.mapping
+}
+
+// Close releases the mapping the offline data was read from. Neither the
+// offline data nor its ways can be used after it is closed.
+func (o *Offline) Close() {
+	if o.mapping != nil {
+		o.mapping.release()
+		o.mapping = nil
+	}
 }
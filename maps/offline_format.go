@@ -0,0 +1,113 @@
+package maps
+
+import (
+	"math"
+
+	"capnproto.org/go/capnp/v3"
+	"github.com/pkg/errors"
+	"pfeifer.dev/mapd/cereal/offline"
+	m "pfeifer.dev/mapd/math"
+)
+
+// Versions of the offline file format. v1 stores every node as two Float64
+// and the strings of every way with the way. v2 stores the nodes as int32
+// microdegree deltas and the strings in a table per file.
+const (
+	OFFLINE_FORMAT_V1     uint16 = 1
+	OFFLINE_FORMAT_V2     uint16 = 2
+	OFFLINE_FORMAT_LATEST        = OFFLINE_FORMAT_V2
+	MICRODEGREES                 = 1e6
+)
+
+func (s OfflineSettings) format() uint16 {
+	if s.Format == 0 || s.Legacy {
+		return OFFLINE_FORMAT_V1
+	}
+	return s.Format
+}
+
+// fileFormat is the format of a file, files from before versions are v1.
+func fileFormat(version uint16) uint16 {
+	if version == 0 {
+		return OFFLINE_FORMAT_V1
+	}
+	return version
+}
+
+// stringTable collects the strings of a v2 file. The first entry is the empty
+// string so unset fields point to it.
+type stringTable struct {
+	indexes map[string]uint32
+	values  []string
+}
+
+func newStringTable() *stringTable {
+	return &stringTable{indexes: map[string]uint32{"": 0}, values: []string{""}}
+}
+
+func (t *stringTable) index(value string) uint32 {
+	if index, ok := t.indexes[value]; ok {
+		return index
+	}
+	index := uint32(len(t.values))
+	t.indexes[value] = index
+	t.values = append(t.values, value)
+	return index
+}
+
+func (t *stringTable) write(o offline.Offline) error {
+	list, err := o.NewStrings(int32(len(t.values)))
+	if err != nil {
+		return errors.Wrap(err, "could not create string table")
+	}
+	for i, value := range t.values {
+		err = list.Set(i, value)
+		if err != nil {
+			return errors.Wrap(err, "could not set string table entry")
+		}
+	}
+	return nil
+}
+
+func toMicrodegrees(degrees float64) int32 {
+	return int32(math.Round(degrees * MICRODEGREES))
+}
+
+// setNodeDeltas stores the nodes of a v2 way as the microdegrees of the first
+// node followed by the differences to the previous node.
+func setNodeDeltas(w offline.Way, nodes []TmpNode) error {
+	deltas, err := w.NewNodeDeltas(int32(2 * len(nodes)))
+	if err != nil {
+		return errors.Wrap(err, "could not create way node deltas")
+	}
+	var lastLat, lastLon int32
+	for i, node := range nodes {
+		lat, lon := toMicrodegrees(node.Latitude), toMicrodegrees(node.Longitude)
+		deltas.Set(2*i, lat-lastLat)
+		deltas.Set(2*i+1, lon-lastLon)
+		lastLat, lastLon = lat, lon
+	}
+	return nil
+}
+
+// decodeNodeDeltas returns the nodes of a v2 way.
+func decodeNodeDeltas(deltas capnp.Int32List) []m.Position {
+	nodes := make([]m.Position, deltas.Len()/2)
+	var lat, lon int32
+	for i := range nodes {
+		lat += deltas.At(2 * i)
+		lon += deltas.At(2*i + 1)
+		nodes[i] = m.NewPosition(float64(lat)/MICRODEGREES, float64(lon)/MICRODEGREES)
+	}
+	return nodes
+}
+
+func positionsBox(positions []m.Position) m.Box {
+	minLat, minLon := 90.0, 180.0
+	maxLat, maxLon := -90.0, -180.0
+	for _, p := range positions {
+		minLat, maxLat = min(minLat, p.Lat()), max(maxLat, p.Lat())
+		minLon, maxLon = min(minLon, p.Lon()), max(maxLon, p.Lon())
+	}
+	return m.Box{MinPos: m.NewPosition(minLat, minLon), MaxPos: m.NewPosition(maxLat, maxLon)}
+}
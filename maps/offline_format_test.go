@@ -0,0 +1,94 @@
+package maps
+
+import (
+	"math"
+	"testing"
+
+	"capnproto.org/go/capnp/v3"
+	"pfeifer.dev/mapd/cereal/offline"
+	m "pfeifer.dev/mapd/math"
+)
+
+// encodeArea encodes an area in the default format, for tests written before
+// the format could be picked.
+func encodeArea(area Area, overlap float64) ([]byte, error) {
+	return marshalArea(area, OfflineSettings{Overlap: overlap})
+}
+
+func testArea() Area {
+	area := Area{Box: m.Box{MinPos: m.NewPosition(40, -84), MaxPos: m.NewPosition(40.25, -83.75)}}
+	names := []string{"Main Street", "High Street", "Broad Street"}
+	for _, way := range testWays(300) {
+		way.Name = names[way.Id%3]
+		if way.Id%4 == 0 {
+			way.Hazard = "curve"
+		}
+		area.Ways = append(area.Ways, way)
+	}
+	return area
+}
+
+func TestFormatsReadTheSame(t *testing.T) {
+	area := testArea()
+	v1, err := marshalArea(area, OfflineSettings{Format: OFFLINE_FORMAT_V1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := marshalArea(area, OfflineSettings{Format: OFFLINE_FORMAT_V2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if float64(len(v2)) > 0.7*float64(len(v1)) {
+		t.Errorf("expected v2 to be much smaller than v1, got %d and %d bytes", len(v2), len(v1))
+	}
+
+	o1, o2 := ReadOffline(v1), ReadOffline(v2)
+	if !o1.Loaded || !o2.Loaded {
+		t.Fatal("expected both formats to load")
+	}
+	if o1.Ways.Len() != len(area.Ways) || o2.Ways.Len() != len(area.Ways) {
+		t.Fatalf("expected %d ways, got %d and %d", len(area.Ways), o1.Ways.Len(), o2.Ways.Len())
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) <= 0.5/MICRODEGREES+1e-12 }
+	for i := range area.Ways {
+		w1, w2 := o1.Ways.At(i), o2.Ways.At(i)
+		if w1.WayName() != w2.WayName() || w1.WayRef() != w2.WayRef() || w1.Hazard() != w2.Hazard() {
+			t.Errorf("way %d: strings differ: %q %q %q and %q %q %q", i, w1.WayName(), w1.WayRef(), w1.Hazard(), w2.WayName(), w2.WayRef(), w2.Hazard())
+		}
+		if w1.Id() != w2.Id() || w1.Lanes() != w2.Lanes() || w1.OneWay() != w2.OneWay() {
+			t.Errorf("way %d: fields differ", i)
+		}
+		if w1.Nodes.Len() != w2.Nodes.Len() || !w2.HasCurvatures() {
+			t.Fatalf("way %d: expected %d nodes with curvatures, got %d", i, w1.Nodes.Len(), w2.Nodes.Len())
+		}
+		for j := range w1.Nodes.Len() {
+			p1, p2 := w1.Nodes.At(j), w2.Nodes.At(j)
+			if !near(p1.Lat(), p2.Lat()) || !near(p1.Lon(), p2.Lon()) {
+				t.Errorf("way %d node %d: %v is not %v", i, j, p2, p1)
+			}
+		}
+		b1, b2 := w1.Box(), w2.Box()
+		if !near(b1.MinPos.Lat(), b2.MinPos.Lat()) || !near(b1.MaxPos.Lon(), b2.MaxPos.Lon()) {
+			t.Errorf("way %d: box %v is not %v", i, b2, b1)
+		}
+	}
+}
+
+func TestReadOfflineRejectsNewerFormats(t *testing.T) {
+	msg, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := offline.NewRootOffline(seg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.SetVersion(OFFLINE_FORMAT_LATEST + 1)
+	data, err := msg.MarshalPacked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ReadOffline(data).Loaded {
+		t.Error("expected a newer format not to load")
+	}
+}
@@ -0,0 +1,279 @@
+package maps
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"capnproto.org/go/capnp/v3"
+	"github.com/pkg/errors"
+	m "pfeifer.dev/mapd/math"
+	ms "pfeifer.dev/mapd/settings"
+)
+
+// A pack holds every tile of a group in one file instead of a file per tile.
+// The tiles are unpacked capnp messages at 8 byte aligned offsets after the
+// header, so they are read in place from a memory mapping of the pack without
+// copying.
+
+// packTile is a tile of a pack with its unpacked capnp message.
+type packTile struct {
+	box  m.Box
+	data []byte
+}
+
+type packEntry struct {
+	box    m.Box
+	offset uint64
+	length uint64
+}
+
+// Pack is an open pack of tiles. The tiles read from it hold on to its mapping,
+// so it is only unmapped once the pack and all of its tiles are closed.
+type Pack struct {
+	mapping *tileMapping
+	entries []packEntry
+}
+
+func packPath(box m.Box, s OfflineSettings) string {
+	return filepath.Join(groupDirectory(box, s), ms.PACK_FILE)
+}
+
+// OpenPack maps a pack into memory.
+func OpenPack(path string) (*Pack, error) {
+	mapping, err := mapTile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open pack")
+	}
+	entries, err := parsePack(mapping.data)
+	if err != nil {
+		mapping.release()
+		return nil, err
+	}
+	return &Pack{mapping: mapping, entries: entries}, nil
+}
+
+// parsePack reads the header of a pack.
+func parsePack(data []byte) ([]packEntry, error) {
+	header, err := ms.ReadPackHeader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]packEntry, len(header))
+	for i, e := range header {
+		entries[i] = packEntry{
+			box:    m.Box{MinPos: m.NewPosition(e.Box[0], e.Box[1]), MaxPos: m.NewPosition(e.Box[2], e.Box[3])},
+			offset: e.Offset,
+			length: e.Length,
+		}
+	}
+	return entries, nil
+}
+
+// Close gives back the reference of whoever opened the pack.
+func (p *Pack) Close() {
+	p.mapping.release()
+}
+
+// Len is the number of tiles in the pack.
+func (p *Pack) Len() int {
+	return len(p.entries)
+}
+
+// Box is the box of a tile.
+func (p *Pack) Box(index int) m.Box {
+	return p.entries[index].box
+}
+
+// Tile reads a tile in place. The tile has to be closed once neither it nor its
+// ways are used anymore.
+func (p *Pack) Tile(index int) Offline {
+	e := p.entries[index]
+	msg, err := capnp.Unmarshal(p.mapping.data[e.offset : e.offset+e.length])
+	if err != nil {
+		slog.Warn("could not unmarshal pack tile", "error", err)
+		return Offline{Loaded: false}
+	}
+	o := readOfflineMessage(msg, p.mapping.acquire())
+	if !o.Loaded {
+		p.mapping.release()
+	}
+	return o
+}
+
+// find returns the tile covering pos.
+func (p *Pack) find(pos m.Position) (int, bool) {
+	for i, e := range p.entries {
+		if e.box.PosInside(pos) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func packTiles(data []byte, entries []packEntry) []packTile {
+	tiles := make([]packTile, len(entries))
+	for i, e := range entries {
+		tiles[i] = packTile{box: e.box, data: data[e.offset : e.offset+e.length]}
+	}
+	return tiles
+}
+
+// writePack stores tiles in a pack ordered by their boxes.
+func writePack(path string, tiles []packTile) error {
+	slices.SortFunc(tiles, func(a, b packTile) int {
+		for _, d := range []float64{
+			a.box.MinPos.Lat() - b.box.MinPos.Lat(),
+			a.box.MinPos.Lon() - b.box.MinPos.Lon(),
+			a.box.MaxPos.Lat() - b.box.MaxPos.Lat(),
+			a.box.MaxPos.Lon() - b.box.MaxPos.Lon(),
+		} {
+			if d < 0 {
+				return -1
+			} else if d > 0 {
+				return 1
+			}
+		}
+		return 0
+	})
+	entries := make([]ms.PackEntry, len(tiles))
+	buf := make([]byte, ms.PackHeaderSize(len(tiles)))
+	for i, tile := range tiles {
+		for len(buf)%8 != 0 {
+			buf = append(buf, 0)
+		}
+		entries[i] = ms.PackEntry{
+			Box:    [4]float64{tile.box.MinPos.Lat(), tile.box.MinPos.Lon(), tile.box.MaxPos.Lat(), tile.box.MaxPos.Lon()},
+			Offset: uint64(len(buf)),
+			Length: uint64(len(tile.data)),
+		}
+		buf = append(buf, tile.data...)
+	}
+	copy(buf, ms.EncodePackHeader(entries))
+	tmpPath := path + ".tmp"
+	err := os.WriteFile(tmpPath, buf, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "could not write pack")
+	}
+	return errors.Wrap(os.Rename(tmpPath, path), "could not replace pack")
+}
+
+// packBuilder collects the tiles of a group written in the pack format. The
+// tiles of areas that are not written again are kept from the current pack.
+type packBuilder struct {
+	path  string
+	tiles []packTile
+}
+
+func newPackBuilder(path string) (*packBuilder, error) {
+	b := &packBuilder{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read pack")
+	}
+	entries, err := parsePack(data)
+	if err != nil {
+		return nil, err
+	}
+	b.tiles = packTiles(data, entries)
+	return b, nil
+}
+
+// add replaces the tiles of an area with its leaves.
+func (b *packBuilder) add(area m.Box, leaves []areaLeaf, s OfflineSettings) error {
+	b.tiles = slices.DeleteFunc(b.tiles, func(tile packTile) bool {
+		return area.Contains(tile.box)
+	})
+	for _, leaf := range leaves {
+		msg, err := buildArea(leaf.Area, s)
+		if err != nil {
+			return err
+		}
+		data, err := msg.Marshal()
+		if err != nil {
+			return errors.Wrap(err, "could not marshal offline data")
+		}
+		b.tiles = append(b.tiles, packTile{box: leaf.Box, data: data})
+	}
+	return nil
+}
+
+func (b *packBuilder) write() error {
+	err := os.MkdirAll(filepath.Dir(b.path), 0o775)
+	if err != nil {
+		return errors.Wrap(err, "could not create bounds directory")
+	}
+	return writePack(b.path, b.tiles)
+}
+
+// MAX_OPEN_PACKS is how many packs the loader keeps mapped, enough for the
+// groups around the car when it drives along the edge of a group.
+const MAX_OPEN_PACKS = 4
+
+// openPack is a pack mapped by the loader along with the file it was read
+// from, so a pack that was replaced by a download is mapped again. The loader
+// closes the pack it replaces and the least recently used pack once more than
+// MAX_OPEN_PACKS are open, which are unmapped once their tiles are closed.
+type openPack struct {
+	pack    *Pack
+	size    int64
+	modTime time.Time
+	used    uint64
+}
+
+var (
+	openPacks     = map[string]openPack{}
+	openPacksUsed uint64
+	openPacksLock sync.Mutex
+)
+
+// loadPack returns the pack at path, nil when there is none.
+func loadPack(path string) (*Pack, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not stat pack")
+	}
+	openPacksLock.Lock()
+	defer openPacksLock.Unlock()
+	openPacksUsed++
+	if open, ok := openPacks[path]; ok && open.size == info.Size() && open.modTime.Equal(info.ModTime()) {
+		open.used = openPacksUsed
+		openPacks[path] = open
+		return open.pack, nil
+	}
+	pack, err := OpenPack(path)
+	if err != nil {
+		return nil, err
+	}
+	if open, ok := openPacks[path]; ok {
+		open.pack.Close()
+	}
+	openPacks[path] = openPack{pack: pack, size: info.Size(), modTime: info.ModTime(), used: openPacksUsed}
+	if len(openPacks) > MAX_OPEN_PACKS {
+		closeLeastUsedPack()
+	}
+	return pack, nil
+}
+
+// closeLeastUsedPack closes the pack the loader used longest ago. The lock
+// must be held.
+func closeLeastUsedPack() {
+	oldest := ""
+	for path, open := range openPacks {
+		if oldest == "" || open.used < openPacks[oldest].used {
+			oldest = path
+		}
+	}
+	openPacks[oldest].pack.Close()
+	delete(openPacks, oldest)
+}
@@ -0,0 +1,247 @@
+package maps
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/osm"
+	m "pfeifer.dev/mapd/math"
+	ms "pfeifer.dev/mapd/settings"
+)
+
+func TestPackMatchesFiles(t *testing.T) {
+	ways := denseWays(400)
+	ways = append(ways, osmWay(1000, "Long", osm.WayNode{ID: 5000, Lat: 40.3, Lon: -83.9}, osm.WayNode{ID: 5001, Lat: 41.2, Lon: -82.3}))
+	settings := OfflineSettings{
+		Box:          m.Box{MinPos: m.NewPosition(40, -84), MaxPos: m.NewPosition(42, -82)},
+		Overlap:      0.001,
+		TmpDirectory: t.TempDir(),
+		WaysPerFile:  100,
+	}
+	files := settings
+	files.OutputDirectory = t.TempDir()
+	if err := generate(files, scanWays(ways)); err != nil {
+		t.Fatal(err)
+	}
+	packed := settings
+	packed.OutputDirectory = t.TempDir()
+	packed.Pack = true
+	if err := generate(packed, scanWays(ways)); err != nil {
+		t.Fatal(err)
+	}
+
+	output := readOutput(t, packed.OutputDirectory)
+	if len(output) != 2 {
+		t.Errorf("expected a pack and the way index, got %d files", len(output))
+	}
+	pack, err := OpenPack(packPath(settings.Box, packed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pack.Close()
+	tiles := 0
+	for name, data := range readOutput(t, files.OutputDirectory) {
+		base := filepath.Base(name)
+		if base == WAY_INDEX_FILE || base == TILE_INDEX_FILE {
+			continue
+		}
+		tiles++
+		o := ReadOffline(data)
+		box := o.Box()
+		center := m.NewPosition((box.MinPos.Lat()+box.MaxPos.Lat())/2, (box.MinPos.Lon()+box.MaxPos.Lon())/2)
+		index, found := pack.find(center)
+		if !found || !box.Equals(pack.Box(index)) {
+			t.Fatalf("%s: expected a pack tile with the same box", name)
+		}
+		tile := pack.Tile(index)
+		if tile.Ways.Len() != o.Ways.Len() {
+			t.Errorf("%s: expected %d ways, got %d", name, o.Ways.Len(), tile.Ways.Len())
+			continue
+		}
+		for i := range o.Ways.Len() {
+			w1, w2 := o.Ways.At(i), tile.Ways.At(i)
+			if w1.Id() != w2.Id() || w1.Nodes.Len() != w2.Nodes.Len() {
+				t.Errorf("%s: way %d differs", name, i)
+			}
+		}
+		tile.Close()
+	}
+	if pack.Len() != tiles {
+		t.Errorf("expected %d tiles in the pack, got %d", tiles, pack.Len())
+	}
+}
+
+func TestApplyChangesToPack(t *testing.T) {
+	before := denseWays(300)
+	after := before[1:]
+	settings := OfflineSettings{
+		Box:          m.Box{MinPos: m.NewPosition(40, -84), MaxPos: m.NewPosition(42, -82)},
+		Overlap:      0.001,
+		TmpDirectory: t.TempDir(),
+		WaysPerFile:  100,
+		Pack:         true,
+	}
+	incremental := settings
+	incremental.OutputDirectory = t.TempDir()
+	if err := generate(incremental, scanWays(before)); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := parseChanges(strings.NewReader(`<osmChange version="0.6"><delete><way id="1" version="2"/></delete></osmChange>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := applyChanges(incremental, changes, scanWays(after)); err != nil {
+		t.Fatal(err)
+	}
+	full := settings
+	full.OutputDirectory = t.TempDir()
+	if err := generate(full, scanWays(after)); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.ReadFile(packPath(settings.Box, full))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := os.ReadFile(packPath(settings.Box, incremental))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Error("expected the changed pack to match a full generation")
+	}
+}
+
+func TestParsePackRejectsBadHeaders(t *testing.T) {
+	if _, err := parsePack([]byte("MAPDWIX1\x00\x00\x00\x00\x00\x00\x00\x00")); err == nil {
+		t.Error("expected an unknown magic to fail")
+	}
+	if _, err := parsePack([]byte(ms.PACK_MAGIC + "\x01\x00\x00\x00\x00\x00\x00\x00")); err == nil {
+		t.Error("expected a missing tile entry to fail")
+	}
+	path := filepath.Join(t.TempDir(), ms.PACK_FILE)
+	if err := writePack(path, []packTile{{box: testArea().Box, data: make([]byte, 16)}}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parsePack(data[:len(data)-1]); err == nil {
+		t.Error("expected a tile past the end of the pack to fail")
+	}
+}
+
+func TestReplacedPackIsUnmapped(t *testing.T) {
+	area := testArea()
+	msg, err := buildArea(area, OfflineSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := msg.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), ms.PACK_FILE)
+	if err := writePack(path, []packTile{{box: area.Box, data: data}}); err != nil {
+		t.Fatal(err)
+	}
+	old, err := loadPack(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tile := old.Tile(0)
+	if !tile.Loaded || !tile.Holds(tile.Ways.At(0)) {
+		t.Fatal("expected the tile to be read in place from the pack")
+	}
+
+	if err := writePack(path, []packTile{{box: area.Box, data: data}, {box: area.Box, data: data}}); err != nil {
+		t.Fatal(err)
+	}
+	replaced, err := loadPack(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replaced.Close()
+	if replaced == old || replaced.Len() != 2 {
+		t.Fatal("expected the replaced pack to be mapped again")
+	}
+	if old.mapping.data == nil {
+		t.Fatal("expected the old pack to stay mapped while its tile is open")
+	}
+	tile.Close()
+	if old.mapping.data != nil {
+		t.Error("expected the old pack to be unmapped once its tile is closed")
+	}
+}
+
+// closeOpenPacks closes every pack the loader keeps open.
+func closeOpenPacks() {
+	openPacksLock.Lock()
+	defer openPacksLock.Unlock()
+	for path, open := range openPacks {
+		open.pack.Close()
+		delete(openPacks, path)
+	}
+}
+
+func TestLeastUsedPackIsReleased(t *testing.T) {
+	closeOpenPacks()
+	area := testArea()
+	msg, err := buildArea(area, OfflineSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := msg.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	paths := make([]string, MAX_OPEN_PACKS+1)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("%d%s", i, ms.PACK_FILE))
+		if err := writePack(paths[i], []packTile{{box: area.Box, data: data}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first, err := loadPack(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tile := first.Tile(0)
+	second, err := loadPack(paths[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths[2:MAX_OPEN_PACKS] {
+		if _, err := loadPack(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// using the first pack again leaves the second as the least used one
+	if again, err := loadPack(paths[0]); err != nil || again != first {
+		t.Fatal("expected the first pack to stay open", err)
+	}
+	if _, err := loadPack(paths[MAX_OPEN_PACKS]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := openPacks[paths[1]]; ok || second.mapping.data != nil {
+		t.Error("expected the least used pack to be unmapped")
+	}
+	if _, ok := openPacks[paths[0]]; !ok {
+		t.Fatal("expected the recently used pack to stay open")
+	}
+
+	closeOpenPacks()
+	if first.mapping.data == nil {
+		t.Fatal("expected a closed pack to stay mapped while its tile is open")
+	}
+	tile.Close()
+	if first.mapping.data != nil {
+		t.Error("expected the pack to be unmapped once its tile is closed")
+	}
+}
@@ -0,0 +1,205 @@
+package maps
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/paulmach/osm"
+	"github.com/pkg/errors"
+	"pfeifer.dev/mapd/cereal/offline"
+)
+
+// GeneratorProfile decides which ways the generator keeps and which of their
+// tags end up in the offline data.
+type GeneratorProfile struct {
+	// highway tag values to keep and the class they are stored as, unknown
+	// when empty
+	Highways map[string]string `json:"highways"`
+	// osm tags read for each way field, the first one that is set wins
+	Fields    map[string][]string `json:"fields"`
+	ExtraTags []ExtraTag          `json:"extra_tags"`
+}
+
+// ExtraTag is an osm tag stored with the ways beyond the fields mapd uses.
+type ExtraTag struct {
+	Tag string `json:"tag"`
+	// stored name, the osm tag when empty
+	Key string `json:"key"`
+	// maps osm values to stored values. Values missing from a non empty map
+	// are not stored.
+	Values map[string]string `json:"values"`
+}
+
+type TmpTag struct {
+	Key   string
+	Value string
+}
+
+// the way fields a profile can map osm tags to
+var profileFields = []string{
+	"name",
+	"ref",
+	"hazard",
+	"max_speed",
+	"max_speed_forward",
+	"max_speed_backward",
+	"max_speed_advisory",
+	"max_speed_conditional",
+	"max_speed_forward_conditional",
+	"max_speed_backward_conditional",
+	"lanes",
+	"one_way",
+	"layer",
+	"bridge",
+	"tunnel",
+}
+
+// DefaultProfile keeps the roads of scripts/filter_planet.sh with the tags
+// mapd has always stored.
+func DefaultProfile() *GeneratorProfile {
+	return &GeneratorProfile{
+		Highways: map[string]string{
+			"motorway":       "motorway",
+			"motorway_link":  "motorwayLink",
+			"trunk":          "trunk",
+			"trunk_link":     "trunkLink",
+			"primary":        "primary",
+			"primary_link":   "primaryLink",
+			"secondary":      "secondary",
+			"secondary_link": "secondaryLink",
+			"tertiary":       "tertiary",
+			"tertiary_link":  "tertiaryLink",
+			"unclassified":   "unclassified",
+			"residential":    "residential",
+		},
+		Fields: map[string][]string{
+			"name":                           {"name"},
+			"ref":                            {"ref"},
+			"hazard":                         {"hazard"},
+			"max_speed":                      {"maxspeed"},
+			"max_speed_forward":              {"maxspeed:forward"},
+			"max_speed_backward":             {"maxspeed:backward"},
+			"max_speed_advisory":             {"maxspeed:advisory"},
+			"max_speed_conditional":          {"maxspeed:conditional"},
+			"max_speed_forward_conditional":  {"maxspeed:forward:conditional"},
+			"max_speed_backward_conditional": {"maxspeed:backward:conditional"},
+			"lanes":                          {"lanes"},
+			"one_way":                        {"oneway"},
+			"layer":                          {"layer"},
+			"bridge":                         {"bridge"},
+			"tunnel":                         {"tunnel"},
+		},
+	}
+}
+
+// LoadProfile reads a profile file. Fields it does not map keep their default
+// tags and highways replace the default roads when given.
+func LoadProfile(path string) (*GeneratorProfile, error) {
+	profile := DefaultProfile()
+	if path == "" {
+		return profile, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read generator profile")
+	}
+	var loaded GeneratorProfile
+	err = json.Unmarshal(data, &loaded)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse generator profile")
+	}
+	if loaded.Highways != nil {
+		profile.Highways = loaded.Highways
+	}
+	for field, tags := range loaded.Fields {
+		profile.Fields[field] = tags
+	}
+	profile.ExtraTags = loaded.ExtraTags
+	return profile, profile.validate()
+}
+
+func (p *GeneratorProfile) validate() error {
+	for highway, class := range p.Highways {
+		if class != "" && offline.HighwayClassFromString(class).String() != class {
+			return errors.Errorf("unknown highway class %q for highway %q", class, highway)
+		}
+	}
+	for field := range p.Fields {
+		if !slices.Contains(profileFields, field) {
+			return errors.Errorf("unknown way field %q", field)
+		}
+	}
+	for _, extra := range p.ExtraTags {
+		if extra.Tag == "" {
+			return errors.New("extra tags need a tag")
+		}
+	}
+	return nil
+}
+
+// Includes checks whether the generator keeps a way.
+func (p *GeneratorProfile) Includes(way *osm.Way) bool {
+	_, ok := p.Highways[way.Tags.Find("highway")]
+	return ok
+}
+
+func (p *GeneratorProfile) field(tags map[string]string, field string) string {
+	for _, tag := range p.Fields[field] {
+		if value := tags[tag]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// tmpWay copies what the profile keeps from an osm way. The way must carry the
+// locations of its nodes.
+func (p *GeneratorProfile) tmpWay(way *osm.Way) TmpWay {
+	tags := way.TagMap()
+	lanes, _ := strconv.ParseUint(p.field(tags, "lanes"), 10, 8)
+	tmpWay := TmpWay{
+		Nodes:            make([]TmpNode, len(way.Nodes)),
+		Name:             p.field(tags, "name"),
+		Ref:              p.field(tags, "ref"),
+		Hazard:           p.field(tags, "hazard"),
+		MaxSpeed:         ParseMaxSpeed(p.field(tags, "max_speed")),
+		MaxSpeedForward:  ParseMaxSpeed(p.field(tags, "max_speed_forward")),
+		MaxSpeedBackward: ParseMaxSpeed(p.field(tags, "max_speed_backward")),
+		MaxSpeedAdvisory: ParseMaxSpeed(p.field(tags, "max_speed_advisory")),
+		Lanes:            uint8(lanes),
+		Box:              wayBox(way),
+		OneWay:           p.field(tags, "one_way") == "yes",
+		Id:               int64(way.ID),
+		HighwayClass:     offline.HighwayClassFromString(p.Highways[tags["highway"]]),
+		Layer:            ParseLayer(p.field(tags, "layer")),
+		Bridge:           isStructureTag(p.field(tags, "bridge")),
+		Tunnel:           isStructureTag(p.field(tags, "tunnel")),
+
+		MaxSpeedConditional:         p.field(tags, "max_speed_conditional"),
+		MaxSpeedForwardConditional:  p.field(tags, "max_speed_forward_conditional"),
+		MaxSpeedBackwardConditional: p.field(tags, "max_speed_backward_conditional"),
+	}
+	for i, n := range way.Nodes {
+		tmpWay.Nodes[i].Latitude = n.Lat
+		tmpWay.Nodes[i].Longitude = n.Lon
+	}
+	for _, extra := range p.ExtraTags {
+		value, ok := tags[extra.Tag]
+		if !ok {
+			continue
+		}
+		if len(extra.Values) > 0 {
+			if value, ok = extra.Values[value]; !ok {
+				continue
+			}
+		}
+		key := extra.Key
+		if key == "" {
+			key = extra.Tag
+		}
+		tmpWay.ExtraTags = append(tmpWay.ExtraTags, TmpTag{Key: key, Value: value})
+	}
+	return tmpWay
+}
@@ -0,0 +1,161 @@
+package maps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"capnproto.org/go/capnp/v3"
+	"github.com/paulmach/osm"
+	"pfeifer.dev/mapd/cereal/offline"
+	m "pfeifer.dev/mapd/math"
+)
+
+const testProfile = `{
+  "highways": {"primary": "primary", "service": ""},
+  "fields": {"name": ["name:en", "name"]},
+  "extra_tags": [
+    {"tag": "surface", "values": {"asphalt": "paved", "gravel": "unpaved"}},
+    {"tag": "toll", "key": "toll_road"}
+  ]
+}`
+
+func loadTestProfile(t *testing.T, profile string) (*GeneratorProfile, error) {
+	path := filepath.Join(t.TempDir(), "profile.json")
+	if err := os.WriteFile(path, []byte(profile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadProfile(path)
+}
+
+func TestProfileFiltersAndMapsTags(t *testing.T) {
+	profile, err := loadTestProfile(t, testProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := []osm.WayNode{{ID: 1, Lat: 40.1, Lon: -83.1}, {ID: 2, Lat: 40.2, Lon: -83.2}}
+	service := &osm.Way{ID: 1, Nodes: nodes, Tags: osm.Tags{
+		{Key: "highway", Value: "service"},
+		{Key: "name", Value: "Weg"},
+		{Key: "name:en", Value: "Lane"},
+		{Key: "maxspeed", Value: "20"},
+		{Key: "surface", Value: "asphalt"},
+		{Key: "toll", Value: "yes"},
+	}}
+	primary := &osm.Way{ID: 2, Nodes: nodes, Tags: osm.Tags{
+		{Key: "highway", Value: "primary"},
+		{Key: "name", Value: "Main"},
+		{Key: "surface", Value: "sett"},
+	}}
+	footway := &osm.Way{ID: 3, Nodes: nodes, Tags: osm.Tags{{Key: "highway", Value: "footway"}}}
+	motorway := &osm.Way{ID: 4, Nodes: nodes, Tags: osm.Tags{{Key: "highway", Value: "motorway"}}}
+
+	if !profile.Includes(service) || !profile.Includes(primary) {
+		t.Error("expected the profile highways to be included")
+	}
+	if profile.Includes(footway) || profile.Includes(motorway) {
+		t.Error("expected the profile highways to replace the default roads")
+	}
+
+	way := profile.tmpWay(service)
+	if way.Name != "Lane" {
+		t.Errorf("expected the name from name:en, got %q", way.Name)
+	}
+	if way.HighwayClass != offline.HighwayClass_unknown {
+		t.Errorf("expected an unknown class, got %v", way.HighwayClass)
+	}
+	// fields missing from the profile keep their default tags
+	if way.MaxSpeed == 0 {
+		t.Error("expected the default max speed tag")
+	}
+	expected := []TmpTag{{Key: "surface", Value: "paved"}, {Key: "toll_road", Value: "yes"}}
+	if len(way.ExtraTags) != len(expected) || way.ExtraTags[0] != expected[0] || way.ExtraTags[1] != expected[1] {
+		t.Errorf("expected extra tags %v, got %v", expected, way.ExtraTags)
+	}
+
+	way = profile.tmpWay(primary)
+	if way.Name != "Main" || way.HighwayClass != offline.HighwayClass_primary {
+		t.Errorf("unexpected primary way %+v", way)
+	}
+	// unmapped values are not stored
+	if len(way.ExtraTags) != 0 {
+		t.Errorf("expected no extra tags, got %v", way.ExtraTags)
+	}
+}
+
+func TestLoadProfileRejectsUnknownNames(t *testing.T) {
+	if _, err := loadTestProfile(t, `{"highways": {"service": "driveway"}}`); err == nil {
+		t.Error("expected an unknown highway class to fail")
+	}
+	if _, err := loadTestProfile(t, `{"fields": {"speed": ["maxspeed"]}}`); err == nil {
+		t.Error("expected an unknown field to fail")
+	}
+}
+
+func TestExtraTagsAreWritten(t *testing.T) {
+	profile, err := loadTestProfile(t, testProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	way := osmWay(1, "Lane", osm.WayNode{ID: 1, Lat: 40.1, Lon: -83.9}, osm.WayNode{ID: 2, Lat: 40.15, Lon: -83.85})
+	way.Tags = append(way.Tags, osm.Tag{Key: "toll", Value: "yes"})
+	settings := OfflineSettings{
+		Box:             m.Box{MinPos: m.NewPosition(40, -84), MaxPos: m.NewPosition(40.25, -83.75)},
+		OutputDirectory: t.TempDir(),
+		TmpDirectory:    t.TempDir(),
+		MemoryLimit:     1, // spill to check the bucket encoding
+		Profile:         profile,
+	}
+	if err := generate(settings, scanWays([]*osm.Way{way})); err != nil {
+		t.Fatal(err)
+	}
+	area := Area{Box: settings.Box}
+	data, err := os.ReadFile(GenerateBoundsFileName(area, settings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := capnp.UnmarshalPacked(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := offline.ReadRootOffline(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ways, _ := root.Ways()
+	if ways.Len() != 1 {
+		t.Fatalf("expected 1 way, got %d", ways.Len())
+	}
+	tags, _ := ways.At(0).ExtraTags()
+	if tags.Len() != 1 {
+		t.Fatalf("expected 1 extra tag, got %d", tags.Len())
+	}
+	key, _ := tags.At(0).Key()
+	value, _ := tags.At(0).Value()
+	if key != "toll_road" || value != "yes" {
+		t.Errorf("expected toll_road=yes, got %s=%s", key, value)
+	}
+}
+
+func TestNodeLocationsResolveWays(t *testing.T) {
+	way := &osm.Way{ID: 1, Nodes: osm.WayNodes{{ID: 3}, {ID: 1}, {ID: 2}, {ID: 3}}}
+	locations := newNodeLocations([]osm.NodeID{3, 1, 2, 3})
+	if len(locations.ids) != 3 || locations.missing != 3 {
+		t.Fatalf("expected 3 missing nodes, got %d of %d", locations.missing, len(locations.ids))
+	}
+	locations.set(&osm.Node{ID: 1, Lat: 40, Lon: -83})
+	locations.set(&osm.Node{ID: 3, Lat: 41, Lon: -84})
+	locations.set(&osm.Node{ID: 4, Lat: 42, Lon: -85})
+	if locations.missing != 1 {
+		t.Errorf("expected 1 missing node, got %d", locations.missing)
+	}
+
+	// node 2 is outside the extract and dropped
+	locations.resolve(way)
+	if len(way.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(way.Nodes))
+	}
+	if way.Nodes[0].Lat != 41 || way.Nodes[1].Lon != -83 || way.Nodes[2].ID != 3 {
+		t.Errorf("unexpected nodes %v", way.Nodes)
+	}
+}
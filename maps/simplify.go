@@ -0,0 +1,305 @@
+package maps
+
+import (
+	"log/slog"
+	"math"
+
+	m "pfeifer.dev/mapd/math"
+	ms "pfeifer.dev/mapd/settings"
+)
+
+const (
+	// a kept segment spans at most this much of a curve, so the three point
+	// radius estimate of GetCurvatures always has nodes inside the curve
+	SIMPLIFY_MAX_TURN = 30 * ms.TO_RADIANS
+	// segments turning more than this are corners, not curves, and are not
+	// resampled along an arc
+	RESAMPLE_MAX_ARC = 60 * ms.TO_RADIANS
+	// nodes of a single resampled segment
+	RESAMPLE_MAX_NODES = 64
+)
+
+// planePoint is a node in meters east and north of the first node of a way.
+type planePoint struct {
+	x float64
+	y float64
+}
+
+func (p planePoint) sub(o planePoint) planePoint {
+	return planePoint{x: p.x - o.x, y: p.y - o.y}
+}
+
+func (p planePoint) length() float64 {
+	return math.Hypot(p.x, p.y)
+}
+
+// wayPlane projects the nodes of a way onto a plane around its first node.
+// Ways are short enough for an equirectangular projection.
+type wayPlane struct {
+	origin TmpNode
+	scale  float64 // meters per degree of longitude
+}
+
+func newWayPlane(origin TmpNode) wayPlane {
+	return wayPlane{
+		origin: origin,
+		scale:  ms.R * ms.TO_RADIANS * math.Cos(origin.Latitude*ms.TO_RADIANS),
+	}
+}
+
+func (w wayPlane) project(node TmpNode) planePoint {
+	return planePoint{
+		x: (node.Longitude - w.origin.Longitude) * w.scale,
+		y: (node.Latitude - w.origin.Latitude) * ms.R * ms.TO_RADIANS,
+	}
+}
+
+func (w wayPlane) unproject(p planePoint) TmpNode {
+	return TmpNode{
+		Latitude:  w.origin.Latitude + p.y/(ms.R*ms.TO_RADIANS),
+		Longitude: w.origin.Longitude + p.x/w.scale,
+	}
+}
+
+// segmentDistance is the distance in meters from p to the segment a b.
+func segmentDistance(p, a, b planePoint) float64 {
+	ab := b.sub(a)
+	ap := p.sub(a)
+	lengthSq := ab.x*ab.x + ab.y*ab.y
+	if lengthSq == 0 {
+		return ap.length()
+	}
+	t := max(0, min(1, (ap.x*ab.x+ap.y*ab.y)/lengthSq))
+	return math.Hypot(ap.x-t*ab.x, ap.y-t*ab.y)
+}
+
+// turnAngle is the change of direction between the segments a b and c d.
+func turnAngle(a, b, c, d planePoint) float64 {
+	first := b.sub(a)
+	second := d.sub(c)
+	angle := math.Abs(math.Atan2(second.y, second.x) - math.Atan2(first.y, first.x))
+	if angle > math.Pi {
+		angle = 2*math.Pi - angle
+	}
+	return angle
+}
+
+// simplifyNodes drops the nodes of a way that are within tolerance meters of
+// the simplified line, like Douglas-Peucker, but never lets a kept segment span
+// more than SIMPLIFY_MAX_TURN of a curve. The first and last node are always
+// kept since ways are connected by them.
+func simplifyNodes(nodes []TmpNode, tolerance float64) []TmpNode {
+	if len(nodes) < 3 {
+		return nodes
+	}
+	plane := newWayPlane(nodes[0])
+	points := make([]planePoint, len(nodes))
+	for i, node := range nodes {
+		points[i] = plane.project(node)
+	}
+
+	keep := make([]bool, len(nodes))
+	keep[0] = true
+	keep[len(nodes)-1] = true
+	spans := [][2]int{{0, len(nodes) - 1}}
+	for len(spans) > 0 {
+		span := spans[len(spans)-1]
+		spans = spans[:len(spans)-1]
+		first, last := span[0], span[1]
+		if last-first < 2 {
+			continue
+		}
+		split := -1
+		maxDistance := 0.0
+		for i := first + 1; i < last; i++ {
+			distance := segmentDistance(points[i], points[first], points[last])
+			if distance > maxDistance {
+				split, maxDistance = i, distance
+			}
+		}
+		turn := turnAngle(points[first], points[first+1], points[last-1], points[last])
+		if maxDistance <= tolerance && turn <= SIMPLIFY_MAX_TURN {
+			continue
+		}
+		if split < 0 {
+			// every node is on the chord, split in the middle of the turn
+			split = (first + last) / 2
+		}
+		keep[split] = true
+		spans = append(spans, [2]int{first, split}, [2]int{split, last})
+	}
+
+	simplified := make([]TmpNode, 0, len(nodes))
+	for i, node := range nodes {
+		if keep[i] {
+			simplified = append(simplified, node)
+		}
+	}
+	return simplified
+}
+
+// circle returns the center and radius of the circle through three points.
+// ok is false when they are on a line.
+func circle(a, b, c planePoint) (center planePoint, radius float64, ok bool) {
+	d := 2 * (a.x*(b.y-c.y) + b.x*(c.y-a.y) + c.x*(a.y-b.y))
+	if math.Abs(d) < 1e-9 {
+		return planePoint{}, 0, false
+	}
+	aSq := a.x*a.x + a.y*a.y
+	bSq := b.x*b.x + b.y*b.y
+	cSq := c.x*c.x + c.y*c.y
+	center = planePoint{
+		x: (aSq*(b.y-c.y) + bSq*(c.y-a.y) + cSq*(a.y-b.y)) / d,
+		y: (aSq*(c.x-b.x) + bSq*(a.x-c.x) + cSq*(b.x-a.x)) / d,
+	}
+	return center, a.sub(center).length(), true
+}
+
+// arcSweep is the signed angle from start to end around center that does not
+// pass other.
+func arcSweep(center, start, end, other planePoint) float64 {
+	angle := func(p planePoint) float64 {
+		d := p.sub(center)
+		return math.Atan2(d.y, d.x)
+	}
+	normalize := func(a float64) float64 {
+		for a <= -math.Pi {
+			a += 2 * math.Pi
+		}
+		for a > math.Pi {
+			a -= 2 * math.Pi
+		}
+		return a
+	}
+	sweep := normalize(angle(end) - angle(start))
+	toOther := normalize(angle(other) - angle(start))
+	// other lies within the sweep, go around the other way
+	if toOther*sweep > 0 && math.Abs(toOther) < math.Abs(sweep) {
+		if sweep > 0 {
+			sweep -= 2 * math.Pi
+		} else {
+			sweep += 2 * math.Pi
+		}
+	}
+	return sweep
+}
+
+// resampleNodes adds nodes to segments longer than spacing meters. They are
+// placed on the circle through the segment and its flatter neighbor, so a
+// sparse curve gets evenly spaced nodes with the radius of the curve instead
+// of a few sharp corners. Straight segments and corners are left alone.
+func resampleNodes(nodes []TmpNode, spacing float64) []TmpNode {
+	if len(nodes) < 3 || spacing <= 0 {
+		return nodes
+	}
+	plane := newWayPlane(nodes[0])
+	points := make([]planePoint, len(nodes))
+	for i, node := range nodes {
+		points[i] = plane.project(node)
+	}
+
+	resampled := make([]TmpNode, 0, len(nodes))
+	for i := range len(nodes) - 1 {
+		resampled = append(resampled, nodes[i])
+		start, end := points[i], points[i+1]
+		length := end.sub(start).length()
+		if length <= spacing {
+			continue
+		}
+		var center planePoint
+		var sweep float64
+		radius := 0.0
+		straight := false
+		for _, neighbor := range []int{i - 1, i + 2} {
+			if neighbor < 0 || neighbor >= len(points) {
+				continue
+			}
+			c, r, ok := circle(start, end, points[neighbor])
+			if !ok {
+				straight = true
+				break
+			}
+			s := arcSweep(c, start, end, points[neighbor])
+			if math.Abs(s) > RESAMPLE_MAX_ARC || r <= radius {
+				continue
+			}
+			center, radius, sweep = c, r, s
+		}
+		if straight || radius == 0 {
+			continue
+		}
+		count := min(int(math.Ceil(radius*math.Abs(sweep)/spacing)), RESAMPLE_MAX_NODES)
+		startOffset := start.sub(center)
+		startAngle := math.Atan2(startOffset.y, startOffset.x)
+		for j := 1; j < count; j++ {
+			angle := startAngle + sweep*float64(j)/float64(count)
+			p := planePoint{x: center.x + radius*math.Cos(angle), y: center.y + radius*math.Sin(angle)}
+			resampled = append(resampled, plane.unproject(p))
+		}
+	}
+	return append(resampled, nodes[len(nodes)-1])
+}
+
+// wayGeometry simplifies and resamples the nodes of ways and counts them
+// before and after.
+type wayGeometry struct {
+	nodes     int
+	kept      int
+	enabled   bool
+	tolerance float64
+	spacing   float64
+}
+
+func newWayGeometry(s OfflineSettings) *wayGeometry {
+	return &wayGeometry{
+		enabled:   s.Simplify > 0 || s.Resample > 0,
+		tolerance: s.Simplify,
+		spacing:   s.Resample,
+	}
+}
+
+// apply simplifies and then resamples the nodes of a way and updates its box.
+func (g *wayGeometry) apply(way *TmpWay) {
+	if !g.enabled {
+		return
+	}
+	g.nodes += len(way.Nodes)
+	if g.tolerance > 0 {
+		way.Nodes = simplifyNodes(way.Nodes, g.tolerance)
+	}
+	way.Nodes = resampleNodes(way.Nodes, g.spacing)
+	g.kept += len(way.Nodes)
+	way.Box = nodesBox(way.Nodes)
+}
+
+// reduction is the share of the nodes that was removed, negative when
+// resampling added more than simplification removed.
+func (g *wayGeometry) reduction() float64 {
+	if g.nodes == 0 {
+		return 0
+	}
+	return 1 - float64(g.kept)/float64(g.nodes)
+}
+
+// report logs the node reduction. The bytes it saves depend on the file
+// format, so they are left to the size of the written files.
+func (g *wayGeometry) report() {
+	if !g.enabled {
+		return
+	}
+	slog.Info("Simplified Ways",
+		"nodes", g.nodes,
+		"kept_nodes", g.kept,
+		"reduction_percent", math.Round(g.reduction()*1000)/10,
+	)
+}
+
+func nodesBox(nodes []TmpNode) m.Box {
+	minLat, minLon := 90.0, 180.0
+	maxLat, maxLon := -90.0, -180.0
+	for _, n := range nodes {
+		minLat, maxLat = min(minLat, n.Latitude), max(maxLat, n.Latitude)
+		minLon, maxLon = min(minLon, n.Longitude), max(maxLon, n.Longitude)
+	}
+	return m.Box{MinPos: m.NewPosition(minLat, minLon), MaxPos: m.NewPosition(maxLat, maxLon)}
+}
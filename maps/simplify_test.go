@@ -0,0 +1,132 @@
+package maps
+
+import (
+	"math"
+	"testing"
+
+	m "pfeifer.dev/mapd/math"
+)
+
+var testOrigin = TmpNode{Latitude: 40, Longitude: -83}
+
+// arcNodes places count+1 nodes on a circle of radius meters around the
+// origin, from the angle start through sweep radians.
+func arcNodes(radius, start, sweep float64, count int) []TmpNode {
+	plane := newWayPlane(testOrigin)
+	nodes := make([]TmpNode, 0, count+1)
+	for i := range count + 1 {
+		angle := start + sweep*float64(i)/float64(count)
+		nodes = append(nodes, plane.unproject(planePoint{x: radius * math.Cos(angle), y: radius * math.Sin(angle)}))
+	}
+	return nodes
+}
+
+func position(node TmpNode) m.Position {
+	return m.NewPosition(node.Latitude, node.Longitude)
+}
+
+// checkRadius verifies the three point radius at every interior node.
+func checkRadius(t *testing.T, nodes []TmpNode, radius float64) {
+	for i := 1; i < len(nodes)-1; i++ {
+		curvature := m.CalculateCurvature(position(nodes[i-1]), position(nodes[i]), position(nodes[i+1]))
+		if math.Abs(1/curvature.Curvature-radius) > radius*0.02 {
+			t.Errorf("node %d: expected a radius of %.0f m, got %.1f m", i, radius, 1/curvature.Curvature)
+		}
+	}
+}
+
+func TestSimplifyKeepsCurveRadius(t *testing.T) {
+	// a 100 m radius curve with a node every meter
+	nodes := arcNodes(100, 0, math.Pi/2, 157)
+	simplified := simplifyNodes(nodes, 0.5)
+	if len(simplified) > len(nodes)/10 {
+		t.Errorf("expected the curve to be simplified, kept %d of %d nodes", len(simplified), len(nodes))
+	}
+	if len(simplified) < 4 {
+		t.Fatalf("expected at least 4 nodes on a 90 degree curve, got %d", len(simplified))
+	}
+	if simplified[0] != nodes[0] || simplified[len(simplified)-1] != nodes[len(nodes)-1] {
+		t.Error("expected the end nodes to be kept")
+	}
+	checkRadius(t, simplified, 100)
+
+	// a wide tolerance would cut the curve down to a corner without the turn limit
+	simplified = simplifyNodes(nodes, 50)
+	plane := newWayPlane(testOrigin)
+	for i := 1; i < len(simplified)-1; i++ {
+		turn := turnAngle(plane.project(simplified[i-1]), plane.project(simplified[i]), plane.project(simplified[i]), plane.project(simplified[i+1]))
+		if turn > SIMPLIFY_MAX_TURN+1e-9 {
+			t.Errorf("node %d turns %.1f degrees", i, turn/math.Pi*180)
+		}
+	}
+	checkRadius(t, simplified, 100)
+}
+
+func TestSimplifyStraightWay(t *testing.T) {
+	plane := newWayPlane(testOrigin)
+	nodes := []TmpNode{}
+	for i := range 50 {
+		nodes = append(nodes, plane.unproject(planePoint{x: float64(i) * 10, y: 0.1 * float64(i%2)}))
+	}
+	simplified := simplifyNodes(nodes, 1)
+	if len(simplified) != 2 {
+		t.Errorf("expected a straight way to keep its end nodes only, got %d nodes", len(simplified))
+	}
+}
+
+func TestResampleSparseCurve(t *testing.T) {
+	// a 200 m radius curve drawn with 30 degree chords
+	nodes := arcNodes(200, 0, math.Pi/2, 3)
+	resampled := resampleNodes(nodes, 20)
+	if len(resampled) < 15 {
+		t.Errorf("expected nodes to be added to the curve, got %d", len(resampled))
+	}
+	plane := newWayPlane(testOrigin)
+	for i, node := range resampled {
+		if distance := plane.project(node).length(); math.Abs(distance-200) > 0.5 {
+			t.Errorf("node %d is %.1f m from the center of the curve", i, distance)
+		}
+	}
+	checkRadius(t, resampled, 200)
+	for _, node := range nodes {
+		found := false
+		for _, r := range resampled {
+			found = found || r == node
+		}
+		if !found {
+			t.Errorf("expected the original node %v to be kept", node)
+		}
+	}
+}
+
+func TestResampleKeepsCornersAndStraights(t *testing.T) {
+	plane := newWayPlane(testOrigin)
+	corner := []TmpNode{
+		plane.unproject(planePoint{x: 0, y: 0}),
+		plane.unproject(planePoint{x: 100, y: 0}),
+		plane.unproject(planePoint{x: 100, y: 100}),
+	}
+	if resampled := resampleNodes(corner, 20); len(resampled) != len(corner) {
+		t.Errorf("expected a corner to be kept as is, got %d nodes", len(resampled))
+	}
+	straight := []TmpNode{
+		plane.unproject(planePoint{x: 0, y: 0}),
+		plane.unproject(planePoint{x: 100, y: 0}),
+		plane.unproject(planePoint{x: 200, y: 0}),
+	}
+	if resampled := resampleNodes(straight, 20); len(resampled) != len(straight) {
+		t.Errorf("expected a straight way to be kept as is, got %d nodes", len(resampled))
+	}
+}
+
+func TestWayGeometryUpdatesBox(t *testing.T) {
+	way := TmpWay{Nodes: arcNodes(100, 0, math.Pi/2, 157)}
+	geometry := newWayGeometry(OfflineSettings{Simplify: 0.5, Resample: 30})
+	geometry.apply(&way)
+	if geometry.reduction() <= 0.5 {
+		t.Errorf("expected more than half of the nodes to be removed, got %.2f", geometry.reduction())
+	}
+	if !way.Box.Equals(nodesBox(way.Nodes)) {
+		t.Error("expected the box of the simplified nodes")
+	}
+}
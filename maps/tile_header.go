@@ -0,0 +1,111 @@
+package maps
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"runtime/debug"
+	"time"
+
+	"github.com/pkg/errors"
+	"pfeifer.dev/mapd/cereal/offline"
+)
+
+// TileHeader records where the data of the offline files comes from.
+type TileHeader struct {
+	DataTimestamp    time.Time // osm replication timestamp of the input, zero when unknown
+	GeneratorVersion string
+	SourceHash       string // sha256 of the input pbf, empty until it was read once
+}
+
+func NewTileHeader() *TileHeader {
+	return &TileHeader{GeneratorVersion: generatorVersion()}
+}
+
+// generatorVersion is the git revision mapd was built from.
+func generatorVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	revision, modified := "", false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		if info.Main.Version == "" || info.Main.Version == "(devel)" {
+			return "unknown"
+		}
+		return info.Main.Version
+	}
+	if modified {
+		return revision + "-dirty"
+	}
+	return revision
+}
+
+// header returns the tile header of the settings, the generator version alone
+// when there is none.
+func (s OfflineSettings) header() TileHeader {
+	if s.Header == nil {
+		return TileHeader{GeneratorVersion: generatorVersion()}
+	}
+	return *s.Header
+}
+
+// sourceHash hashes the input while it is scanned for the first time.
+type sourceHash struct {
+	header *TileHeader
+	hash   hash.Hash
+}
+
+func newSourceHash(header *TileHeader) *sourceHash {
+	if header == nil || header.SourceHash != "" {
+		return nil
+	}
+	return &sourceHash{header: header, hash: sha256.New()}
+}
+
+func (h *sourceHash) finish() {
+	h.header.SourceHash = hex.EncodeToString(h.hash.Sum(nil))
+}
+
+func (h TileHeader) write(o offline.Offline) error {
+	header, err := o.NewHeader()
+	if err != nil {
+		return errors.Wrap(err, "could not create offline header")
+	}
+	if !h.DataTimestamp.IsZero() {
+		header.SetDataTimestamp(h.DataTimestamp.Unix())
+	}
+	err = header.SetGeneratorVersion(h.GeneratorVersion)
+	if err != nil {
+		return errors.Wrap(err, "could not set offline generator version")
+	}
+	err = header.SetSourceHash(h.SourceHash)
+	return errors.Wrap(err, "could not set offline source hash")
+}
+
+// readTileHeader returns the header of an offline file, an empty one for files
+// from before headers.
+func readTileHeader(o offline.Offline) TileHeader {
+	if !o.HasHeader() {
+		return TileHeader{}
+	}
+	raw, err := o.Header()
+	if err != nil {
+		return TileHeader{}
+	}
+	header := TileHeader{}
+	if raw.DataTimestamp() != 0 {
+		header.DataTimestamp = time.Unix(raw.DataTimestamp(), 0).UTC()
+	}
+	header.GeneratorVersion, _ = raw.GeneratorVersion()
+	header.SourceHash, _ = raw.SourceHash()
+	return header
+}
@@ -0,0 +1,41 @@
+package maps
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestTileHeaderIsReadBack(t *testing.T) {
+	sum := sha256.Sum256([]byte("planet"))
+	header := &TileHeader{
+		DataTimestamp:    time.Date(2025, 3, 2, 1, 0, 0, 0, time.UTC),
+		GeneratorVersion: "abc123",
+		SourceHash:       hex.EncodeToString(sum[:]),
+	}
+	for _, format := range []uint16{OFFLINE_FORMAT_V1, OFFLINE_FORMAT_V2} {
+		data, err := marshalArea(testArea(), OfflineSettings{Format: format, Header: header})
+		if err != nil {
+			t.Fatal(err)
+		}
+		o := ReadOffline(data)
+		if o.Format() != format {
+			t.Errorf("expected format %d, got %d", format, o.Format())
+		}
+		read := o.Header()
+		if !read.DataTimestamp.Equal(header.DataTimestamp) || read.GeneratorVersion != header.GeneratorVersion || read.SourceHash != header.SourceHash {
+			t.Errorf("format %d: expected header %+v, got %+v", format, *header, read)
+		}
+	}
+
+	data, err := marshalArea(testArea(), OfflineSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := ReadOffline(data)
+	read := o.Header()
+	if !read.DataTimestamp.IsZero() || read.GeneratorVersion == "" {
+		t.Errorf("expected an unknown data date and the generator version, got %+v", read)
+	}
+}
@@ -0,0 +1,85 @@
+package maps
+
+import (
+	"bytes"
+	"os"
+	"sync/atomic"
+
+	"capnproto.org/go/capnp/v3"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// Unpacked offline files start with UNPACKED_MAGIC followed by the unpacked
+// capnp message, which is 8 byte aligned after the magic. mapd maps them into
+// memory and reads them in place instead of reading and unpacking the whole
+// file on every tile switch.
+const UNPACKED_MAGIC = "MAPDRAW1"
+
+func isUnpacked(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(UNPACKED_MAGIC))
+}
+
+// tileMapping is a memory mapped file that offline data is read from in place.
+// Every offline data read from it holds a reference, which is given back with
+// Offline.Close. The file is unmapped once the last reference is released.
+type tileMapping struct {
+	data []byte
+	refs atomic.Int32
+}
+
+func mapTile(path string) (*tileMapping, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open offline data file")
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not stat offline data file")
+	}
+	t := &tileMapping{}
+	t.refs.Store(1)
+	if info.Size() == 0 {
+		return t, nil
+	}
+	t.data, err = unix.Mmap(int(f.Fd()), 0, int(info.Size()), unix.PROT_READ, unix.MAP_SHARED)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not map offline data file")
+	}
+	return t, nil
+}
+
+func (t *tileMapping) acquire() *tileMapping {
+	t.refs.Add(1)
+	return t
+}
+
+func (t *tileMapping) release() {
+	if t.refs.Add(-1) == 0 && t.data != nil {
+		unix.Munmap(t.data)
+		t.data = nil
+	}
+}
+
+// LoadOffline reads an offline file. Unpacked files are read in place from a
+// memory mapping, packed files are unpacked into memory. The offline data has
+// to be closed once neither it nor its ways are used anymore.
+func LoadOffline(path string) (Offline, error) {
+	mapping, err := mapTile(path)
+	if err != nil {
+		return Offline{Loaded: false}, err
+	}
+	if !isUnpacked(mapping.data) {
+		// unpacking copies the data, so the mapping is not needed after it
+		o := ReadOffline(mapping.data)
+		mapping.release()
+		return o, nil
+	}
+	msg, err := capnp.Unmarshal(mapping.data[len(UNPACKED_MAGIC):])
+	if err != nil {
+		mapping.release()
+		return Offline{Loaded: false}, errors.Wrap(err, "could not unmarshal offline data")
+	}
+	return readOfflineMessage(msg, mapping), nil
+}
@@ -0,0 +1,134 @@
+package maps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	m "pfeifer.dev/mapd/math"
+	ms "pfeifer.dev/mapd/settings"
+)
+
+// fixtureArea is a dense tile like the ones over a metro area.
+func fixtureArea() Area {
+	area := Area{Box: m.Box{MinPos: m.NewPosition(40, -84), MaxPos: m.NewPosition(40.25, -83.75)}}
+	area.Ways = testWays(5000)
+	return area
+}
+
+func writeFixture(t testing.TB, area Area, s OfflineSettings) string {
+	s.OutputDirectory = t.TempDir()
+	if err := writeArea(area, s); err != nil {
+		t.Fatal(err)
+	}
+	return GenerateBoundsFileName(area, s)
+}
+
+func TestLoadOfflineReadsUnpackedFiles(t *testing.T) {
+	area := testArea()
+	packed, err := LoadOffline(writeFixture(t, area, OfflineSettings{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unpacked, err := LoadOffline(writeFixture(t, area, OfflineSettings{Unpacked: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !packed.Loaded || !unpacked.Loaded {
+		t.Fatal("expected both files to load")
+	}
+	if packed.mapping != nil || unpacked.mapping == nil {
+		t.Error("expected only the unpacked file to stay mapped")
+	}
+	if packed.Ways.Len() != unpacked.Ways.Len() {
+		t.Fatalf("expected %d ways, got %d", packed.Ways.Len(), unpacked.Ways.Len())
+	}
+	for i := range packed.Ways.Len() {
+		w1, w2 := packed.Ways.At(i), unpacked.Ways.At(i)
+		b1, b2 := w1.Box(), w2.Box()
+		if w1.Id() != w2.Id() || w1.WayName() != w2.WayName() || !b1.Equals(b2) {
+			t.Errorf("way %d differs", i)
+		}
+		if !unpacked.Holds(w2) || packed.Holds(w1) {
+			t.Errorf("way %d does not hold on to the mapping", i)
+		}
+	}
+	mapping := unpacked.mapping
+	unpacked.Close()
+	if mapping.data != nil || unpacked.mapping != nil {
+		t.Error("expected closing the offline data to unmap the file")
+	}
+	packed.Close()
+
+	data, err := marshalArea(area, OfflineSettings{Unpacked: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o := ReadOffline(data); o.Ways.Len() != packed.Ways.Len() {
+		t.Errorf("expected ReadOffline to read unpacked data, got %d ways", o.Ways.Len())
+	}
+
+	if _, err := LoadOffline(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a missing file error, got %v", err)
+	}
+}
+
+// checkTile makes sure a benchmarked tile loaded. The ways are read the same
+// way for every kind of file, so only loading the tile is measured.
+func checkTile(b *testing.B, o Offline) {
+	if !o.Loaded || o.Ways.Len() == 0 {
+		b.Fatal("expected the tile to load")
+	}
+}
+
+func BenchmarkLoadPackedTile(b *testing.B) {
+	path := writeFixture(b, fixtureArea(), OfflineSettings{})
+	b.ReportAllocs()
+	for b.Loop() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		checkTile(b, ReadOffline(data))
+	}
+}
+
+func BenchmarkLoadMappedTile(b *testing.B) {
+	path := writeFixture(b, fixtureArea(), OfflineSettings{Unpacked: true})
+	b.ReportAllocs()
+	for b.Loop() {
+		o, err := LoadOffline(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		checkTile(b, o)
+		o.Close()
+	}
+}
+
+func BenchmarkLoadPackTile(b *testing.B) {
+	area := fixtureArea()
+	s := OfflineSettings{OutputDirectory: b.TempDir(), Pack: true}
+	builder, err := newPackBuilder(packPath(area.Box, s))
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := builder.add(area.Box, []areaLeaf{{Area: area}}, s); err != nil {
+		b.Fatal(err)
+	}
+	if err := builder.write(); err != nil {
+		b.Fatal(err)
+	}
+	pack, err := OpenPack(filepath.Join(groupDirectory(area.Box, s), ms.PACK_FILE))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer pack.Close()
+	b.ReportAllocs()
+	for b.Loop() {
+		tile := pack.Tile(0)
+		checkTile(b, tile)
+		tile.Close()
+	}
+}
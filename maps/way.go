@@ -4,6 +4,7 @@ import (
 	"math"
 	"strings"
 
+	"capnproto.org/go/capnp/v3"
 	"github.com/pkg/errors"
 
 	"pfeifer.dev/mapd/cereal/log"
@@ -116,11 +117,20 @@ type Way struct {
 	box              u.Curry[m.Box]
 	Nodes            u.CurryList[m.Position]
 	nodesRaw         offline.Coordinates_List
+	Curvatures       u.CurryList[float64]
+	curvaturesRaw    capnp.Float32List
+	format           uint16
+	strings          capnp.TextList
+	mapping          *tileMapping
 	lanes            u.Curry[int]
 	advisorySpeed    u.Curry[float64]
 	hazard           u.Curry[string]
 	maxSpeedForward  u.Curry[float64]
 	maxSpeedBackward u.Curry[float64]
+	layer            u.Curry[int]
+	bridge           u.Curry[bool]
+	tunnel           u.Curry[bool]
+	level            u.Curry[int]
 
 	maxSpeedConditional           u.Curry[string]
 	maxSpeedForwardConditional    u.Curry[string]
@@ -132,15 +142,59 @@ type Way struct {
 }
 
 func NewWay(way offline.Way) Way {
-	nodes, _ := way.Nodes()
+	return newWay(way, OFFLINE_FORMAT_V1, capnp.TextList{}, nil)
+}
+
+// newWay reads a way of a file with the given format and string table.
+func newWay(way offline.Way, format uint16, strings capnp.TextList, mapping *tileMapping) Way {
 	w := Way{
-		Way: way,
+		Way:     way,
+		format:  format,
+		strings: strings,
+		mapping: mapping,
+	}
+	if format == OFFLINE_FORMAT_V2 {
+		deltas, _ := way.NodeDeltas()
+		nodes := decodeNodeDeltas(deltas)
+		w.Nodes.Init(func(index int) m.Position { return nodes[index] }, len(nodes))
+	} else {
+		nodes, _ := way.Nodes()
+		w.nodesRaw = nodes
+		w.Nodes.Init(w._nodeAt, nodes.Len())
+	}
+	// files generated before curvatures were stored have none
+	curvatures, _ := way.NodeCurvatures()
+	if curvatures.Len() == w.Nodes.Len() {
+		w.curvaturesRaw = curvatures
+		w.Curvatures.Init(w._curvatureAt, curvatures.Len())
+	} else {
+		w.Curvatures.Init(w._curvatureAt, 0)
 	}
-	w.nodesRaw = nodes
-	w.Nodes.Init(w._nodeAt, nodes.Len())
 	return w
 }
 
+// text looks up a string of a v2 file.
+func (w *Way) text(index uint32) string {
+	if int(index) >= w.strings.Len() {
+		return ""
+	}
+	value, err := w.strings.At(int(index))
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+func (w *Way) _curvatureAt(index int) float64 {
+	return float64(w.curvaturesRaw.At(index))
+}
+
+// HasCurvatures reports whether the offline file stores the curvature at every
+// node of the way.
+func (w *Way) HasCurvatures() bool {
+	return w.Nodes.Len() > 0 && w.Curvatures.Len() == w.Nodes.Len()
+}
+
 func (w *Way) IsForwardFrom(matchNode m.Position) bool {
 	if w.Nodes.Len() == 0 {
 		return true
@@ -190,6 +244,9 @@ func (w *Way) Id() int64 {
 }
 
 func (w *Way) _wayName() string {
+	if w.format == OFFLINE_FORMAT_V2 {
+		return w.text(w.Way.NameIndex())
+	}
 	wn, err := w.Way.Name()
 	if err != nil {
 		wn = ""
@@ -202,6 +259,9 @@ func (w *Way) WayName() string {
 }
 
 func (w *Way) _wayRef() string {
+	if w.format == OFFLINE_FORMAT_V2 {
+		return w.text(w.Way.RefIndex())
+	}
 	wr, err := w.Way.Ref()
 	if err != nil {
 		wr = ""
@@ -301,6 +361,10 @@ func (w *Way) ConditionalSpeedRules(isForward bool) []ConditionalSpeedRule {
 }
 
 func (w *Way) _box() m.Box {
+	// v2 files leave the box to the nodes
+	if w.format == OFFLINE_FORMAT_V2 {
+		return positionsBox(w.Nodes.Slice())
+	}
 	return m.Box{
 		MinPos: m.NewPosition(w.Way.MinLat(), w.Way.MinLon()),
 		MaxPos: m.NewPosition(w.Way.MaxLat(), w.Way.MaxLon()),
@@ -328,6 +392,9 @@ func (w *Way) AdvisorySpeed() float64 {
 }
 
 func (w *Way) _hazard() string {
+	if w.format == OFFLINE_FORMAT_V2 {
+		return w.text(w.Way.HazardIndex())
+	}
 	hazard, err := w.Way.Hazard()
 	if err != nil {
 		hazard = ""
@@ -339,6 +406,51 @@ func (w *Way) Hazard() string {
 	return w.hazard.Value(w._hazard)
 }
 
+func (w *Way) _layer() int {
+	return int(w.Way.Layer())
+}
+
+func (w *Way) Layer() int {
+	return w.layer.Value(w._layer)
+}
+
+func (w *Way) _bridge() bool {
+	return w.Way.Bridge()
+}
+
+func (w *Way) Bridge() bool {
+	return w.bridge.Value(w._bridge)
+}
+
+func (w *Way) _tunnel() bool {
+	return w.Way.Tunnel()
+}
+
+func (w *Way) Tunnel() bool {
+	return w.tunnel.Value(w._tunnel)
+}
+
+// the vertical level of the way relative to ground level. An explicit layer
+// tag wins, otherwise bridges are assumed to be one level up and tunnels one
+// level down as osm implies for untagged structures.
+func (w *Way) _level() int {
+	layer := w.Layer()
+	if layer != 0 {
+		return layer
+	}
+	if w.Bridge() {
+		return 1
+	}
+	if w.Tunnel() {
+		return -1
+	}
+	return 0
+}
+
+func (w *Way) Level() int {
+	return w.level.Value(w._level)
+}
+
 func (w *Way) OnWay(location log.GpsLocationData, distanceMultiplier float32) (OnWayResult, error) {
 	res := OnWayResult{}
 	pos := m.NewPosition(location.Latitude(), location.Longitude())
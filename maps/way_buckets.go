@@ -0,0 +1,396 @@
+package maps
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pkg/errors"
+	"pfeifer.dev/mapd/cereal/offline"
+	m "pfeifer.dev/mapd/math"
+	ms "pfeifer.dev/mapd/settings"
+)
+
+const (
+	areasPerGroup      = int(ms.GROUP_AREA_BOX_DEGREES / ms.AREA_BOX_DEGREES)
+	groupsPerRow       = longitudeAreas / areasPerGroup
+	DEFAULT_MEMORY_MB  = 512
+	bucketFileSuffix   = ".ways"
+	bucketFileReadSize = 1024 * 1024
+)
+
+// areaSelection holds the areas inside the generated box.
+type areaSelection struct {
+	overlap  float64
+	selected map[int]bool // area index -> written
+}
+
+func newAreaSelection(s OfflineSettings) areaSelection {
+	a := areaSelection{overlap: s.Overlap, selected: map[int]bool{}}
+	overlapBox := s.Box.Overlap(s.Overlap)
+	minLat, maxLat := areaIndexRange(overlapBox.MinPos.Lat(), overlapBox.MaxPos.Lat(), -90, latitudeAreas)
+	minLon, maxLon := areaIndexRange(overlapBox.MinPos.Lon(), overlapBox.MaxPos.Lon(), -180, longitudeAreas)
+	for i := minLat; i <= maxLat; i++ {
+		for j := minLon; j <= maxLon; j++ {
+			if overlapBox.Contains(areaBox(i, j)) {
+				a.selected[i*longitudeAreas+j] = true
+			}
+		}
+	}
+	return a
+}
+
+// restrict drops every area that is not in keep from the selection.
+func (a areaSelection) restrict(keep map[int]bool) {
+	for index := range a.selected {
+		if !keep[index] {
+			delete(a.selected, index)
+		}
+	}
+}
+
+// overlapping lists the selected areas a way with the given box is written to
+// in index order.
+func (a areaSelection) overlapping(wayBox m.Box) []int {
+	box := wayBox.Overlap(a.overlap)
+	minLat, maxLat := areaIndexRange(box.MinPos.Lat(), box.MaxPos.Lat(), -90, latitudeAreas)
+	minLon, maxLon := areaIndexRange(box.MinPos.Lon(), box.MaxPos.Lon(), -180, longitudeAreas)
+	areas := []int{}
+	for i := minLat; i <= maxLat; i++ {
+		for j := minLon; j <= maxLon; j++ {
+			index := i*longitudeAreas + j
+			if !a.selected[index] {
+				continue
+			}
+			area := Area{Box: areaBox(i, j)}
+			if wayBox.Overlapping(area.OverlapBox(a.overlap)) {
+				areas = append(areas, index)
+			}
+		}
+	}
+	return areas
+}
+
+// areaIndexRange returns the indices of the areas that may touch from to to
+// along one axis, with a margin of one area for rounding.
+func areaIndexRange(from float64, to float64, origin float64, count int) (int, int) {
+	first := int(math.Floor((from-origin)/ms.AREA_BOX_DEGREES)) - 1
+	last := int(math.Floor((to-origin)/ms.AREA_BOX_DEGREES)) + 1
+	return clamp(first, 0, count-1), clamp(last, 0, count-1)
+}
+
+func clamp(value int, low int, high int) int {
+	return min(max(value, low), high)
+}
+
+func areaGroup(index int) int {
+	i, j := index/longitudeAreas, index%longitudeAreas
+	return (i/areasPerGroup)*groupsPerRow + j/areasPerGroup
+}
+
+// wayBuckets sorts the scanned ways by the groups of the areas they overlap so
+// every area is written from a small list of ways instead of all of them. The
+// ways are kept encoded in scan order and spilled to one file per group once
+// the buffered data outgrows the memory limit.
+type wayBuckets struct {
+	selection areaSelection
+	areas     map[int][]int // group -> indices of the areas to write
+	buckets   map[int]*wayBucket
+	buffered  int64
+	limit     int64
+	dir       string
+	ways      int
+	spilled   int64
+	index     *wayIndexRuns // the areas of the added ways, when generating the way index
+}
+
+type wayBucket struct {
+	data    []byte
+	spilled bool
+}
+
+func newWayBuckets(s OfflineSettings, selection areaSelection) (*wayBuckets, error) {
+	dir, err := os.MkdirTemp(s.TmpDirectory, "mapd-generate-")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create way bucket directory")
+	}
+	b := &wayBuckets{
+		selection: selection,
+		areas:     map[int][]int{},
+		buckets:   map[int]*wayBucket{},
+		limit:     s.MemoryLimit,
+		dir:       dir,
+	}
+	if b.limit <= 0 {
+		b.limit = DEFAULT_MEMORY_MB * 1024 * 1024
+	}
+	for index := range selection.selected {
+		group := areaGroup(index)
+		b.areas[group] = append(b.areas[group], index)
+	}
+	return b, nil
+}
+
+// Add stores a way in the bucket of every group with an area it overlaps and
+// returns those areas.
+func (b *wayBuckets) Add(way TmpWay) ([]int, error) {
+	areas := b.selection.overlapping(way.Box)
+	var encoded []byte
+	added := []int{}
+	for _, index := range areas {
+		group := areaGroup(index)
+		if slices.Contains(added, group) {
+			continue
+		}
+		bucket, ok := b.buckets[group]
+		if !ok {
+			bucket = &wayBucket{}
+			b.buckets[group] = bucket
+		}
+		if encoded == nil {
+			encoded = appendWay(nil, way)
+		}
+		bucket.data = append(bucket.data, encoded...)
+		b.buffered += int64(len(encoded))
+		added = append(added, group)
+	}
+	if b.index != nil {
+		b.buffered += b.index.add(way.Id, areas)
+	}
+	b.ways++
+	if b.buffered > b.limit {
+		return areas, b.spill()
+	}
+	return areas, nil
+}
+
+func (b *wayBuckets) bucketPath(group int) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%d%s", group, bucketFileSuffix))
+}
+
+// spill appends every buffered bucket to its file and writes the buffered way
+// index entries to a run.
+func (b *wayBuckets) spill() error {
+	for group, bucket := range b.buckets {
+		if len(bucket.data) == 0 {
+			continue
+		}
+		f, err := os.OpenFile(b.bucketPath(group), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return errors.Wrap(err, "could not open way bucket")
+		}
+		_, err = f.Write(bucket.data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return errors.Wrap(err, "could not spill way bucket")
+		}
+		b.spilled += int64(len(bucket.data))
+		bucket.data = nil
+		bucket.spilled = true
+	}
+	if b.index != nil {
+		if err := b.index.spill(); err != nil {
+			return err
+		}
+	}
+	b.buffered = 0
+	return nil
+}
+
+// Groups lists the groups with areas to write in order.
+func (b *wayBuckets) Groups() []int {
+	groups := make([]int, 0, len(b.areas))
+	for group := range b.areas {
+		groups = append(groups, group)
+	}
+	slices.Sort(groups)
+	return groups
+}
+
+// Areas lists the indices of the areas of a group to write in order.
+func (b *wayBuckets) Areas(group int) []int {
+	areas := slices.Clone(b.areas[group])
+	slices.Sort(areas)
+	return areas
+}
+
+// Ways decodes the ways of a group in scan order and releases its buffer. It
+// may be called for different groups at the same time once scanning is done.
+func (b *wayBuckets) Ways(group int) ([]TmpWay, error) {
+	bucket, ok := b.buckets[group]
+	if !ok {
+		return nil, nil
+	}
+	ways := []TmpWay{}
+	if bucket.spilled {
+		f, err := os.Open(b.bucketPath(group))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not open way bucket")
+		}
+		defer f.Close()
+		ways, err = readWays(bufio.NewReaderSize(f, bucketFileReadSize), ways)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ways, err := readWays(bufio.NewReader(bytes.NewReader(bucket.data)), ways)
+	bucket.data = nil
+	return ways, err
+}
+
+// Close removes the spilled buckets.
+func (b *wayBuckets) Close() error {
+	return errors.Wrap(os.RemoveAll(b.dir), "could not remove way buckets")
+}
+
+func appendString(buf []byte, value string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+func appendFloat(buf []byte, value float64) []byte {
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(value))
+}
+
+func appendBool(buf []byte, value bool) []byte {
+	if value {
+		return append(buf, 1)
+	}
+	return append(buf, 0)
+}
+
+// appendWay encodes a way for a bucket. Floats keep their exact bits so the
+// written areas do not depend on whether a bucket was spilled.
+func appendWay(buf []byte, way TmpWay) []byte {
+	buf = binary.AppendVarint(buf, way.Id)
+	buf = appendString(buf, way.Name)
+	buf = appendString(buf, way.Ref)
+	buf = appendString(buf, way.Hazard)
+	buf = appendString(buf, way.MaxSpeedConditional)
+	buf = appendString(buf, way.MaxSpeedForwardConditional)
+	buf = appendString(buf, way.MaxSpeedBackwardConditional)
+	buf = appendFloat(buf, way.MaxSpeed)
+	buf = appendFloat(buf, way.MaxSpeedForward)
+	buf = appendFloat(buf, way.MaxSpeedBackward)
+	buf = appendFloat(buf, way.MaxSpeedAdvisory)
+	buf = append(buf, way.Lanes, byte(way.Layer))
+	buf = binary.AppendUvarint(buf, uint64(way.HighwayClass))
+	buf = appendBool(buf, way.OneWay)
+	buf = appendBool(buf, way.Bridge)
+	buf = appendBool(buf, way.Tunnel)
+	buf = appendFloat(buf, way.Box.MinPos.Lat())
+	buf = appendFloat(buf, way.Box.MinPos.Lon())
+	buf = appendFloat(buf, way.Box.MaxPos.Lat())
+	buf = appendFloat(buf, way.Box.MaxPos.Lon())
+	buf = binary.AppendUvarint(buf, uint64(len(way.Nodes)))
+	for _, node := range way.Nodes {
+		buf = appendFloat(buf, node.Latitude)
+		buf = appendFloat(buf, node.Longitude)
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(way.ExtraTags)))
+	for _, tag := range way.ExtraTags {
+		buf = appendString(buf, tag.Key)
+		buf = appendString(buf, tag.Value)
+	}
+	return buf
+}
+
+// wayReader decodes ways and keeps the first error.
+type wayReader struct {
+	r   *bufio.Reader
+	err error
+	buf [8]byte
+}
+
+func (r *wayReader) readUvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	value, err := binary.ReadUvarint(r.r)
+	r.err = err
+	return value
+}
+
+func (r *wayReader) readString() string {
+	n := r.readUvarint()
+	if r.err != nil || n == 0 {
+		return ""
+	}
+	value := make([]byte, n)
+	_, r.err = io.ReadFull(r.r, value)
+	return string(value)
+}
+
+func (r *wayReader) readFloat() float64 {
+	if r.err != nil {
+		return 0
+	}
+	_, r.err = io.ReadFull(r.r, r.buf[:])
+	return math.Float64frombits(binary.LittleEndian.Uint64(r.buf[:]))
+}
+
+func (r *wayReader) readByte() byte {
+	if r.err != nil {
+		return 0
+	}
+	value, err := r.r.ReadByte()
+	r.err = err
+	return value
+}
+
+func readWays(br *bufio.Reader, ways []TmpWay) ([]TmpWay, error) {
+	r := &wayReader{r: br}
+	for {
+		id, err := binary.ReadVarint(br)
+		if err == io.EOF {
+			return ways, nil
+		}
+		if err != nil {
+			return ways, errors.Wrap(err, "could not read way bucket")
+		}
+		way := TmpWay{Id: id}
+		way.Name = r.readString()
+		way.Ref = r.readString()
+		way.Hazard = r.readString()
+		way.MaxSpeedConditional = r.readString()
+		way.MaxSpeedForwardConditional = r.readString()
+		way.MaxSpeedBackwardConditional = r.readString()
+		way.MaxSpeed = r.readFloat()
+		way.MaxSpeedForward = r.readFloat()
+		way.MaxSpeedBackward = r.readFloat()
+		way.MaxSpeedAdvisory = r.readFloat()
+		way.Lanes = r.readByte()
+		way.Layer = int8(r.readByte())
+		way.HighwayClass = offline.HighwayClass(r.readUvarint())
+		way.OneWay = r.readByte() == 1
+		way.Bridge = r.readByte() == 1
+		way.Tunnel = r.readByte() == 1
+		minLat, minLon := r.readFloat(), r.readFloat()
+		maxLat, maxLon := r.readFloat(), r.readFloat()
+		way.Box = m.Box{MinPos: m.NewPosition(minLat, minLon), MaxPos: m.NewPosition(maxLat, maxLon)}
+		way.Nodes = make([]TmpNode, r.readUvarint())
+		for i := range way.Nodes {
+			way.Nodes[i].Latitude = r.readFloat()
+			way.Nodes[i].Longitude = r.readFloat()
+		}
+		if tags := r.readUvarint(); tags > 0 {
+			way.ExtraTags = make([]TmpTag, tags)
+			for i := range way.ExtraTags {
+				way.ExtraTags[i].Key = r.readString()
+				way.ExtraTags[i].Value = r.readString()
+			}
+		}
+		if r.err != nil {
+			return ways, errors.Wrap(r.err, "could not read way bucket")
+		}
+		ways = append(ways, way)
+	}
+}
@@ -0,0 +1,373 @@
+package maps
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// The way index is stored in the output directory next to the group
+// directories, so it is not part of the compressed group archives.
+const (
+	WAY_INDEX_FILE  = "way_index"
+	wayIndexMagic   = "MAPDWIX1"
+	wayIndexBufSize = 1024 * 1024
+
+	wayIndexRunSuffix   = ".index"
+	wayIndexRunReadSize = 64 * 1024
+	wayIndexEntrySize   = 16 // bytes of a wayIndexEntry
+)
+
+// wayIndex records the areas every way was written to, so changes to a way
+// can be applied later without regenerating every area.
+type wayIndex struct {
+	entries []wayIndexEntry
+	areas   []int32
+}
+
+type wayIndexEntry struct {
+	id    int64
+	first int32 // offset of the areas of the way
+	count int32
+}
+
+func wayIndexPath(s OfflineSettings) string {
+	return filepath.Join(s.OutputDirectory, WAY_INDEX_FILE)
+}
+
+func (w *wayIndex) add(id int64, areas []int) {
+	if len(areas) == 0 {
+		return
+	}
+	w.entries = append(w.entries, wayIndexEntry{id: id, first: int32(len(w.areas)), count: int32(len(areas))})
+	for _, area := range areas {
+		w.areas = append(w.areas, int32(area))
+	}
+}
+
+func (w *wayIndex) sort() {
+	slices.SortFunc(w.entries, func(a, b wayIndexEntry) int {
+		if a.id < b.id {
+			return -1
+		} else if a.id > b.id {
+			return 1
+		}
+		return 0
+	})
+}
+
+// lookup returns the areas of a way. The index must be sorted.
+func (w *wayIndex) lookup(id int64) []int32 {
+	i, found := slices.BinarySearchFunc(w.entries, id, func(e wayIndexEntry, id int64) int {
+		if e.id < id {
+			return -1
+		} else if e.id > id {
+			return 1
+		}
+		return 0
+	})
+	if !found {
+		return nil
+	}
+	e := w.entries[i]
+	return w.areas[e.first : e.first+e.count]
+}
+
+// merge returns an index with the entries of updated replacing the ways in
+// changed.
+func (w *wayIndex) merge(changed map[int64]bool, updated *wayIndex) *wayIndex {
+	merged := &wayIndex{}
+	for _, e := range w.entries {
+		if changed[e.id] {
+			continue
+		}
+		merged.addAreas(e.id, w.areas[e.first:e.first+e.count])
+	}
+	for _, e := range updated.entries {
+		merged.addAreas(e.id, updated.areas[e.first:e.first+e.count])
+	}
+	merged.sort()
+	return merged
+}
+
+func (w *wayIndex) addAreas(id int64, areas []int32) {
+	w.entries = append(w.entries, wayIndexEntry{id: id, first: int32(len(w.areas)), count: int32(len(areas))})
+	w.areas = append(w.areas, areas...)
+}
+
+// write stores the sorted index with delta encoded ids and areas.
+func (w *wayIndex) write(path string) error {
+	w.sort()
+	out, err := createWayIndex(path, len(w.entries))
+	if err != nil {
+		return err
+	}
+	for _, e := range w.entries {
+		if err := out.add(e.id, w.areas[e.first:e.first+e.count]); err != nil {
+			out.abort()
+			return err
+		}
+	}
+	return out.close()
+}
+
+// wayIndexWriter streams sorted entries to a temporary file that replaces the
+// index once it is closed.
+type wayIndexWriter struct {
+	path   string
+	f      *os.File
+	out    *bufio.Writer
+	buf    []byte
+	lastId int64
+}
+
+func createWayIndex(path string, count int) (*wayIndexWriter, error) {
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create way index")
+	}
+	buf := []byte(wayIndexMagic)
+	buf = binary.AppendUvarint(buf, uint64(count))
+	return &wayIndexWriter{path: path, f: f, out: bufio.NewWriterSize(f, wayIndexBufSize), buf: buf}, nil
+}
+
+func (w *wayIndexWriter) add(id int64, areas []int32) error {
+	w.buf = binary.AppendVarint(w.buf, id-w.lastId)
+	w.lastId = id
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(areas)))
+	lastArea := int32(0)
+	for _, area := range areas {
+		w.buf = binary.AppendVarint(w.buf, int64(area-lastArea))
+		lastArea = area
+	}
+	if len(w.buf) > wayIndexBufSize/2 {
+		if _, err := w.out.Write(w.buf); err != nil {
+			return errors.Wrap(err, "could not write way index")
+		}
+		w.buf = w.buf[:0]
+	}
+	return nil
+}
+
+func (w *wayIndexWriter) close() error {
+	_, err := w.out.Write(w.buf)
+	if err == nil {
+		err = w.out.Flush()
+	}
+	if closeErr := w.f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(w.f.Name())
+		return errors.Wrap(err, "could not write way index")
+	}
+	return errors.Wrap(os.Rename(w.f.Name(), w.path), "could not replace way index")
+}
+
+// abort drops the temporary file and keeps the previous index.
+func (w *wayIndexWriter) abort() {
+	w.f.Close()
+	os.Remove(w.f.Name())
+}
+
+// wayIndexReader streams the entries of an index in id order.
+type wayIndexReader struct {
+	f     *os.File
+	in    *bufio.Reader
+	count uint64
+	read  uint64
+	id    int64
+}
+
+func openWayIndex(path string, bufSize int) (*wayIndexReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open way index")
+	}
+	in := bufio.NewReaderSize(f, bufSize)
+	magic := make([]byte, len(wayIndexMagic))
+	if _, err := io.ReadFull(in, magic); err != nil || string(magic) != wayIndexMagic {
+		f.Close()
+		return nil, errors.New("not a way index")
+	}
+	count, err := binary.ReadUvarint(in)
+	if err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "could not read way index")
+	}
+	return &wayIndexReader{f: f, in: in, count: count}, nil
+}
+
+// next appends the areas of the next way to areas and returns io.EOF after
+// the last way.
+func (r *wayIndexReader) next(areas []int32) (int64, []int32, error) {
+	if r.read == r.count {
+		return 0, areas, io.EOF
+	}
+	r.read++
+	delta, err := binary.ReadVarint(r.in)
+	if err != nil {
+		return 0, areas, errors.Wrap(err, "could not read way index")
+	}
+	r.id += delta
+	count, err := binary.ReadUvarint(r.in)
+	if err != nil {
+		return 0, areas, errors.Wrap(err, "could not read way index")
+	}
+	area := int64(0)
+	for range count {
+		delta, err := binary.ReadVarint(r.in)
+		if err != nil {
+			return 0, areas, errors.Wrap(err, "could not read way index")
+		}
+		area += delta
+		areas = append(areas, int32(area))
+	}
+	return r.id, areas, nil
+}
+
+func (r *wayIndexReader) Close() error {
+	return r.f.Close()
+}
+
+func readWayIndex(path string) (*wayIndex, error) {
+	r, err := openWayIndex(path, wayIndexBufSize)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	w := &wayIndex{entries: make([]wayIndexEntry, 0, r.count)}
+	for {
+		first := int32(len(w.areas))
+		id, areas, err := r.next(w.areas)
+		if err == io.EOF {
+			return w, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		w.areas = areas
+		w.entries = append(w.entries, wayIndexEntry{id: id, first: first, count: int32(len(w.areas)) - first})
+	}
+}
+
+// wayIndexRuns builds the index of a full generation in sorted runs, so the
+// areas of every way are not kept in memory until the end. The runs are spilled
+// with the way buckets and merged into the index once every way is added.
+type wayIndexRuns struct {
+	dir   string
+	run   wayIndex
+	runs  []string
+	count int
+}
+
+// add records the areas of a way and returns the bytes it buffered.
+func (r *wayIndexRuns) add(id int64, areas []int) int64 {
+	if len(areas) == 0 {
+		return 0
+	}
+	r.run.add(id, areas)
+	r.count++
+	return wayIndexEntrySize + int64(len(areas))*4
+}
+
+// spill writes the buffered entries to a new run.
+func (r *wayIndexRuns) spill() error {
+	if len(r.run.entries) == 0 {
+		return nil
+	}
+	path := filepath.Join(r.dir, fmt.Sprintf("%d%s", len(r.runs), wayIndexRunSuffix))
+	if err := r.run.write(path); err != nil {
+		return err
+	}
+	r.runs = append(r.runs, path)
+	r.run = wayIndex{}
+	return nil
+}
+
+// write merges the runs into the index at path.
+func (r *wayIndexRuns) write(path string) error {
+	if err := r.spill(); err != nil {
+		return err
+	}
+	cursors := make(wayIndexCursors, 0, len(r.runs))
+	defer func() {
+		for _, c := range cursors {
+			c.reader.Close()
+		}
+	}()
+	for _, run := range r.runs {
+		reader, err := openWayIndex(run, wayIndexRunReadSize)
+		if err != nil {
+			return err
+		}
+		c := &wayIndexCursor{reader: reader}
+		cursors = append(cursors, c)
+		if err := c.advance(); err != nil {
+			return err
+		}
+	}
+	out, err := createWayIndex(path, r.count)
+	if err != nil {
+		return err
+	}
+	merging := slices.Clone(cursors)
+	merging = slices.DeleteFunc(merging, func(c *wayIndexCursor) bool { return c.done })
+	heap.Init(&merging)
+	for len(merging) > 0 {
+		c := merging[0]
+		err := out.add(c.id, c.areas)
+		if err == nil {
+			err = c.advance()
+		}
+		if err != nil {
+			out.abort()
+			return err
+		}
+		if c.done {
+			heap.Pop(&merging)
+		} else {
+			heap.Fix(&merging, 0)
+		}
+	}
+	return out.close()
+}
+
+// wayIndexCursor holds the next entry of a run while the runs are merged.
+type wayIndexCursor struct {
+	reader *wayIndexReader
+	id     int64
+	areas  []int32
+	done   bool
+}
+
+func (c *wayIndexCursor) advance() error {
+	id, areas, err := c.reader.next(c.areas[:0])
+	if err == io.EOF {
+		c.done = true
+		return nil
+	}
+	c.id, c.areas = id, areas
+	return err
+}
+
+// wayIndexCursors is a heap of cursors ordered by their next way id.
+type wayIndexCursors []*wayIndexCursor
+
+func (h wayIndexCursors) Len() int           { return len(h) }
+func (h wayIndexCursors) Less(i, j int) bool { return h[i].id < h[j].id }
+func (h wayIndexCursors) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *wayIndexCursors) Push(x any)        { *h = append(*h, x.(*wayIndexCursor)) }
+func (h *wayIndexCursors) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,60 @@
+package maps
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	m "pfeifer.dev/mapd/math"
+)
+
+func TestWayIndexRunsMatchIndexInMemory(t *testing.T) {
+	s := OfflineSettings{
+		Box:          m.Box{MinPos: m.NewPosition(40, -84), MaxPos: m.NewPosition(42, -81)},
+		Overlap:      0.01,
+		MemoryLimit:  16 * 1024,
+		TmpDirectory: t.TempDir(),
+	}
+	selection := newAreaSelection(s)
+	buckets, err := newWayBuckets(s, selection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buckets.Close()
+	buckets.index = &wayIndexRuns{dir: buckets.dir}
+
+	expected := &wayIndex{}
+	ways := testWays(500)
+	// the runs are merged by id, so add the ways out of order
+	slices.Reverse(ways)
+	for _, way := range ways {
+		if _, err := buckets.Add(way); err != nil {
+			t.Fatal(err)
+		}
+		expected.add(way.Id, selection.overlapping(way.Box))
+	}
+	if len(buckets.index.runs) < 2 {
+		t.Fatalf("expected the index to be spilled in runs, got %d", len(buckets.index.runs))
+	}
+
+	path := filepath.Join(t.TempDir(), WAY_INDEX_FILE)
+	if err := buckets.index.write(path); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := readWayIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected.sort()
+	if len(actual.entries) != len(expected.entries) {
+		t.Fatalf("expected %d ways, got %d", len(expected.entries), len(actual.entries))
+	}
+	for i, e := range expected.entries {
+		if actual.entries[i].id != e.id {
+			t.Fatalf("expected way %d at %d, got %d", e.id, i, actual.entries[i].id)
+		}
+		if !slices.Equal(actual.lookup(e.id), expected.lookup(e.id)) {
+			t.Errorf("way %d: expected areas %v, got %v", e.id, expected.lookup(e.id), actual.lookup(e.id))
+		}
+	}
+}
@@ -15,8 +15,13 @@ func GetStateCurvatures(state *State) ([]m.Curvature, error) {
 	all_nodes := [][]m.Position{nodes}
 	all_nodes_direction := []bool{state.CurrentWay.OnWay.IsForward}
 	all_nodes_is_merge_or_split := []bool{false}
+	// curvatures stored in the offline files are used when every way has them
+	precomputed := state.CurrentWay.Way.HasCurvatures()
+	all_curvatures := [][]float64{state.CurrentWay.Way.Curvatures.Slice()}
 	lastWay := state.CurrentWay.Way
 	for _, nextWay := range state.NextWays {
+		precomputed = precomputed && nextWay.Way.HasCurvatures()
+		all_curvatures = append(all_curvatures, nextWay.Way.Curvatures.Slice())
 		nwNodes := nextWay.Way.Nodes.Slice()
 		if len(nwNodes) > 0 {
 			num_points += len(nwNodes) - 1
@@ -28,6 +33,7 @@ func GetStateCurvatures(state *State) ([]m.Curvature, error) {
 	}
 
 	positions := make([]m.Position, num_points)
+	stored := make([]float64, num_points)
 
 	merge_or_split_nodes := []int{}
 	all_nodes_idx := 0
@@ -48,6 +54,9 @@ func GetStateCurvatures(state *State) ([]m.Curvature, error) {
 		}
 		node := all_nodes[all_nodes_idx][index]
 		positions[i] = node
+		if precomputed {
+			stored[i] = all_curvatures[all_nodes_idx][index]
+		}
 
 		nodes_idx += 1
 		if nodes_idx == len(all_nodes[all_nodes_idx]) || (nodes_idx == len(all_nodes[all_nodes_idx])-1 && all_nodes_idx > 0) {
@@ -59,16 +68,70 @@ func GetStateCurvatures(state *State) ([]m.Curvature, error) {
 		}
 	}
 
+	straight := straightNodes(positions, merge_or_split_nodes)
+
+	if precomputed {
+		curvatures, err := GetStoredCurvatures(positions, stored)
+		if err != nil {
+			return []m.Curvature{}, errors.Wrap(err, "could not get curvatures from points")
+		}
+		// stored curvatures are already averaged, only the ends the averaging drops
+		// are left out so both give a curvature for the same positions
+		if len(curvatures) < 3 {
+			return []m.Curvature{}, errors.New("could not get average curvatures from curvatures: not enough curvatures to average")
+		}
+		average_curvatures := curvatures[1 : len(curvatures)-1]
+		// the merge nodes are set straight before averaging, which the stored
+		// curvatures cannot be, so the averages that include them are computed
+		// from the positions like without stored curvatures
+		for i := range average_curvatures {
+			if !straight[i] && !straight[i+1] && !straight[i+2] {
+				continue
+			}
+			window, err := GetCurvatures(positions[i : i+5])
+			if err != nil {
+				return []m.Curvature{}, errors.Wrap(err, "could not get curvatures from points")
+			}
+			for j := range window {
+				if straight[i+j] {
+					window[j].Curvature = 0.0015
+				}
+			}
+			average, err := GetAverageCurvatures(window)
+			if err != nil {
+				return []m.Curvature{}, errors.Wrap(err, "could not get average curvatures from curvatures")
+			}
+			average_curvatures[i] = average[0]
+		}
+		return average_curvatures, nil
+	}
+
 	curvatures, err := GetCurvatures(positions)
 	if err != nil {
 		return []m.Curvature{}, errors.Wrap(err, "could not get curvatures from points")
 	}
+	for i := range curvatures {
+		if straight[i] {
+			curvatures[i].Curvature = 0.0015
+		}
+	}
+
+	average_curvatures, err := GetAverageCurvatures(curvatures)
+	if err != nil {
+		return []m.Curvature{}, errors.Wrap(err, "could not get average curvatures from curvatures")
+	}
+	return average_curvatures, nil
+}
 
-	// set the merge nodes to be straight to help balance out issues with map representation
+// straightNodes marks the curvatures around merge and split nodes that are set
+// to be straight to help balance out issues with map representation. The
+// curvatures line up with GetCurvatures of the positions.
+func straightNodes(positions []m.Position, merge_or_split_nodes []int) []bool {
+	straight := make([]bool, max(len(positions)-2, 0))
 	for _, merge_or_split_node := range merge_or_split_nodes {
 		if merge_or_split_node >= 2 {
-			curvatures[merge_or_split_node-2].Curvature = 0.0015
-			curvatures[merge_or_split_node-1].Curvature = 0.0015
+			straight[merge_or_split_node-2] = true
+			straight[merge_or_split_node-1] = true
 		}
 		// also include nodes within 15 meters
 		for i := merge_or_split_node - 3; i >= 0; i-- {
@@ -76,22 +139,17 @@ func GetStateCurvatures(state *State) ([]m.Curvature, error) {
 			if positions[merge_or_split_node].DistanceTo(positions[i]) > 15 {
 				break
 			}
-			curvatures[i].Curvature = 0.0015
+			straight[i] = true
 		}
 		// also include forward nodes within 15 meters
-		for i := merge_or_split_node; i < len(curvatures); i++ {
+		for i := merge_or_split_node; i < len(straight); i++ {
 			if positions[merge_or_split_node].DistanceTo(positions[i]) > 15 {
 				break
 			}
-			curvatures[i].Curvature = 0.0015
+			straight[i] = true
 		}
 	}
-
-	average_curvatures, err := GetAverageCurvatures(curvatures)
-	if err != nil {
-		return []m.Curvature{}, errors.Wrap(err, "could not get average curvatures from curvatures")
-	}
-	return average_curvatures, nil
+	return straight
 }
 
 type Velocity struct {
@@ -161,3 +219,18 @@ func GetCurvatures(positions []m.Position) (curvatures []m.Curvature, err error)
 
 	return curvatures, nil
 }
+
+// GetStoredCurvatures lines up the curvatures stored for the positions of a
+// path like GetCurvatures does, without the first and last position.
+func GetStoredCurvatures(positions []m.Position, stored []float64) (curvatures []m.Curvature, err error) {
+	if len(positions) < 3 {
+		return []m.Curvature{}, errors.New(fmt.Sprintf("not enough points to calculate curvatures. len(points): %d", len(positions)))
+	}
+	curvatures = make([]m.Curvature, len(positions)-2)
+
+	for i := 0; i < len(positions)-2; i++ {
+		curvatures[i] = m.Curvature{Pos: positions[i+1], Curvature: stored[i+1]}
+	}
+
+	return curvatures, nil
+}
@@ -72,3 +72,14 @@ func (p *Position) VectorTo(end Position) Vector {
 	res.Y = m.Cos(p.LatRad())*m.Sin(end.LatRad()) - (m.Sin(p.LatRad()) * m.Cos(end.LatRad()) * m.Cos(dlon))
 	return res
 }
+
+// Project returns the position reached by travelling distance meters from p
+// along the given bearing in degrees.
+func (p *Position) Project(distance float64, bearingDeg float64) Position {
+	angular := distance / ms.R
+	bearing := bearingDeg * ms.TO_RADIANS
+	lat := m.Asin(m.Sin(p.LatRad())*m.Cos(angular) + m.Cos(p.LatRad())*m.Sin(angular)*m.Cos(bearing))
+	lon := p.LonRad() + m.Atan2(m.Sin(bearing)*m.Sin(angular)*m.Cos(p.LatRad()), m.Cos(angular)-m.Sin(p.LatRad())*m.Sin(lat))
+	lonDeg := m.Mod(lon*ms.TO_DEGREES+540, 360) - 180
+	return NewPosition(lat*ms.TO_DEGREES, lonDeg)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"capnproto.org/go/capnp/v3"
+	"pfeifer.dev/mapd/cereal/offline"
+	"pfeifer.dev/mapd/maps"
+)
+
+// arcWay is a part of a left curve with a radius of 100 meters and a node
+// every 5 meters, stored with the given curvature at every node unless it is
+// 0.
+func arcWay(t *testing.T, lanes uint8, first int, count int, stored float32) maps.Way {
+	t.Helper()
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := offline.NewRootWay(seg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.SetLanes(lanes)
+	w.SetOneWay(true)
+	nodes, err := w.NewNodes(int32(count))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range count {
+		angle := float64(first+i) * 0.05
+		lat := 40 + 100*math.Sin(angle)/111111
+		lon := -83 + 100*(1-math.Cos(angle))/(111111*math.Cos(40*math.Pi/180))
+		nodes.At(i).SetLatitude(lat)
+		nodes.At(i).SetLongitude(lon)
+	}
+	if stored != 0 {
+		curvatures, err := w.NewNodeCurvatures(int32(count))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range count {
+			curvatures.Set(i, stored)
+		}
+	}
+	return maps.NewWay(w)
+}
+
+func mergeState(t *testing.T, stored float32) *State {
+	state := &State{}
+	state.CurrentWay.Way = arcWay(t, 1, 0, 12, stored)
+	state.CurrentWay.OnWay.IsForward = true
+	state.NextWays = []maps.NextWayResult{{Way: arcWay(t, 2, 11, 12, stored), IsForward: true}}
+	return state
+}
+
+func TestStoredCurvaturesStraightenMergesLikeComputed(t *testing.T) {
+	computed, err := GetStateCurvatures(mergeState(t, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := GetStateCurvatures(mergeState(t, 0.02))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != len(computed) {
+		t.Fatalf("expected %d curvatures, got %d", len(computed), len(stored))
+	}
+
+	straight, merged := 0, 0
+	for i := range computed {
+		if !stored[i].Pos.Equals(computed[i].Pos) {
+			t.Fatalf("curvature %d is at %v, expected %v", i, stored[i].Pos, computed[i].Pos)
+		}
+		if computed[i].Curvature == 0.0015 {
+			straight++
+		}
+		if stored[i].Curvature == float64(float32(0.02)) {
+			continue
+		}
+		// averages around the merge use the positions on both paths
+		merged++
+		if math.Abs(stored[i].Curvature-computed[i].Curvature) > 1e-9 {
+			t.Errorf("curvature %d: expected %f like without stored curvatures, got %f", i, computed[i].Curvature, stored[i].Curvature)
+		}
+	}
+	if straight == 0 {
+		t.Error("expected the merge to be straightened")
+	}
+	if merged == 0 || merged == len(computed) {
+		t.Errorf("expected only the curvatures around the merge to be computed, got %d of %d", merged, len(computed))
+	}
+	if math.Abs(computed[0].Curvature-0.01) > 0.001 {
+		t.Errorf("expected a curvature of 0.01 away from the merge, got %f", computed[0].Curvature)
+	}
+}
@@ -3,6 +3,7 @@ MIN_LON=-180
 MIN_LAT=-90
 MAX_LON=180
 MAX_LAT=90
+DATA_VERSION=$(date -u +%Y-%m-%dT%H:%M:%SZ)
 
 ./filter_planet.sh
 
@@ -17,6 +18,7 @@ do
     ./add_locations.sh
     ./mapd generate --minlat $j --minlon $i --maxlat $max_lat --maxlon $max_lon || exit 1
     ./compress_offline.sh
+    ./mapd manifest --data-version $DATA_VERSION || exit 1
     ./upload_offline.sh
     #./upload_small_offline.sh
     rm -r offline
@@ -1,3 +1,3 @@
 #!/bin/bash
 
-rclone copy offline r2:osm-map-data/offline/ --progress --transfers 128 --checkers 128 --exclude **/*.tar.gz
+rclone copy offline r2:osm-map-data/offline/ --progress --transfers 128 --checkers 128 --exclude **/*.tar.gz --exclude **/*.manifest.json
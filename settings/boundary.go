@@ -0,0 +1,102 @@
+package settings
+
+import (
+	"math"
+	"slices"
+)
+
+// Boundary is a simplified outline of a download location as a list of
+// polygons, each a ring of [lon, lat] points like a GeoJSON MultiPolygon
+// without holes. Polygons should be buffered outward so coastal roads are not
+// cut off, and split at the antimeridian.
+type Boundary [][][2]float64
+
+// groups returns the group archives for a download location. Locations with a
+// boundary only use the groups that intersect it, others every group in the
+// bounding box.
+func (l LocationData) groups() []groupCell {
+	if len(l.Boundary) == 0 {
+		return groupsForBounds(l.BoundingBox)
+	}
+	seen := map[groupCell]bool{}
+	groups := []groupCell{}
+	for _, ring := range l.Boundary {
+		if len(ring) < 3 {
+			continue
+		}
+		for _, group := range groupsForBounds(ringBounds(ring)) {
+			if !seen[group] && ringIntersectsGroup(ring, group) {
+				seen[group] = true
+				groups = append(groups, group)
+			}
+		}
+	}
+	slices.SortFunc(groups, func(a, b groupCell) int {
+		if a.Lat != b.Lat {
+			return a.Lat - b.Lat
+		}
+		return a.Lon - b.Lon
+	})
+	return groups
+}
+
+func ringBounds(ring [][2]float64) Bounds {
+	bounds := Bounds{MinLat: math.Inf(1), MinLon: math.Inf(1), MaxLat: math.Inf(-1), MaxLon: math.Inf(-1)}
+	for _, p := range ring {
+		bounds.MinLon = min(bounds.MinLon, p[0])
+		bounds.MaxLon = max(bounds.MaxLon, p[0])
+		bounds.MinLat = min(bounds.MinLat, p[1])
+		bounds.MaxLat = max(bounds.MaxLat, p[1])
+	}
+	return bounds
+}
+
+// ringIntersectsGroup checks whether a polygon and the area of a group archive
+// overlap: a corner of the polygon lies in the group, the group lies in the
+// polygon, or their edges cross.
+func ringIntersectsGroup(ring [][2]float64, group groupCell) bool {
+	minLon, minLat := float64(group.Lon), float64(group.Lat)
+	maxLon, maxLat := minLon+GROUP_AREA_BOX_DEGREES, minLat+GROUP_AREA_BOX_DEGREES
+	for _, p := range ring {
+		if p[0] >= minLon && p[0] <= maxLon && p[1] >= minLat && p[1] <= maxLat {
+			return true
+		}
+	}
+	if pointInRing(ring, (minLon+maxLon)/2, (minLat+maxLat)/2) {
+		return true
+	}
+	corners := [][2]float64{{minLon, minLat}, {maxLon, minLat}, {maxLon, maxLat}, {minLon, maxLat}}
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		for j := range corners {
+			if segmentsIntersect(a, b, corners[j], corners[(j+1)%len(corners)]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pointInRing is the even-odd ray casting test
+func pointInRing(ring [][2]float64, lon float64, lat float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a[1] > lat) != (b[1] > lat) && lon < (b[0]-a[0])*(lat-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func orientation(a, b, c [2]float64) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+func segmentsIntersect(a, b, c, d [2]float64) bool {
+	d1 := orientation(c, d, a)
+	d2 := orientation(c, d, b)
+	d3 := orientation(a, b, c)
+	d4 := orientation(a, b, d)
+	return ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))
+}
@@ -0,0 +1,70 @@
+package settings
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestLocationGroupsWithoutBoundary(t *testing.T) {
+	var menu DownloadMenu
+	custom := `{"nation": {"XX": {"full_name": "Custom", "bounding_box": {"min_lon": -1, "min_lat": 0, "max_lon": 3, "max_lat": 2}}}}`
+	if err := json.Unmarshal([]byte(custom), &menu); err != nil {
+		t.Fatal(err)
+	}
+	location := menu["nation"]["XX"]
+	expected := []groupCell{{0, -2}, {0, 0}, {0, 2}}
+	if groups := sortedGroups(location.groups()); !slices.Equal(groups, expected) {
+		t.Errorf("groups() = %v, expected %v", groups, expected)
+	}
+	if count := countFilesForLocation(location); count != 3 {
+		t.Errorf("countFilesForLocation() = %d, expected 3", count)
+	}
+}
+
+func TestLocationGroupsWithBoundary(t *testing.T) {
+	location := LocationData{
+		BoundingBox: Bounds{MinLat: 0, MinLon: 0, MaxLat: 6, MaxLon: 6},
+		Boundary: Boundary{
+			// an L shape that leaves out the upper right of its bounding box
+			{{0.5, 0.5}, {5.5, 0.5}, {5.5, 1.5}, {1.5, 1.5}, {1.5, 5.5}, {0.5, 5.5}},
+			// a small island in a separate polygon
+			{{4.2, 4.2}, {4.8, 4.2}, {4.8, 4.8}},
+		},
+	}
+	expected := []groupCell{{0, 0}, {0, 2}, {0, 4}, {2, 0}, {4, 0}, {4, 4}}
+	if groups := location.groups(); !slices.Equal(groups, expected) {
+		t.Errorf("groups() = %v, expected %v", groups, expected)
+	}
+	if count := countFilesForLocation(location); count != len(expected) {
+		t.Errorf("countFilesForLocation() = %d, expected %d", count, len(expected))
+	}
+
+	// a group inside a large polygon without any of its corners
+	location.Boundary = Boundary{{{-10, -10}, {10, -10}, {10, 10}, {-10, 10}}}
+	if !slices.Contains(location.groups(), groupCell{2, 2}) {
+		t.Error("expected a group inside the boundary to be selected")
+	}
+}
+
+func TestDownloadMenuBoundaryAcrossAntimeridian(t *testing.T) {
+	var menu DownloadMenu
+	if err := json.Unmarshal(boundingBoxesJson, &menu); err != nil {
+		t.Fatal(err)
+	}
+	alaska := menu["us_state"]["AK"]
+	groups := alaska.groups()
+	for _, group := range []groupCell{{60, -150}, {58, -136}, {52, 172}, {70, -158}} {
+		if !slices.Contains(groups, group) {
+			t.Errorf("expected %v to be downloaded for Alaska", group)
+		}
+	}
+	for _, group := range []groupCell{{60, 0}, {52, -140}, {70, 178}} {
+		if slices.Contains(groups, group) {
+			t.Errorf("did not expect %v to be downloaded for Alaska", group)
+		}
+	}
+	if count := countFilesForBounds(alaska.BoundingBox); len(groups) >= count/10 {
+		t.Errorf("expected the boundary to select far fewer than the %d groups of the bounding box, got %d", count, len(groups))
+	}
+}
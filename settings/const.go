@@ -36,6 +36,35 @@ const (
 	ACCEPTABLE_BEARING_DELTA_SIN = 0.7071067811865475 // sin(45°) - max acceptable bearing mismatch
 	MIN_WAY_DIST                 = 500                // meters. how many meters to look ahead before stopping gathering next ways.
 	CURVE_CALC_OFFSET            = 10 * MPH_TO_MS
+	LEVEL_HEIGHT                 = 5                // meters. minimum altitude change to consider a move between stacked road levels
+	LEVEL_TREND_WINDOW           = 15 * time.Second // how far back to look when estimating the altitude trend
+	MAX_VERTICAL_ACCURACY        = 15               // meters. gps altitude samples less accurate than this are ignored
+	MAP_DATA_URL                 = "https://map-data.pfeifer.dev/"
+	DOWNLOAD_MAX_ATTEMPTS        = 5
+	DOWNLOAD_RETRY_BASE_DELAY    = 2 * time.Second
+	DOWNLOAD_RETRY_MAX_DELAY     = time.Minute
+	DOWNLOAD_IDLE_TIMEOUT        = 30 * time.Second // abort a download attempt when no data arrives for this long
+	DOWNLOAD_VERIFY_ATTEMPTS     = 2                // how many times to fetch an archive that fails verification
+	DOWNLOAD_PAUSE_POLL          = time.Second      // how often a paused download checks whether it may continue
+	DOWNLOAD_PROGRESS_INTERVAL   = time.Second / 2  // how often byte progress is published during a transfer
+	DOWNLOAD_THROUGHPUT_WINDOW   = time.Second      // how long transferred bytes are collected per throughput sample
+	DOWNLOAD_THROUGHPUT_WEIGHT   = 0.3              // weight of the newest throughput sample
+	DOWNLOAD_WORKERS             = 2                // group archives downloaded in parallel unless configured
+	DOWNLOAD_MAX_WORKERS         = 8
+	DOWNLOAD_THROTTLE_CHUNK      = 16 * 1024        // bytes read at a time while the bandwidth is capped
+	DOWNLOAD_CANCEL_POLL         = time.Second / 10 // how often a transfer checks whether the download was canceled
+	METERED_CHECK_INTERVAL       = 15 * time.Second // how long a metered connection check is reused
+	METERED_COMMAND_TIMEOUT      = 5 * time.Second  // how long the metered connection command may run
+	LIVE_DOWNLOAD_REGION         = "live"           // inventory region for groups fetched by live downloads
+	LIVE_DOWNLOAD_QUEUE_SIZE     = 4
+	LIVE_DOWNLOAD_MIN_INTERVAL   = 30 * time.Second // minimum time between starting two live downloads
+	LIVE_DOWNLOAD_RETRY_DELAY    = 10 * time.Minute // how long before a group that was already tried is requested again
+	LIVE_DOWNLOAD_LOOKAHEAD_TIME = 2 * time.Minute  // how far ahead of the vehicle to look for missing map data
+	LIVE_DOWNLOAD_MIN_LOOKAHEAD  = 2000             // meters
+	GROUP_ACCESS_INTERVAL        = time.Hour        // how often the group being driven in has its last access time refreshed
+	MAX_RECENT_EVICTIONS         = 20               // evictions kept for the extended output
+	NEARBY_REGIONS_INTERVAL      = 10 * time.Second // how often the download regions around the vehicle are looked up
+	PACK_FILE                    = "tiles.pack"     // holds every tile of a group directory in the pack format
 )
 
 // ServiceQueueSize maps service names to their queue sizes from openpilot's services.py
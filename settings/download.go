@@ -1,26 +1,24 @@
 package settings
 
 import (
-	"archive/tar"
-	"compress/gzip"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
 	"math"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 	"pfeifer.dev/mapd/params"
 )
 
 type LocationData struct {
-	BoundingBox Bounds `json:"bounding_box"`
-	FullName    string `json:"full_name"`
-	Submenu     string `json:"submenu"`
+	BoundingBox Bounds   `json:"bounding_box"`
+	Boundary    Boundary `json:"boundary,omitempty"`
+	FullName    string   `json:"full_name"`
+	Submenu     string   `json:"submenu"`
 }
 
 type DownloadMenu map[string]map[string]LocationData
@@ -48,36 +46,8 @@ func GetDownloadMenu() (menu DownloadMenu) {
 
 func DownloadFile(url string, filepath string) (err error) {
 	slog.Info("Downloading", "url", url)
-	// Create the file
-	out, err := os.Create(filepath)
-	if err != nil {
-		return errors.Wrap(err, "could not create file for download")
-	}
-	defer out.Close()
-
-	// Get the data
-	resp, err := http.Get(url)
-	if err != nil {
-		return errors.Wrap(err, "could not download the file data")
-	}
-	defer resp.Body.Close()
-
-	// Check server response
-	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("download received bad status: %s", resp.Status)
-	}
-
-	// Writer the body to file
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		return errors.Wrap(err, "could not write download data to file")
-	}
-	err = out.Sync()
-	if err != nil {
-		return errors.Wrap(err, "could not fsync downloaded file")
-	}
-
-	return nil
+	_, err = NewFileFetcher().Fetch(url, filepath)
+	return err
 }
 
 type Bounds struct {
@@ -94,6 +64,24 @@ type DownloadProgress struct {
 	Active              bool                               `json:"active"`
 	LocationsToDownload []string                           `json:"locations_to_download"`
 	LocationDetails     map[string]*DownloadLocationDetail `json:"location_details"`
+	FailedFiles         []DownloadFailure                  `json:"failed_files"`
+	UpdateCheck         bool                               `json:"update_check"`
+	UpdatesAvailable    int                                `json:"updates_available"`
+	Paused              bool                               `json:"paused"`
+	DownloadedBytes     int64                              `json:"downloaded_bytes"`
+	BytesPerSecond      float64                            `json:"bytes_per_second"`
+	EtaSeconds          int                                `json:"eta_seconds"` // -1 when unknown
+	CurrentFile         string                             `json:"current_file"`
+	CurrentFileReceived int64                              `json:"current_file_received"` // bytes
+	CurrentFileSize     int64                              `json:"current_file_size"`     // bytes, 0 when unknown
+	UnverifiedFiles     []string                           `json:"unverified_files"`      // installed from a source without a manifest
+}
+
+type DownloadFailure struct {
+	File     string `json:"file"`
+	Location string `json:"location"`
+	Error    string `json:"error"`
+	Attempts int    `json:"attempts"`
 }
 
 type DownloadLocationDetail struct {
@@ -101,37 +89,147 @@ type DownloadLocationDetail struct {
 	DownloadedFiles int `json:"location_downloaded_files"`
 }
 
+// download is shared by the workers of a download. lock guards the progress,
+// the transfer stats, the cancel flag and the failed sources.
 type download struct {
-	progress     DownloadProgress
-	progressChan chan DownloadProgress
-	cancelChan   chan bool
+	lock          sync.Mutex
+	progress      DownloadProgress
+	progressChan  chan DownloadProgress
+	cancelChan    chan bool
+	canceled      bool
+	sources       []string
+	failedSources map[string]bool
+	basePath      string
+	tmpDir        string // created on first use and removed when the download finishes
+	fetcher       FileFetcher
+	network       *NetworkPolicy
+	quota         *DiskQuota
+	stats         transferStats
+	workers       int
+}
+
+func newDownload(progress DownloadProgress, progressChan chan DownloadProgress, cancelChan chan bool) *download {
+	d := &download{
+		progress:      progress,
+		progressChan:  progressChan,
+		cancelChan:    cancelChan,
+		sources:       mapSources.get(),
+		failedSources: map[string]bool{},
+		basePath:      params.GetBaseOpPath(),
+		fetcher:       NewFileFetcher(),
+		network:       networkPolicy,
+		quota:         diskQuota,
+		stats:         transferStats{files: map[string]*fileTransfer{}},
+		workers:       downloadLimits.Workers(),
+	}
+	d.progress.EtaSeconds = -1
+	d.fetcher.Canceled = d.checkCanceled
+	d.fetcher.Paused = d.checkPaused
+	d.fetcher.Throttle = downloadLimits.throttle
+	return d
+}
+
+// tmpPath is where archives are downloaded and staged before installing.
+// Every download has its own directory, so downloads running at the same time
+// never remove each other's files.
+func (d *download) tmpPath() (string, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.tmpDir != "" {
+		return d.tmpDir, nil
+	}
+	err := os.MkdirAll(d.basePath, 0o775)
+	if err != nil {
+		return "", errors.Wrap(err, "could not create offline maps directory")
+	}
+	d.tmpDir, err = os.MkdirTemp(d.basePath, "tmp-")
+	if err != nil {
+		return "", errors.Wrap(err, "could not create temporary download directory")
+	}
+	return d.tmpDir, nil
+}
+
+// removeTmpPath removes the temporary directory of the download.
+func (d *download) removeTmpPath() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.tmpDir == "" {
+		return
+	}
+	err := os.RemoveAll(d.tmpDir)
+	if err != nil {
+		slog.Warn("could not remove temporary download directory", "error", err)
+	}
+	d.tmpDir = ""
+}
+
+func (d *download) recordFailure(filename string, locationName string, attempts int, err error) {
+	slog.Warn("failed to download file, continuing to next", "error", err, "file", filename)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.progress.FailedFiles = append(d.progress.FailedFiles, DownloadFailure{
+		File:     filename,
+		Location: locationName,
+		Error:    err.Error(),
+		Attempts: attempts,
+	})
+}
+
+// checkCanceled does a nonblocking check for a cancel message and remembers
+// it so every stage of the download sees the same answer.
+func (d *download) checkCanceled() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	select {
+	case cancel := <-d.cancelChan:
+		if cancel {
+			d.canceled = true
+		}
+	default:
+	}
+	return d.canceled
+}
+
+// checkPaused asks the network policy whether transfers have to wait and
+// reports changes through the download progress.
+func (d *download) checkPaused() bool {
+	paused := d.network.Paused()
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if paused != d.progress.Paused {
+		if paused {
+			slog.Info("pausing map download on metered connection")
+		} else {
+			slog.Info("resuming map download")
+		}
+		d.progress.Paused = paused
+		d.resetThroughput()
+		d.publishProgress()
+	}
+	return paused
 }
 
 func (p *DownloadProgress) addLocationDetails(path string) {
 	p.LocationDetails[path] = &DownloadLocationDetail{
-		TotalFiles: countFilesForBounds(getBoundsForPath(path)),
+		TotalFiles: countFilesForLocation(getDataForPath(path)),
 	}
 }
 
 func Download(paths string, progressChan chan DownloadProgress, cancelChan chan bool) {
 	slog.Info("download", "paths", paths)
 	pathsSplit := strings.Split(paths, ",")
-	d := download{
-		progress: DownloadProgress{
-			LocationsToDownload: pathsSplit,
-			TotalFiles:          countTotalFiles(pathsSplit),
-			LocationDetails:     make(map[string]*DownloadLocationDetail),
-			Active:              true,
-		},
-		progressChan: progressChan,
-		cancelChan:   cancelChan,
-	}
+	d := newDownload(DownloadProgress{
+		LocationsToDownload: pathsSplit,
+		TotalFiles:          countTotalFiles(pathsSplit),
+		LocationDetails:     make(map[string]*DownloadLocationDetail),
+		Active:              true,
+	}, progressChan, cancelChan)
 
 	for _, p := range pathsSplit {
 		d.progress.addLocationDetails(p)
 		location := getDataForPath(p)
 		slog.Info("downloading nation", "nation", location.FullName)
-		err, canceled := d.downloadBounds(location.BoundingBox, p)
+		err, canceled := d.downloadLocation(location, p)
 		if err != nil {
 			slog.Warn("failed to download nation", "error", err, "nation", location.FullName)
 		}
@@ -141,10 +239,8 @@ func Download(paths string, progressChan chan DownloadProgress, cancelChan chan
 		}
 	}
 	d.progress.Active = false
-	select { // nonblocking update of progress
-	case d.progressChan <- d.progress:
-	default:
-	}
+	d.finishFiles()
+	d.sendProgress()
 }
 
 func adjustedBounds(bounds Bounds) (int, int, int, int) {
@@ -162,115 +258,255 @@ func adjustedBounds(bounds Bounds) (int, int, int, int) {
 	return minLat, minLon, maxLat, maxLon
 }
 
-func (d *download) downloadBounds(bounds Bounds, locationName string) (err error, cancel bool) {
-	slog.Info("Downloading Bounds", "min_lat", bounds.MinLat, "min_lon", bounds.MinLon, "max_lat", bounds.MaxLat, "max_lon", bounds.MaxLon)
+// a group archive cell identified by its minimum corner in whole degrees
+type groupCell struct {
+	Lat int
+	Lon int
+}
+
+func (g groupCell) ArchiveName() string {
+	return fmt.Sprintf("offline/%d/%d.tar.gz", g.Lat, g.Lon)
+}
 
+func groupsForBounds(bounds Bounds) (groups []groupCell) {
 	// clip given bounds to file areas
 	minLat, minLon, maxLat, maxLon := adjustedBounds(bounds)
-	d.progress.LocationDetails[locationName].TotalFiles = countFilesForBounds(bounds)
 	for i := minLat; i < maxLat; i += GROUP_AREA_BOX_DEGREES {
 		for j := minLon; j < maxLon; j += GROUP_AREA_BOX_DEGREES {
-			select { // nonblocking update of progress
-			case d.progressChan <- d.progress:
-			default:
-			}
-			select { // cancel if sent message
-			case cancel := <-d.cancelChan:
-				if cancel {
-					return nil, true
-				}
-			default:
-			}
+			groups = append(groups, groupCell{Lat: i, Lon: j})
+		}
+	}
+	return groups
+}
 
-			filename := fmt.Sprintf("offline/%d/%d.tar.gz", i, j)
-			url := fmt.Sprintf("https://map-data.pfeifer.dev/%s", filename)
-			outputName := filepath.Join(params.GetBaseOpPath(), "tmp", filename)
-			err := os.MkdirAll(filepath.Dir(outputName), 0o775)
-			if err != nil {
-				slog.Error("failed to create offline maps output directory", "error", err)
-			}
-			err = DownloadFile(url, outputName)
-			if err != nil {
-				slog.Warn("failed to download file, continuing to next", "error", err, "url", url, "file", outputName)
-				continue
-			}
-			file, err := os.Open(outputName)
-			if err != nil {
-				slog.Warn("failed to open downloaded file", "error", err, "file", outputName)
-			}
-			reader, err := gzip.NewReader(file)
-			if err != nil {
-				slog.Warn("failed to parse gzip downloaded file", "error", err, "file", outputName)
-			}
-			tr := tar.NewReader(reader)
-			for {
-				header, err := tr.Next()
-				if err != nil {
-					break
-				}
-
-				// if the header is nil, just skip it (not sure how this happens)
-				if header == nil {
-					continue
-				}
-				// the target location where the dir/file should be created
-				target := filepath.Join(params.GetBaseOpPath(), header.Name)
-				// check the file type
-				switch header.Typeflag {
-
-				// if its a dir and it doesn't exist create it
-				case tar.TypeDir:
-					if _, err := os.Stat(target); err != nil {
-						err := os.MkdirAll(target, 0o755)
-						if err != nil {
-							slog.Warn("could not create directory from downloaded gzip", "error", err, "file", outputName, "directory", target)
-						}
-					}
-
-				// if it's a file create it
-				case tar.TypeReg:
-					f, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
-					if err != nil {
-						slog.Warn("could not open file target from downloaded gzip", "error", err, "file", outputName, "targetFile", target)
-					}
-
-					_, err = io.Copy(f, tr)
-					if err != nil {
-						slog.Warn("could not write data to file target from downloaded gzip", "error", err, "file", outputName, "targetFile", target)
-					}
-
-					err = f.Sync()
-					if err != nil {
-						slog.Warn("could not fsync file target from downloaded gzip", "error", err, "file", outputName, "targetFile", target)
-					}
-					f.Close()
-				}
+func (d *download) downloadBounds(bounds Bounds, locationName string) (err error, cancel bool) {
+	return d.downloadLocation(LocationData{BoundingBox: bounds}, locationName)
+}
+
+func (d *download) downloadLocation(location LocationData, locationName string) (err error, cancel bool) {
+	bounds := location.BoundingBox
+	slog.Info("Downloading Bounds", "min_lat", bounds.MinLat, "min_lon", bounds.MinLon, "max_lat", bounds.MaxLat, "max_lon", bounds.MaxLon, "boundary_polygons", len(location.Boundary))
+	cancel = d.downloadGroups(location.groups(), locationName)
+	if cancel {
+		return nil, true
+	}
+	slog.Info("Finished Downloading Bounds", "min_lat", bounds.MinLat, "min_lon", bounds.MinLon, "max_lat", bounds.MaxLat, "max_lon", bounds.MaxLon)
+	return nil, false
+}
+
+// downloadGroups downloads the group archives with a pool of workers. A cancel
+// stops every worker in the middle of its transfer.
+func (d *download) downloadGroups(groups []groupCell, locationName string) (cancel bool) {
+	d.lock.Lock()
+	d.progress.LocationDetails[locationName].TotalFiles = len(groups)
+	d.lock.Unlock()
+
+	filenames := make([]string, len(groups))
+	for i, group := range groups {
+		filenames[i] = group.ArchiveName()
+	}
+	return d.processGroups(filenames, func(filename string) {
+		d.processGroup(filename, locationName)
+	})
+}
+
+// processGroups hands the group archives to a pool of d.workers workers, which
+// share the bandwidth cap of the download.
+func (d *download) processGroups(filenames []string, process func(filename string)) (cancel bool) {
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range min(max(d.workers, 1), max(len(filenames), 1)) {
+		wg.Go(func() {
+			for filename := range jobs {
+				process(filename)
 			}
-			err = reader.Close()
-			if err != nil {
-				slog.Warn("could not close gzip reader", "error", err)
+		})
+	}
+	for _, filename := range filenames {
+		if d.checkCanceled() {
+			break
+		}
+		jobs <- filename
+	}
+	close(jobs)
+	wg.Wait()
+	d.removeTmpPath()
+	return d.checkCanceled()
+}
+
+func (d *download) processGroup(filename string, locationName string) {
+	d.startFile(filename)
+	d.sendProgress()
+	if d.checkCanceled() {
+		return
+	}
+
+	attempts, err := d.downloadGroup(locationName, filename)
+	d.finishFile(filename)
+	if errors.Is(err, ErrDownloadCanceled) {
+		return
+	}
+	if err != nil {
+		d.recordFailure(filename, locationName, attempts, err)
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.progress.DownloadedFiles++
+	d.progress.LocationDetails[locationName].DownloadedFiles++
+}
+
+// fetchManifest downloads the manifest for a group archive from a source. A
+// source that does not publish manifests fails with ErrNotFound.
+func (d *download) fetchManifest(source string, filename string) (*GroupManifest, error) {
+	tmpPath, err := d.tmpPath()
+	if err != nil {
+		return nil, err
+	}
+	manifestName := GroupManifestName(filename)
+	outputName := filepath.Join(tmpPath, manifestName)
+	err = os.MkdirAll(filepath.Dir(outputName), 0o775)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create temporary download directory")
+	}
+	_, err = d.fetchFrom(source, manifestName, outputName)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := ReadGroupManifest(outputName)
+	if err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// downloadGroup downloads, verifies and extracts a single group archive for
+// the given download location. The manifest and the archive always come from
+// the same source.
+func (d *download) downloadGroup(locationName string, filename string) (attempts int, err error) {
+	return d.fromEachSource(filename, func(source string) (int, error) {
+		manifest, err := d.fetchManifest(source, filename)
+		if errors.Is(err, ErrNotFound) {
+			slog.Warn("no manifest available, map data will not be verified", "file", filename, "source", source)
+		} else if err != nil {
+			return 1, errors.Wrap(err, "could not download manifest")
+		}
+		attempts, err := d.installGroupArchive(source, locationName, filename, manifest)
+		if err == nil && manifest == nil {
+			d.lock.Lock()
+			d.progress.UnverifiedFiles = append(d.progress.UnverifiedFiles, filename)
+			d.lock.Unlock()
+		}
+		return attempts, err
+	})
+}
+
+// installGroupArchive downloads and installs a group archive from a source,
+// fetching it again when the data does not match its manifest, and records it
+// in the inventory.
+func (d *download) installGroupArchive(source string, locationName string, filename string, manifest *GroupManifest) (attempts int, err error) {
+	tmpPath, err := d.tmpPath()
+	if err != nil {
+		return 1, err
+	}
+	outputName := filepath.Join(tmpPath, filename)
+	err = os.MkdirAll(filepath.Dir(outputName), 0o775)
+	if err != nil {
+		slog.Error("failed to create offline maps output directory", "error", err)
+	}
+
+	for verifyAttempt := 1; ; verifyAttempt++ {
+		slog.Info("Downloading", "file", filename)
+		fetchAttempts, err := d.fetchFrom(source, filename, outputName)
+		attempts += fetchAttempts
+		if err != nil {
+			return attempts, err
+		}
+
+		err = d.verifyAndExtract(filename, outputName, manifest)
+		removeErr := os.Remove(outputName)
+		if removeErr != nil {
+			slog.Warn("could not delete downloaded gzip file", "error", removeErr)
+		}
+		if err == nil {
+			size, sizeErr := directorySize(groupDirectory(d.basePath, filename))
+			if sizeErr != nil {
+				slog.Warn("could not measure installed map data", "error", sizeErr, "file", filename)
 			}
-			err = file.Close()
+			err = UpdateInventory(d.basePath, func(inventory *Inventory) {
+				inventory.recordGroup(locationName, filename, manifest, size)
+			})
 			if err != nil {
-				slog.Warn("could not close downloaded file", "error", err)
+				slog.Warn("could not record installed map data", "error", err, "file", filename)
 			}
+			return attempts, nil
+		}
+		if !errors.Is(err, ErrIntegrity) || verifyAttempt >= DOWNLOAD_VERIFY_ATTEMPTS {
+			return attempts, err
+		}
+		slog.Warn("downloaded map data is corrupt, downloading again", "error", err, "file", filename)
+	}
+}
 
-			err = os.Remove(outputName)
-			if err != nil {
-				slog.Warn("could not delete downloaded gzip file", "error", err)
-			}
+func (d *download) verifyAndExtract(filename string, archiveName string, manifest *GroupManifest) error {
+	if manifest != nil {
+		err := manifest.VerifyArchive(archiveName)
+		if err != nil {
+			return err
+		}
+	}
 
-			d.progress.DownloadedFiles++
-			d.progress.LocationDetails[locationName].DownloadedFiles++
+	// tiles are extracted and verified away from the live map data and then
+	// swapped in as a whole group so mapd never reads a partial tile set
+	groupDir := strings.TrimSuffix(filename, ".tar.gz")
+	tmpPath, err := d.tmpPath()
+	if err != nil {
+		return err
+	}
+	stagingRoot := filepath.Join(tmpPath, "staging")
+	stagedGroup := filepath.Join(stagingRoot, filepath.FromSlash(groupDir))
+	defer os.RemoveAll(stagedGroup)
+	err = os.RemoveAll(stagedGroup)
+	if err != nil {
+		return errors.Wrap(err, "could not clear staging directory")
+	}
+
+	err = extractArchive(archiveName, stagingRoot, groupDir)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(stagedGroup); err != nil {
+		return errors.Wrapf(ErrIntegrity, "archive did not contain %s", groupDir)
+	}
+	err = verifyStagedPack(stagedGroup)
+	if err != nil {
+		return err
+	}
+
+	if manifest != nil {
+		bad := manifest.VerifyTiles(stagingRoot)
+		if len(bad) > 0 {
+			return errors.Wrapf(ErrIntegrity, "%d extracted tiles did not match the manifest", len(bad))
 		}
 	}
-	err = os.RemoveAll(filepath.Join(params.GetBaseOpPath(), "tmp"))
+
+	size, err := directorySize(stagedGroup)
 	if err != nil {
-		slog.Warn("could not remove temporary download directory", "error", err)
+		return errors.Wrap(err, "could not measure staged map data")
+	}
+	err = d.installWithinQuota(filename, stagedGroup, size)
+	if err != nil {
+		return err
 	}
 
-	slog.Info("Finished Downloading Bounds", "min_lat", bounds.MinLat, "min_lon", bounds.MinLon, "max_lat", bounds.MaxLat, "max_lon", bounds.MaxLon)
-	return nil, false
+	if manifest != nil {
+		err = WriteGroupManifest(filepath.Join(d.basePath, GroupManifestName(filename)), *manifest)
+		if err != nil {
+			slog.Warn("could not save manifest for installed map data", "error", err)
+		}
+	}
+	return nil
 }
 
 func countFilesForBounds(bounds Bounds) int {
@@ -278,6 +514,13 @@ func countFilesForBounds(bounds Bounds) int {
 	return ((maxLat - minLat) / GROUP_AREA_BOX_DEGREES) * ((maxLon - minLon) / GROUP_AREA_BOX_DEGREES)
 }
 
+func countFilesForLocation(location LocationData) int {
+	if len(location.Boundary) == 0 {
+		return countFilesForBounds(location.BoundingBox)
+	}
+	return len(location.groups())
+}
+
 func getDataForPath(path string) LocationData {
 	parts := strings.Split(path, ".")
 	if len(parts) < 2 {
@@ -294,15 +537,11 @@ func getDataForPath(path string) LocationData {
 	return box
 }
 
-func getBoundsForPath(path string) Bounds {
-	return getDataForPath(path).BoundingBox
-}
-
 func countTotalFiles(paths []string) int {
 	totalFiles := 0
 
 	for _, p := range paths {
-		totalFiles += countFilesForBounds(getBoundsForPath(p))
+		totalFiles += countFilesForLocation(getDataForPath(p))
 	}
 
 	return totalFiles
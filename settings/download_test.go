@@ -0,0 +1,489 @@
+package settings
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	dirs := map[string]bool{}
+	for name := range files {
+		for dir := filepath.Dir(name); dir != "."; dir = filepath.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+	sortedDirs := []string{}
+	for dir := range dirs {
+		sortedDirs = append(sortedDirs, dir)
+	}
+	slices.Sort(sortedDirs)
+	for _, dir := range sortedDirs {
+		err := tw.WriteHeader(&tar.Header{Name: dir + "/", Mode: 0o755, Typeflag: tar.TypeDir})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func testManifest(t *testing.T, archive []byte) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "archive.tar.gz")
+	if err := os.WriteFile(path, archive, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := BuildGroupManifest(path, "test-version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// flakyServer serves archives with range support but drops the connection
+// half way through the body for the first drops requests of each file.
+type flakyServer struct {
+	mu       sync.Mutex
+	archives map[string][]byte
+	drops    int
+	corrupt  int // serve flipped bytes for this many complete responses
+	requests map[string]int
+	ranges   []string
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data, ok := s.archives[r.URL.Path]
+	s.requests[r.URL.Path]++
+	count := s.requests[r.URL.Path]
+	if rng := r.Header.Get("Range"); rng != "" {
+		s.ranges = append(s.ranges, rng)
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if count > s.drops+s.corrupt {
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+		return
+	}
+	if count > s.drops {
+		corrupted := bytes.Clone(data)
+		corrupted[len(corrupted)/2] ^= 0xff
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(corrupted))
+		return
+	}
+
+	// claim the full remaining length, send part of it and hang up
+	start := 0
+	if rng := r.Header.Get("Range"); rng != "" {
+		start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(data)-1, len(data)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)-start))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+	}
+	remaining := data[start:]
+	w.Write(remaining[:len(remaining)/2])
+	w.(http.Flusher).Flush()
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		conn.Close()
+	}
+}
+
+func newTestDownload(t *testing.T, server *httptest.Server) *download {
+	t.Helper()
+	d := newDownload(DownloadProgress{
+		LocationDetails: map[string]*DownloadLocationDetail{"test.location": {}},
+		Active:          true,
+	}, make(chan DownloadProgress, 1), make(chan bool, 1))
+	d.sources = []string{server.URL + "/"}
+	d.basePath = t.TempDir()
+	d.fetcher.Client = server.Client()
+	d.fetcher.BaseDelay = time.Millisecond
+	d.fetcher.MaxDelay = 5 * time.Millisecond
+	d.network = &NetworkPolicy{}
+	// one worker keeps the order of progress and failures predictable
+	d.workers = 1
+	return d
+}
+
+// tmpDirs lists the temporary directories downloads left in basePath.
+func tmpDirs(t *testing.T, basePath string) []string {
+	t.Helper()
+	dirs, err := filepath.Glob(filepath.Join(basePath, "tmp-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dirs
+}
+
+func TestDownloadBoundsResumesDroppedConnections(t *testing.T) {
+	tile := strings.Repeat("tile data ", 10000)
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz": testArchive(t, map[string]string{"offline/0/0/0.000000_0.000000_0.250000_0.250000": tile}),
+		},
+		drops:    2,
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	err, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}, "test.location")
+	if err != nil || canceled {
+		t.Fatalf("downloadBounds() = %v, %v", err, canceled)
+	}
+	if len(d.progress.FailedFiles) != 0 {
+		t.Fatalf("unexpected failed files: %+v", d.progress.FailedFiles)
+	}
+	if d.progress.DownloadedFiles != 1 {
+		t.Errorf("DownloadedFiles = %d, expected 1", d.progress.DownloadedFiles)
+	}
+	if flaky.requests["/offline/0/0.tar.gz"] != 3 {
+		t.Errorf("expected 3 requests, got %d", flaky.requests["/offline/0/0.tar.gz"])
+	}
+	if len(flaky.ranges) != 2 {
+		t.Errorf("expected both retries to resume with a range request, got %v", flaky.ranges)
+	}
+
+	data, err := os.ReadFile(filepath.Join(d.basePath, "offline/0/0/0.000000_0.000000_0.250000_0.250000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != tile {
+		t.Error("extracted tile does not match the archive contents")
+	}
+	if !slices.Equal(d.progress.UnverifiedFiles, []string{"offline/0/0.tar.gz"}) {
+		t.Errorf("expected the archive without a manifest to be reported as unverified, got %v", d.progress.UnverifiedFiles)
+	}
+}
+
+func TestFetchRestartsWhenTheFileChanged(t *testing.T) {
+	versions := [][]byte{[]byte(strings.Repeat("old data ", 10000)), []byte(strings.Repeat("new data ", 10000))}
+	modTimes := []time.Time{time.Unix(1700000000, 0), time.Unix(1700003600, 0)}
+	requests := 0
+	ifRange := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			// send half of the old version and hang up
+			w.Header().Set("Last-Modified", modTimes[0].UTC().Format(http.TimeFormat))
+			w.Header().Set("Content-Length", strconv.Itoa(len(versions[0])))
+			w.WriteHeader(http.StatusOK)
+			w.Write(versions[0][:len(versions[0])/2])
+			w.(http.Flusher).Flush()
+			if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+				conn.Close()
+			}
+			return
+		}
+		ifRange = r.Header.Get("If-Range")
+		http.ServeContent(w, r, "", modTimes[1], bytes.NewReader(versions[1]))
+	}))
+	defer server.Close()
+
+	fetcher := NewFileFetcher()
+	fetcher.Client = server.Client()
+	fetcher.BaseDelay = time.Millisecond
+	path := filepath.Join(t.TempDir(), "archive")
+	if _, err := fetcher.Fetch(server.URL+"/archive", path); err != nil {
+		t.Fatal(err)
+	}
+	if ifRange != modTimes[0].UTC().Format(http.TimeFormat) {
+		t.Errorf("expected the resumed request to send If-Range with the first modification time, got %q", ifRange)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, versions[1]) {
+		t.Error("expected the changed file to be downloaded again from the start")
+	}
+}
+
+func TestDownloadBoundsRecordsFailures(t *testing.T) {
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz": testArchive(t, map[string]string{"offline/0/0/tile": "data"}),
+		},
+		drops:    100,
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	d.fetcher.MaxAttempts = 3
+	err, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 3}, "test.location")
+	if err != nil || canceled {
+		t.Fatalf("downloadBounds() = %v, %v", err, canceled)
+	}
+	if len(d.progress.FailedFiles) != 2 {
+		t.Fatalf("expected 2 failed files, got %+v", d.progress.FailedFiles)
+	}
+
+	dropped := d.progress.FailedFiles[0]
+	if dropped.File != "offline/0/0.tar.gz" || dropped.Attempts != 3 || dropped.Location != "test.location" {
+		t.Errorf("unexpected failure for dropped file: %+v", dropped)
+	}
+	if flaky.requests["/offline/0/0.tar.gz"] != 3 {
+		t.Errorf("expected the attempt limit to stop at 3 requests, got %d", flaky.requests["/offline/0/0.tar.gz"])
+	}
+
+	missing := d.progress.FailedFiles[1]
+	if missing.File != "offline/0/2.tar.gz" || missing.Attempts != 1 {
+		t.Errorf("missing files should fail without retrying: %+v", missing)
+	}
+	if d.progress.DownloadedFiles != 0 {
+		t.Errorf("DownloadedFiles = %d, expected 0", d.progress.DownloadedFiles)
+	}
+	if dirs := tmpDirs(t, d.basePath); len(dirs) != 0 {
+		t.Errorf("expected partial downloads to be cleaned up, found %v", dirs)
+	}
+}
+
+func TestDownloadBoundsCancelsDuringBackoff(t *testing.T) {
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz": testArchive(t, map[string]string{"offline/0/0/tile": "data"}),
+		},
+		drops:    100,
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	d.fetcher.BaseDelay = time.Hour
+	d.fetcher.MaxDelay = time.Hour
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		d.cancelChan <- true
+	}()
+
+	done := make(chan bool)
+	go func() {
+		_, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}, "test.location")
+		done <- canceled
+	}()
+	select {
+	case canceled := <-done:
+		if !canceled {
+			t.Error("expected download to report cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancel did not interrupt the retry backoff")
+	}
+}
+
+func TestDownloadBoundsRefetchesCorruptArchives(t *testing.T) {
+	tileName := "offline/0/0/0.000000_0.000000_0.250000_0.250000"
+	archive := testArchive(t, map[string]string{tileName: strings.Repeat("tile data ", 1000)})
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz":        archive,
+			"/offline/0/0.manifest.json": testManifest(t, archive),
+		},
+		corrupt:  1,
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	err, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}, "test.location")
+	if err != nil || canceled {
+		t.Fatalf("downloadBounds() = %v, %v", err, canceled)
+	}
+	if len(d.progress.FailedFiles) != 0 {
+		t.Fatalf("unexpected failed files: %+v", d.progress.FailedFiles)
+	}
+	if flaky.requests["/offline/0/0.tar.gz"] != 2 {
+		t.Errorf("expected the corrupt archive to be fetched again, got %d requests", flaky.requests["/offline/0/0.tar.gz"])
+	}
+	manifest, err := ReadGroupManifest(filepath.Join(d.basePath, "offline/0/0.manifest.json"))
+	if err != nil {
+		t.Fatal("expected the verified manifest to be installed:", err)
+	}
+	if manifest.Version != "test-version" {
+		t.Errorf("installed manifest version = %q", manifest.Version)
+	}
+	if bad := manifest.VerifyTiles(d.basePath); len(bad) != 0 {
+		t.Errorf("installed tiles do not match the manifest: %v", bad)
+	}
+}
+
+func TestDownloadBoundsRejectsPersistentCorruption(t *testing.T) {
+	tileName := "offline/0/0/0.000000_0.000000_0.250000_0.250000"
+	archive := testArchive(t, map[string]string{tileName: strings.Repeat("tile data ", 1000)})
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz":        archive,
+			"/offline/0/0.manifest.json": testManifest(t, archive),
+		},
+		corrupt:  100,
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	err, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}, "test.location")
+	if err != nil || canceled {
+		t.Fatalf("downloadBounds() = %v, %v", err, canceled)
+	}
+	if len(d.progress.FailedFiles) != 1 || !strings.Contains(d.progress.FailedFiles[0].Error, ErrIntegrity.Error()) {
+		t.Fatalf("expected an integrity failure, got %+v", d.progress.FailedFiles)
+	}
+	if flaky.requests["/offline/0/0.tar.gz"] != DOWNLOAD_VERIFY_ATTEMPTS {
+		t.Errorf("expected %d archive requests, got %d", DOWNLOAD_VERIFY_ATTEMPTS, flaky.requests["/offline/0/0.tar.gz"])
+	}
+	if _, err := os.Stat(filepath.Join(d.basePath, tileName)); !os.IsNotExist(err) {
+		t.Error("corrupt archive should not have been extracted")
+	}
+}
+
+func TestGenerateManifests(t *testing.T) {
+	dir := t.TempDir()
+	tileName := "offline/2/4/2.000000_4.000000_2.250000_4.250000"
+	archive := testArchive(t, map[string]string{tileName: "tile"})
+	archivePath := filepath.Join(dir, "offline/2/4.tar.gz")
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(archivePath, archive, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := GenerateManifests(dir, "v1"); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := ReadGroupManifest(filepath.Join(dir, "offline/2/4.manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Version != "v1" || manifest.Archive.Size != int64(len(archive)) {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+	if len(manifest.Tiles) != 1 || manifest.Tiles[tileName] == "" {
+		t.Errorf("expected a digest for %s, got %v", tileName, manifest.Tiles)
+	}
+	if err := manifest.VerifyArchive(archivePath); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDownloadProgressReportsBytesAndFailures(t *testing.T) {
+	archive := testArchive(t, map[string]string{"offline/0/0/tile": strings.Repeat("tile data ", 10000)})
+	flaky := &flakyServer{
+		archives: map[string][]byte{"/offline/0/0.tar.gz": archive},
+		requests: map[string]int{},
+		drops:    1,
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	d.progress.TotalFiles = 2
+	d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 3}, "test.location")
+
+	// the resumed part of the archive is only counted once
+	if d.progress.DownloadedBytes != int64(len(archive)) {
+		t.Errorf("expected %d downloaded bytes, got %d", len(archive), d.progress.DownloadedBytes)
+	}
+	if d.progress.CurrentFile != "offline/0/2.tar.gz" {
+		t.Errorf("unexpected current file %q", d.progress.CurrentFile)
+	}
+	if len(d.progress.FailedFiles) != 1 || d.progress.FailedFiles[0].File != "offline/0/2.tar.gz" || !strings.Contains(d.progress.FailedFiles[0].Error, "not available") {
+		t.Errorf("expected the missing group to be reported with its reason: %+v", d.progress.FailedFiles)
+	}
+	if d.stats.completedFiles != 1 || d.stats.completedBytes != int64(len(archive)) {
+		t.Errorf("unexpected completed file stats: %+v", d.stats)
+	}
+}
+
+func TestDownloadEta(t *testing.T) {
+	d := &download{progress: DownloadProgress{
+		TotalFiles:      4,
+		DownloadedFiles: 1,
+		BytesPerSecond:  100,
+	}}
+	d.stats.files = map[string]*fileTransfer{
+		"offline/0/2.tar.gz":        {received: 200, size: 1000},
+		"offline/0/2.manifest.json": {received: 10, size: 100},
+	}
+	d.stats.completedBytes = 1000
+	d.stats.completedFiles = 1
+	d.updateEta()
+	// 800 bytes of the current file and two more files of about 1000 bytes
+	if d.progress.EtaSeconds != 28 {
+		t.Errorf("expected an eta of 28 seconds, got %d", d.progress.EtaSeconds)
+	}
+
+	d.resetThroughput()
+	d.updateEta()
+	if d.progress.EtaSeconds != -1 {
+		t.Errorf("the eta should be unknown without throughput, got %d", d.progress.EtaSeconds)
+	}
+}
+
+func TestDownloadsUseTheirOwnTmpPath(t *testing.T) {
+	basePath := t.TempDir()
+	first, second := newDownload(DownloadProgress{}, nil, nil), newDownload(DownloadProgress{}, nil, nil)
+	first.basePath, second.basePath = basePath, basePath
+	firstPath, err := first.tmpPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondPath, err := second.tmpPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstPath == secondPath {
+		t.Fatal("expected every download to have its own temporary directory")
+	}
+	first.removeTmpPath()
+	if _, err := os.Stat(secondPath); err != nil {
+		t.Errorf("expected the other download's directory to be kept, got %v", err)
+	}
+	if dirs := tmpDirs(t, basePath); len(dirs) != 1 {
+		t.Errorf("expected one temporary directory left, found %v", dirs)
+	}
+}
@@ -0,0 +1,178 @@
+package settings
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+var ErrUnsafeArchive = errors.New("archive contains an unsafe entry")
+
+// stagedEntryPath maps a tar entry name to its location in the staging
+// directory. Entries must be relative, must not escape through "..", and must
+// belong to the group directory the archive was downloaded for.
+func stagedEntryPath(stagingRoot string, groupDir string, name string) (string, error) {
+	if name == "" || strings.Contains(name, "\\") || path.IsAbs(name) {
+		return "", errors.Wrapf(ErrUnsafeArchive, "invalid entry name %q", name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return "", errors.Wrapf(ErrUnsafeArchive, "entry %q leaves the archive root", name)
+		}
+	}
+	cleaned := path.Clean(name)
+	if cleaned != groupDir && !strings.HasPrefix(cleaned, groupDir+"/") {
+		return "", errors.Wrapf(ErrUnsafeArchive, "entry %q is outside of %s", name, groupDir)
+	}
+	return filepath.Join(stagingRoot, filepath.FromSlash(cleaned)), nil
+}
+
+// extractArchive extracts a group archive below stagingRoot. Only directories
+// and regular files are accepted, links and devices are rejected.
+func extractArchive(archiveName string, stagingRoot string, groupDir string) error {
+	file, err := os.Open(archiveName)
+	if err != nil {
+		return errors.Wrap(err, "failed to open downloaded file")
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return errors.Wrapf(ErrIntegrity, "failed to parse gzip downloaded file: %s", err)
+	}
+	defer reader.Close()
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrapf(ErrIntegrity, "failed to read tar downloaded file: %s", err)
+		}
+
+		// if the header is nil, just skip it (not sure how this happens)
+		if header == nil {
+			continue
+		}
+		// parent directories of the group (offline/, offline/<lat>/) already exist
+		if header.Typeflag == tar.TypeDir && strings.HasPrefix(groupDir+"/", path.Clean(header.Name)+"/") && path.Clean(header.Name) != groupDir {
+			continue
+		}
+		target, err := stagedEntryPath(stagingRoot, groupDir, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err := os.MkdirAll(target, 0o755)
+			if err != nil {
+				return errors.Wrap(err, "could not create directory from downloaded archive")
+			}
+		case tar.TypeReg:
+			err := writeStagedFile(target, tr, os.FileMode(header.Mode).Perm())
+			if err != nil {
+				return err
+			}
+		default:
+			return errors.Wrapf(ErrUnsafeArchive, "entry %q has unsupported type %c", header.Name, header.Typeflag)
+		}
+	}
+	return nil
+}
+
+// verifyStagedPack checks the header of the pack of a staged group, if it has
+// one, and that every tile it lists is inside of the pack.
+func verifyStagedPack(stagedGroup string) error {
+	f, err := os.Open(filepath.Join(stagedGroup, PACK_FILE))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not open staged pack")
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Wrap(err, "could not stat staged pack")
+	}
+	_, err = ReadPackHeader(f, info.Size())
+	return err
+}
+
+func writeStagedFile(target string, data io.Reader, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(target), 0o755)
+	if err != nil {
+		return errors.Wrap(err, "could not create directory from downloaded archive")
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return errors.Wrap(err, "could not open file target from downloaded archive")
+	}
+	defer f.Close()
+	_, err = io.Copy(f, data)
+	if err != nil {
+		return errors.Wrapf(ErrIntegrity, "could not write data to file target from downloaded archive: %s", err)
+	}
+	err = f.Sync()
+	if err != nil {
+		return errors.Wrap(err, "could not fsync file target from downloaded archive")
+	}
+	return nil
+}
+
+// installGroup replaces the live group directory with the staged one. The
+// staged directory is left holding the previous tiles (if any) so the caller
+// can remove it once the new tiles are in place.
+func installGroup(stagedDir string, liveDir string) error {
+	err := os.MkdirAll(filepath.Dir(liveDir), 0o755)
+	if err != nil {
+		return errors.Wrap(err, "could not create offline group parent directory")
+	}
+	if _, err := os.Stat(liveDir); os.IsNotExist(err) {
+		err = os.Rename(stagedDir, liveDir)
+		return errors.Wrap(err, "could not move staged tiles into place")
+	}
+	err = exchangeDirectories(stagedDir, liveDir)
+	return errors.Wrap(err, "could not swap staged tiles into place")
+}
+
+// exchangeDirectories atomically swaps a and b so readers of b always see
+// either the complete old or the complete new directory.
+func exchangeDirectories(a string, b string) error {
+	err := unix.Renameat2(unix.AT_FDCWD, a, unix.AT_FDCWD, b, unix.RENAME_EXCHANGE)
+	if errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EINVAL) {
+		return renameExchange(a, b)
+	}
+	return err
+}
+
+// renameExchange swaps two directories with plain renames. There is a short
+// window where b does not exist, so it is only used when the filesystem
+// cannot exchange atomically.
+func renameExchange(a string, b string) error {
+	old := b + ".old"
+	err := os.RemoveAll(old)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(b, old)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(a, b)
+	if err != nil {
+		if restoreErr := os.Rename(old, b); restoreErr != nil {
+			slog.Error("could not restore previous map tiles", "error", restoreErr, "directory", b)
+		}
+		return err
+	}
+	return os.Rename(old, a)
+}
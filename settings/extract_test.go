@@ -0,0 +1,152 @@
+package settings
+
+import (
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStagedEntryPath(t *testing.T) {
+	root := filepath.FromSlash("/staging")
+	valid := map[string]string{
+		"offline/0/0":       "/staging/offline/0/0",
+		"offline/0/0/":      "/staging/offline/0/0",
+		"offline/0/0/tile":  "/staging/offline/0/0/tile",
+		"./offline/0/0/a_b": "/staging/offline/0/0/a_b",
+	}
+	for name, expected := range valid {
+		target, err := stagedEntryPath(root, "offline/0/0", name)
+		if err != nil || target != filepath.FromSlash(expected) {
+			t.Errorf("stagedEntryPath(%q) = %q, %v, expected %q", name, target, err, expected)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"/etc/passwd",
+		"../outside",
+		"offline/0/0/../../../outside",
+		"offline/0/0/../2/tile",
+		"offline/0/2/tile",
+		"offline/0/00/tile",
+		"offline\\0\\0\\tile",
+	}
+	for _, name := range invalid {
+		if _, err := stagedEntryPath(root, "offline/0/0", name); !errors.Is(err, ErrUnsafeArchive) {
+			t.Errorf("stagedEntryPath(%q) should be rejected, got %v", name, err)
+		}
+	}
+}
+
+func writeLiveTile(t *testing.T, basePath string, name string, content string) string {
+	t.Helper()
+	target := filepath.Join(basePath, name)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return target
+}
+
+func TestDownloadBoundsRejectsPathTraversal(t *testing.T) {
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz": testArchive(t, map[string]string{"offline/0/0/../../../escaped": "evil"}),
+		},
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	live := writeLiveTile(t, d.basePath, "offline/0/0/tile", "old")
+	err, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}, "test.location")
+	if err != nil || canceled {
+		t.Fatalf("downloadBounds() = %v, %v", err, canceled)
+	}
+	if len(d.progress.FailedFiles) != 1 || d.progress.FailedFiles[0].Attempts != 1 {
+		t.Fatalf("expected a single failed attempt, got %+v", d.progress.FailedFiles)
+	}
+	if _, err := os.Stat(filepath.Join(d.basePath, "escaped")); !os.IsNotExist(err) {
+		t.Error("archive entry escaped the base path")
+	}
+	if data, err := os.ReadFile(live); err != nil || string(data) != "old" {
+		t.Error("live tiles should be untouched by a rejected archive")
+	}
+}
+
+func TestDownloadBoundsSwapsGroupDirectory(t *testing.T) {
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz": testArchive(t, map[string]string{"offline/0/0/tile": "new"}),
+		},
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	live := writeLiveTile(t, d.basePath, "offline/0/0/tile", "old")
+	stale := writeLiveTile(t, d.basePath, "offline/0/0/stale", "old")
+	neighbour := writeLiveTile(t, d.basePath, "offline/0/2/tile", "neighbour")
+	err, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}, "test.location")
+	if err != nil || canceled {
+		t.Fatalf("downloadBounds() = %v, %v", err, canceled)
+	}
+	if len(d.progress.FailedFiles) != 0 {
+		t.Fatalf("unexpected failed files: %+v", d.progress.FailedFiles)
+	}
+	if data, err := os.ReadFile(live); err != nil || string(data) != "new" {
+		t.Errorf("expected the new tile to be installed, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("tiles missing from the new archive should be gone after the swap")
+	}
+	if data, err := os.ReadFile(neighbour); err != nil || string(data) != "neighbour" {
+		t.Error("other groups should not be touched")
+	}
+	if dirs := tmpDirs(t, d.basePath); len(dirs) != 0 {
+		t.Errorf("expected the staging directory to be cleaned up, found %v", dirs)
+	}
+}
+
+func TestRenameExchange(t *testing.T) {
+	dir := t.TempDir()
+	a := writeLiveTile(t, dir, "a/tile", "a")
+	b := writeLiveTile(t, dir, "b/tile", "b")
+	if err := renameExchange(filepath.Dir(a), filepath.Dir(b)); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(a); string(data) != "b" {
+		t.Errorf("expected a to hold b's data, got %q", data)
+	}
+	if data, _ := os.ReadFile(b); string(data) != "a" {
+		t.Errorf("expected b to hold a's data, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Dir(b) + ".old"); !os.IsNotExist(err) {
+		t.Error("expected no leftover directory")
+	}
+}
+
+func TestVerifyStagedPack(t *testing.T) {
+	dir := t.TempDir()
+	if err := verifyStagedPack(dir); err != nil {
+		t.Errorf("expected a group without a pack to pass, got %v", err)
+	}
+	writeLiveTile(t, dir, PACK_FILE, PACK_MAGIC+"\x02\x00\x00\x00\x00\x00\x00\x00")
+	if err := verifyStagedPack(dir); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("expected a pack missing its tile entries to fail, got %v", err)
+	}
+	writeLiveTile(t, dir, PACK_FILE, PACK_MAGIC+"\x00\x00\x00\x00\x00\x00\x00\x00")
+	if err := verifyStagedPack(dir); err != nil {
+		t.Errorf("expected an empty pack to pass, got %v", err)
+	}
+	writeLiveTile(t, dir, PACK_FILE, "not a pack")
+	if err := verifyStagedPack(dir); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("expected an unknown format to fail, got %v", err)
+	}
+}
@@ -0,0 +1,399 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrDownloadCanceled = errors.New("download canceled")
+	ErrNotFound         = errors.New("file not found on server")
+	errPaused           = errors.New("download paused")
+)
+
+// FileFetcher downloads files over http, resuming partially downloaded files
+// with range requests and retrying failed attempts with exponential backoff.
+type FileFetcher struct {
+	Client      *http.Client
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	IdleTimeout time.Duration                     // abort an attempt when no data arrives for this long
+	Canceled    func() bool                       // polled between attempts and while waiting to retry
+	Paused      func() bool                       // polled before and during transfers, data is kept while paused
+	PausePoll   time.Duration                     // how often to check whether a paused download may continue
+	Progress    func(received int64, total int64) // bytes of the file on disk so far and its size, -1 when unknown
+	Throttle    func(n int)                       // called after every read and blocks while over the bandwidth cap
+}
+
+func NewFileFetcher() FileFetcher {
+	return FileFetcher{
+		Client:      http.DefaultClient,
+		MaxAttempts: DOWNLOAD_MAX_ATTEMPTS,
+		BaseDelay:   DOWNLOAD_RETRY_BASE_DELAY,
+		MaxDelay:    DOWNLOAD_RETRY_MAX_DELAY,
+		IdleTimeout: DOWNLOAD_IDLE_TIMEOUT,
+		PausePoll:   DOWNLOAD_PAUSE_POLL,
+	}
+}
+
+type fetchError struct {
+	err       error
+	retryable bool
+}
+
+func (e *fetchError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fetchError) Unwrap() error {
+	return e.err
+}
+
+func retryable(err error) *fetchError {
+	return &fetchError{err: err, retryable: true}
+}
+
+func permanent(err error) *fetchError {
+	return &fetchError{err: err, retryable: false}
+}
+
+func (f FileFetcher) canceled() bool {
+	return f.Canceled != nil && f.Canceled()
+}
+
+// the delay before the given retry attempt (1 based), doubling each attempt
+func (f FileFetcher) backoff(attempt int) time.Duration {
+	delay := f.BaseDelay
+	for i := 1; i < attempt && delay < f.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, f.MaxDelay)
+}
+
+func (f FileFetcher) paused() bool {
+	return f.Paused != nil && f.Paused()
+}
+
+// waitWhilePaused blocks until transfers are allowed again. It returns false
+// when the download was canceled while waiting.
+func (f FileFetcher) waitWhilePaused() bool {
+	for f.paused() {
+		if f.canceled() {
+			return false
+		}
+		time.Sleep(max(f.PausePoll, time.Millisecond))
+	}
+	return !f.canceled()
+}
+
+func (f FileFetcher) wait(delay time.Duration) bool {
+	deadline := time.Now().Add(delay)
+	for time.Now().Before(deadline) {
+		if f.canceled() {
+			return false
+		}
+		time.Sleep(min(100*time.Millisecond, time.Until(deadline)))
+	}
+	return !f.canceled()
+}
+
+// Fetch downloads url into path. Data already in path from an earlier failed
+// attempt is kept and only the remainder is requested, as long as the file on
+// the server did not change in between. It returns the number of attempts
+// used. A file that could not be completed is removed.
+func (f FileFetcher) Fetch(url string, path string) (attempts int, err error) {
+	maxAttempts := max(f.MaxAttempts, 1)
+	validator := ""
+	for attempts = 1; ; attempts++ {
+		if !f.waitWhilePaused() {
+			return attempts, ErrDownloadCanceled
+		}
+		ferr := f.fetchOnce(url, path, &validator)
+		if ferr == nil {
+			return attempts, nil
+		}
+		if errors.Is(ferr, ErrDownloadCanceled) {
+			// the partial file is kept, the caller decides whether to resume it
+			return attempts, ErrDownloadCanceled
+		}
+		if errors.Is(ferr, errPaused) {
+			// a pause is not a failed attempt, the partial file is resumed later
+			attempts--
+			continue
+		}
+		err = ferr
+		if !ferr.retryable || attempts == maxAttempts {
+			break
+		}
+		delay := f.backoff(attempts)
+		slog.Warn("download attempt failed, retrying", "error", ferr, "url", url, "attempt", attempts, "delay", delay)
+		if !f.wait(delay) {
+			return attempts, ErrDownloadCanceled
+		}
+	}
+	if rmErr := os.Remove(path); rmErr != nil && !os.IsNotExist(rmErr) {
+		slog.Warn("could not remove incomplete download", "error", rmErr, "file", path)
+	}
+	return attempts, errors.Wrapf(err, "download failed after %d attempts", attempts)
+}
+
+// rangeValidator returns the strong ETag or the modification time of a
+// response, which a resumed request sends as If-Range so the server only
+// resumes the same version of the file.
+func rangeValidator(header http.Header) string {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return header.Get("Last-Modified")
+}
+
+// fetchOnce makes a single attempt at downloading url into path. validator is
+// the version of the file the data in path belongs to.
+func (f FileFetcher) fetchOnce(url string, path string, validator *string) *fetchError {
+	offset := int64(0)
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return permanent(errors.Wrap(err, "could not create download request"))
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if *validator != "" {
+			req.Header.Set("If-Range", *validator)
+		}
+	}
+
+	// a cancel aborts the request right away instead of after the current file
+	if f.Canceled != nil {
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			ticker := time.NewTicker(DOWNLOAD_CANCEL_POLL)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stop:
+					return
+				case <-ticker.C:
+					if f.canceled() {
+						cancel()
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	// the watchdog also covers waiting for the response headers
+	var watchdog *time.Timer
+	if f.IdleTimeout > 0 {
+		watchdog = time.AfterFunc(f.IdleTimeout, cancel)
+		defer watchdog.Stop()
+	}
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil && f.canceled() {
+		return permanent(ErrDownloadCanceled)
+	}
+	if err != nil {
+		return retryable(errors.Wrap(err, "could not download the file data"))
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	total := int64(-1)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// the server ignored the range request, the file changed since the
+		// partial data was received or we are starting fresh
+		flags |= os.O_TRUNC
+		offset = 0
+		total = resp.ContentLength
+		*validator = rangeValidator(resp.Header)
+	case http.StatusPartialContent:
+		start, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil || start != offset {
+			os.Remove(path)
+			return retryable(errors.Errorf("server resumed at the wrong offset: %q", resp.Header.Get("Content-Range")))
+		}
+		flags |= os.O_APPEND
+		total = size
+	case http.StatusRequestedRangeNotSatisfiable:
+		_, total, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err == nil && total == offset {
+			return nil // already complete
+		}
+		os.Remove(path)
+		return retryable(errors.New("partial download is larger than the remote file"))
+	case http.StatusNotFound:
+		return permanent(errors.Wrapf(ErrNotFound, "download received bad status: %s", resp.Status))
+	default:
+		err := errors.Errorf("download received bad status: %s", resp.Status)
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return retryable(err)
+		}
+		return permanent(err)
+	}
+
+	out, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return permanent(errors.Wrap(err, "could not create file for download"))
+	}
+	defer out.Close()
+
+	var body io.Reader = resp.Body
+	if watchdog != nil {
+		watchdog.Reset(f.IdleTimeout)
+		body = &idleReader{reader: resp.Body, watchdog: watchdog, timeout: f.IdleTimeout}
+	}
+	if f.Paused != nil {
+		body = &pauseReader{reader: body, paused: f.Paused}
+	}
+	if f.Throttle != nil {
+		throttle := f.Throttle
+		if watchdog != nil {
+			// waiting for the bandwidth cap is not an idle connection
+			throttle = func(n int) {
+				watchdog.Stop()
+				f.Throttle(n)
+				watchdog.Reset(f.IdleTimeout)
+			}
+		}
+		body = &throttleReader{reader: body, throttle: throttle}
+	}
+	if f.Progress != nil {
+		f.Progress(offset, total)
+		body = &progressReader{reader: body, received: offset, total: total, report: f.Progress}
+	}
+	written, err := io.Copy(out, body)
+	if errors.Is(err, errPaused) {
+		return retryable(errPaused)
+	}
+	if err != nil && f.canceled() {
+		return permanent(ErrDownloadCanceled)
+	}
+	if err != nil {
+		return retryable(errors.Wrap(err, "could not write download data to file"))
+	}
+	if resp.ContentLength >= 0 && written != resp.ContentLength {
+		return retryable(errors.Errorf("download ended early, received %d of %d bytes", written, resp.ContentLength))
+	}
+	err = out.Sync()
+	if err != nil {
+		return permanent(errors.Wrap(err, "could not fsync downloaded file"))
+	}
+	return nil
+}
+
+// idleReader pushes back a watchdog timer on every read so stalled
+// connections are aborted instead of hanging the download forever.
+type idleReader struct {
+	reader   io.Reader
+	watchdog *time.Timer
+	timeout  time.Duration
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.watchdog.Reset(r.timeout)
+	return n, err
+}
+
+// pauseReader stops a transfer as soon as downloads are paused
+type pauseReader struct {
+	reader io.Reader
+	paused func() bool
+}
+
+func (r *pauseReader) Read(p []byte) (int, error) {
+	if r.paused() {
+		return 0, errPaused
+	}
+	return r.reader.Read(p)
+}
+
+// throttleReader reads in small chunks and waits for the bandwidth cap after
+// each of them
+type throttleReader struct {
+	reader   io.Reader
+	throttle func(n int)
+}
+
+func (r *throttleReader) Read(p []byte) (int, error) {
+	if len(p) > DOWNLOAD_THROTTLE_CHUNK {
+		p = p[:DOWNLOAD_THROTTLE_CHUNK]
+	}
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.throttle(n)
+	}
+	return n, err
+}
+
+// progressReader reports the bytes received so far after every read
+type progressReader struct {
+	reader   io.Reader
+	received int64
+	total    int64
+	report   func(received int64, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.received += int64(n)
+		r.report(r.received, r.total)
+	}
+	return n, err
+}
+
+// parseContentRange parses "bytes start-end/total" and "bytes */total"
+// headers. total is -1 when the server reports it as unknown.
+func parseContentRange(header string) (start int64, total int64, err error) {
+	rangeSpec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, errors.Errorf("invalid content range %q", header)
+	}
+	span, size, found := strings.Cut(rangeSpec, "/")
+	if !found {
+		return 0, 0, errors.Errorf("invalid content range %q", header)
+	}
+	total = -1
+	if size != "*" {
+		total, err = strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return 0, 0, errors.Wrap(err, "invalid content range size")
+		}
+	}
+	if span == "*" {
+		return 0, total, nil
+	}
+	first, _, found := strings.Cut(span, "-")
+	if !found {
+		return 0, 0, errors.Errorf("invalid content range %q", header)
+	}
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid content range start")
+	}
+	return start, total, nil
+}
@@ -0,0 +1,384 @@
+package settings
+
+import (
+	"encoding/json"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"pfeifer.dev/mapd/params"
+)
+
+const INVENTORY_FILE = "map_inventory.json"
+
+// Inventory records which download regions and group archives are installed
+// under the osm base path along with the data version of each group.
+type Inventory struct {
+	Regions map[string]*InstalledRegion `json:"regions"` // download path -> region
+	Groups  map[string]*InstalledGroup  `json:"groups"`  // group archive name -> group
+}
+
+type InstalledRegion struct {
+	Groups      []string  `json:"groups"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+type InstalledGroup struct {
+	Version     string    `json:"version"`
+	Sha256      string    `json:"sha256"`
+	Size        int64     `json:"size"` // bytes of installed tiles
+	InstalledAt time.Time `json:"installed_at"`
+	LastAccess  time.Time `json:"last_access"` // last time mapd loaded tiles from the group
+}
+
+// lastUsed is when the group was last driven in or installed, whichever is
+// later. Groups that were never used sort first for eviction.
+func (g *InstalledGroup) lastUsed() time.Time {
+	if g.LastAccess.After(g.InstalledAt) {
+		return g.LastAccess
+	}
+	return g.InstalledAt
+}
+
+var ErrRegionNotInstalled = errors.New("region is not installed")
+
+var inventoryLock sync.Mutex
+
+func inventoryPath(basePath string) string {
+	return filepath.Join(basePath, INVENTORY_FILE)
+}
+
+func readInventory(basePath string) (inventory Inventory, err error) {
+	inventory = Inventory{
+		Regions: map[string]*InstalledRegion{},
+		Groups:  map[string]*InstalledGroup{},
+	}
+	data, err := os.ReadFile(inventoryPath(basePath))
+	if os.IsNotExist(err) {
+		return inventory, nil
+	}
+	if err != nil {
+		return inventory, errors.Wrap(err, "could not read map inventory")
+	}
+	err = json.Unmarshal(data, &inventory)
+	if err != nil {
+		return inventory, errors.Wrap(err, "could not parse map inventory")
+	}
+	if inventory.Regions == nil {
+		inventory.Regions = map[string]*InstalledRegion{}
+	}
+	if inventory.Groups == nil {
+		inventory.Groups = map[string]*InstalledGroup{}
+	}
+	return inventory, nil
+}
+
+func writeInventory(basePath string, inventory Inventory) error {
+	data, err := json.Marshal(inventory)
+	if err != nil {
+		return errors.Wrap(err, "could not encode map inventory")
+	}
+	tmpPath := inventoryPath(basePath) + ".tmp"
+	err = os.WriteFile(tmpPath, data, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "could not write map inventory")
+	}
+	err = os.Rename(tmpPath, inventoryPath(basePath))
+	return errors.Wrap(err, "could not replace map inventory")
+}
+
+func LoadInventory(basePath string) (Inventory, error) {
+	inventoryLock.Lock()
+	defer inventoryLock.Unlock()
+	return readInventory(basePath)
+}
+
+// UpdateInventory loads the inventory, applies update and writes it back.
+func UpdateInventory(basePath string, update func(inventory *Inventory)) error {
+	inventoryLock.Lock()
+	defer inventoryLock.Unlock()
+	inventory, err := readInventory(basePath)
+	if err != nil {
+		return err
+	}
+	update(&inventory)
+	return writeInventory(basePath, inventory)
+}
+
+// seedInventory records the groups on disk that are missing from the
+// inventory, like the ones installed before it existed, so they are checked
+// for updates too. Their version comes from the manifest installed with them
+// when there is one.
+func seedInventory(basePath string) error {
+	groups, err := offlineGroups(basePath)
+	if err != nil {
+		return err
+	}
+	inventoryLock.Lock()
+	defer inventoryLock.Unlock()
+	inventory, err := readInventory(basePath)
+	if err != nil {
+		return err
+	}
+	seeded := 0
+	for _, group := range groups {
+		if _, ok := inventory.Groups[group]; ok {
+			continue
+		}
+		var manifest *GroupManifest
+		if installed, err := ReadGroupManifest(filepath.Join(basePath, GroupManifestName(group))); err == nil {
+			manifest = &installed
+		}
+		size, err := directorySize(groupDirectory(basePath, group))
+		if err != nil {
+			slog.Warn("could not measure installed map data", "error", err, "file", group)
+		}
+		inventory.recordGroup("", group, manifest, size)
+		seeded++
+	}
+	if seeded == 0 {
+		return nil
+	}
+	slog.Info("recorded map data installed without the inventory", "groups", seeded)
+	return writeInventory(basePath, inventory)
+}
+
+// recordGroup marks a group archive as installed for a region. A nil manifest
+// means the group was installed from a source without manifests.
+func (inventory *Inventory) recordGroup(region string, filename string, manifest *GroupManifest, size int64) {
+	group := &InstalledGroup{Size: size, InstalledAt: time.Now()}
+	if previous, ok := inventory.Groups[filename]; ok {
+		group.LastAccess = previous.LastAccess
+	}
+	if manifest != nil {
+		group.Version = manifest.Version
+		group.Sha256 = manifest.Archive.Sha256
+	}
+	inventory.Groups[filename] = group
+
+	if region == "" {
+		return
+	}
+	r, ok := inventory.Regions[region]
+	if !ok {
+		r = &InstalledRegion{InstalledAt: time.Now()}
+		inventory.Regions[region] = r
+	}
+	if !slices.Contains(r.Groups, filename) {
+		r.Groups = append(r.Groups, filename)
+		slices.Sort(r.Groups)
+	}
+}
+
+// RegionNames returns the installed download paths in a stable order.
+func (inventory *Inventory) RegionNames() []string {
+	names := make([]string, 0, len(inventory.Regions))
+	for name := range inventory.Regions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// regionsWithGroup returns the installed regions that include a group archive.
+func (inventory *Inventory) regionsWithGroup(filename string) (regions []string) {
+	for _, name := range inventory.RegionNames() {
+		if slices.Contains(inventory.Regions[name].Groups, filename) {
+			regions = append(regions, name)
+		}
+	}
+	return regions
+}
+
+// removeGroup forgets a group archive and drops regions left without groups.
+func (inventory *Inventory) removeGroup(filename string) {
+	delete(inventory.Groups, filename)
+	for name, region := range inventory.Regions {
+		region.Groups = slices.DeleteFunc(region.Groups, func(group string) bool { return group == filename })
+		if len(region.Groups) == 0 {
+			delete(inventory.Regions, name)
+		}
+	}
+}
+
+// groupDirectory is the directory an archive's tiles are installed to
+func groupDirectory(basePath string, filename string) string {
+	return filepath.Join(basePath, filepath.FromSlash(strings.TrimSuffix(filename, ".tar.gz")))
+}
+
+func directorySize(dir string) (size int64, err error) {
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// DeleteRegion removes an installed region. Groups that another installed
+// region still uses are kept. It returns the group archives that were removed.
+func DeleteRegion(basePath string, region string) (removed []string, err error) {
+	inventoryLock.Lock()
+	defer inventoryLock.Unlock()
+	inventory, err := readInventory(basePath)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := inventory.Regions[region]
+	if !ok {
+		return nil, errors.Wrapf(ErrRegionNotInstalled, "could not delete %s", region)
+	}
+	delete(inventory.Regions, region)
+
+	for _, filename := range r.Groups {
+		if len(inventory.regionsWithGroup(filename)) > 0 {
+			continue
+		}
+		err = removeGroupFiles(basePath, filename)
+		if err != nil {
+			// keep the inventory consistent with what is still on disk
+			r.Groups = slices.DeleteFunc(r.Groups, func(group string) bool {
+				return slices.Contains(removed, group)
+			})
+			inventory.Regions[region] = r
+			writeErr := writeInventory(basePath, inventory)
+			if writeErr != nil {
+				slog.Warn("could not record deleted map data", "error", writeErr, "region", region)
+			}
+			return removed, err
+		}
+		delete(inventory.Groups, filename)
+		removed = append(removed, filename)
+	}
+	return removed, writeInventory(basePath, inventory)
+}
+
+// removeGroupFiles deletes the tiles and manifest of an installed group.
+func removeGroupFiles(basePath string, filename string) error {
+	dir := groupDirectory(basePath, filename)
+	err := os.RemoveAll(dir)
+	if err != nil {
+		return errors.Wrapf(err, "could not delete %s", filename)
+	}
+	err = os.Remove(filepath.Join(basePath, GroupManifestName(filename)))
+	if err != nil && !os.IsNotExist(err) {
+		slog.Warn("could not delete group manifest", "error", err, "file", filename)
+	}
+	// drop the latitude directory once its last group is gone
+	os.Remove(filepath.Dir(dir))
+	return nil
+}
+
+// DeleteInstalledRegion deletes a region from the osm base path.
+func DeleteInstalledRegion(region string) {
+	removed, err := DeleteRegion(params.GetBaseOpPath(), region)
+	if err != nil {
+		slog.Warn("could not delete region", "error", err, "region", region, "removed_files", len(removed))
+		return
+	}
+	slog.Info("deleted region", "region", region, "removed_files", len(removed))
+}
+
+// RegionSummary describes an installed region for display.
+type RegionSummary struct {
+	Name        string
+	FullName    string
+	Groups      int
+	Size        int64
+	DataVersion string // oldest data version of the region's groups
+	InstalledAt time.Time
+}
+
+func (inventory *Inventory) Summary() []RegionSummary {
+	summaries := []RegionSummary{}
+	for _, name := range inventory.RegionNames() {
+		region := inventory.Regions[name]
+		summary := RegionSummary{
+			Name:        name,
+			FullName:    regionFullName(name),
+			Groups:      len(region.Groups),
+			InstalledAt: region.InstalledAt,
+		}
+		for _, filename := range region.Groups {
+			group, ok := inventory.Groups[filename]
+			if !ok {
+				continue
+			}
+			summary.Size += group.Size
+			if group.Version != "" && (summary.DataVersion == "" || group.Version < summary.DataVersion) {
+				summary.DataVersion = group.Version
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// Size is the total size of installed tiles, counting shared groups once.
+func (inventory *Inventory) Size() (size int64) {
+	for _, group := range inventory.Groups {
+		size += group.Size
+	}
+	return size
+}
+
+// regionFullName resolves download menu paths to their display name. Radius
+// and live downloads are named after themselves.
+func regionFullName(name string) string {
+	if strings.HasPrefix(name, "radius:") || name == LIVE_DOWNLOAD_REGION {
+		return name
+	}
+	fullNames := []string{}
+	for _, path := range strings.Split(name, ",") {
+		fullName := getDataForPath(path).FullName
+		if fullName == "" {
+			return name
+		}
+		fullNames = append(fullNames, fullName)
+	}
+	return strings.Join(fullNames, ", ")
+}
+
+// InventoryCache keeps the inventory in memory and reloads it when the file
+// on disk changes.
+type InventoryCache struct {
+	modTime   time.Time
+	inventory Inventory
+}
+
+func (c *InventoryCache) Get(basePath string) Inventory {
+	info, err := os.Stat(inventoryPath(basePath))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("could not check map inventory", "error", err)
+		}
+		c.modTime = time.Time{}
+		c.inventory = Inventory{}
+		return c.inventory
+	}
+	if info.ModTime().Equal(c.modTime) {
+		return c.inventory
+	}
+	inventory, err := LoadInventory(basePath)
+	if err != nil {
+		slog.Warn("could not load map inventory", "error", err)
+		return c.inventory
+	}
+	c.modTime = info.ModTime()
+	c.inventory = inventory
+	return inventory
+}
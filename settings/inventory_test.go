@@ -0,0 +1,121 @@
+package settings
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestDeleteRegionKeepsSharedGroups(t *testing.T) {
+	west := testArchive(t, map[string]string{"offline/0/0/tile": "west"})
+	shared := testArchive(t, map[string]string{"offline/0/2/tile": "shared"})
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz":        west,
+			"/offline/0/0.manifest.json": testManifest(t, west),
+			"/offline/0/2.tar.gz":        shared,
+			"/offline/0/2.manifest.json": testManifest(t, shared),
+		},
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	d.progress.LocationDetails["other.location"] = &DownloadLocationDetail{}
+	d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 3}, "test.location")
+	d.downloadBounds(Bounds{MinLat: 0, MinLon: 2, MaxLat: 1, MaxLon: 3}, "other.location")
+	if len(d.progress.FailedFiles) != 0 {
+		t.Fatalf("download failed: %+v", d.progress.FailedFiles)
+	}
+
+	inventory, err := LoadInventory(d.basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summaries := inventory.Summary()
+	if len(summaries) != 2 || summaries[1].Name != "test.location" || summaries[1].Groups != 2 {
+		t.Fatalf("unexpected summary: %+v", summaries)
+	}
+	if summaries[1].Size != int64(len("west")+len("shared")) || summaries[1].DataVersion != "test-version" {
+		t.Errorf("unexpected region size or version: %+v", summaries[1])
+	}
+	if inventory.Size() != int64(len("west")+len("shared")) {
+		t.Errorf("shared groups should be counted once, got %d bytes", inventory.Size())
+	}
+
+	removed, err := DeleteRegion(d.basePath, "test.location")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(removed, []string{"offline/0/0.tar.gz"}) {
+		t.Errorf("expected only the unshared group to be removed, got %v", removed)
+	}
+	if _, err := os.Stat(filepath.Join(d.basePath, "offline/0/0")); !os.IsNotExist(err) {
+		t.Error("unshared tiles were not deleted")
+	}
+	if _, err := os.Stat(filepath.Join(d.basePath, "offline/0/0.manifest.json")); !os.IsNotExist(err) {
+		t.Error("unshared manifest was not deleted")
+	}
+	if data, _ := os.ReadFile(filepath.Join(d.basePath, "offline/0/2/tile")); string(data) != "shared" {
+		t.Error("tiles used by another region should be kept")
+	}
+
+	_, err = DeleteRegion(d.basePath, "other.location")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(d.basePath, "offline/0")); !os.IsNotExist(err) {
+		t.Error("empty latitude directory was not removed")
+	}
+	inventory, err = LoadInventory(d.basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inventory.Regions) != 0 || len(inventory.Groups) != 0 {
+		t.Errorf("expected an empty inventory, got %+v", inventory)
+	}
+
+	_, err = DeleteRegion(d.basePath, "missing.location")
+	if !errors.Is(err, ErrRegionNotInstalled) {
+		t.Errorf("expected ErrRegionNotInstalled, got %v", err)
+	}
+}
+
+func TestDeleteRegionRecordsGroupsRemovedBeforeAFailure(t *testing.T) {
+	basePath := t.TempDir()
+	writeLiveTile(t, basePath, "offline/0/0/tile", "west")
+	// a file in place of a directory makes deleting the second group fail
+	writeLiveTile(t, basePath, "offline/0/2", "not a directory")
+	broken := "offline/0/2/broken.tar.gz"
+	err := UpdateInventory(basePath, func(inventory *Inventory) {
+		inventory.recordGroup("test.location", "offline/0/0.tar.gz", nil, 4)
+		inventory.recordGroup("test.location", broken, nil, 4)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := DeleteRegion(basePath, "test.location")
+	if err == nil {
+		t.Fatal("expected deleting the second group to fail")
+	}
+	if !slices.Equal(removed, []string{"offline/0/0.tar.gz"}) {
+		t.Errorf("expected the first group to be removed, got %v", removed)
+	}
+	inventory, err := LoadInventory(basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := inventory.Groups["offline/0/0.tar.gz"]; ok {
+		t.Error("expected the removed group to be gone from the inventory")
+	}
+	region := inventory.Regions["test.location"]
+	if region == nil || !slices.Equal(region.Groups, []string{broken}) {
+		t.Errorf("expected the region to keep only the group still on disk, got %+v", region)
+	}
+}
@@ -0,0 +1,59 @@
+package settings
+
+import (
+	"sync"
+	"time"
+)
+
+// DownloadLimits holds how many group archives a download fetches at once and
+// the bandwidth cap shared by every transfer, so map downloads leave room for
+// openpilot's own uploads. The cap is a token bucket that allows a burst of one
+// second worth of data.
+type DownloadLimits struct {
+	lock           sync.Mutex
+	workers        int
+	bytesPerSecond float64 // 0 is unlimited
+	tokens         float64
+	last           time.Time
+}
+
+var downloadLimits = &DownloadLimits{workers: DOWNLOAD_WORKERS}
+
+func (l *DownloadLimits) configure(settings DownloadSettings) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.workers = min(max(settings.Workers, 1), DOWNLOAD_MAX_WORKERS)
+	bytesPerSecond := max(float64(settings.BandwidthMbps), 0) * 1000 * 1000 / 8
+	if bytesPerSecond != l.bytesPerSecond {
+		l.bytesPerSecond = bytesPerSecond
+		l.tokens = bytesPerSecond
+		l.last = time.Now()
+	}
+}
+
+// Workers is how many group archives are downloaded in parallel.
+func (l *DownloadLimits) Workers() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.workers
+}
+
+// throttle blocks until n more bytes fit under the bandwidth cap.
+func (l *DownloadLimits) throttle(n int) {
+	l.lock.Lock()
+	if l.bytesPerSecond <= 0 {
+		l.lock.Unlock()
+		return
+	}
+	now := time.Now()
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.bytesPerSecond, l.bytesPerSecond)
+	l.last = now
+	// going into debt makes the next transfers wait their turn
+	l.tokens -= float64(n)
+	delay := time.Duration(0)
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.bytesPerSecond * float64(time.Second))
+	}
+	l.lock.Unlock()
+	time.Sleep(delay)
+}
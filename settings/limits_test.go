@@ -0,0 +1,153 @@
+package settings
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingServer sends half of every archive and then stalls until the
+// client goes away or release is closed.
+type blockingServer struct {
+	mu      sync.Mutex
+	active  int
+	peak    int
+	started chan string
+	release chan struct{}
+	data    []byte
+}
+
+func (s *blockingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, ".tar.gz") {
+		http.NotFound(w, r)
+		return
+	}
+	s.mu.Lock()
+	s.active++
+	s.peak = max(s.peak, s.active)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.active--
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Length", "100000")
+	w.WriteHeader(http.StatusOK)
+	w.Write(make([]byte, 1000))
+	w.(http.Flusher).Flush()
+	s.started <- r.URL.Path
+	select {
+	case <-r.Context().Done():
+	case <-s.release:
+	}
+}
+
+func TestDownloadGroupsRunsWorkersInParallel(t *testing.T) {
+	blocking := &blockingServer{started: make(chan string, 10), release: make(chan struct{})}
+	server := httptest.NewServer(blocking)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	d.workers = 3
+	done := make(chan bool)
+	go func() {
+		_, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 7}, "test.location")
+		done <- canceled
+	}()
+
+	// every worker has a transfer in flight before any of them finished
+	for range 3 {
+		select {
+		case <-blocking.started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the workers did not download in parallel")
+		}
+	}
+	blocking.mu.Lock()
+	peak := blocking.peak
+	blocking.mu.Unlock()
+	if peak != 3 {
+		t.Errorf("expected 3 parallel transfers, got %d", peak)
+	}
+
+	// a cancel interrupts the stalled transfers of all workers
+	start := time.Now()
+	d.cancelChan <- true
+	select {
+	case canceled := <-done:
+		if !canceled {
+			t.Error("expected the download to report the cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancel did not stop the workers")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancel took %v", elapsed)
+	}
+	if len(d.progress.FailedFiles) != 0 || d.progress.DownloadedFiles != 0 {
+		t.Errorf("canceled transfers should not be recorded: %+v", d.progress)
+	}
+	if _, err := os.Stat(groupDirectory(d.basePath, "offline/0/6.tar.gz")); !os.IsNotExist(err) {
+		t.Error("groups after the cancel should not be downloaded")
+	}
+	close(blocking.release)
+}
+
+func TestDownloadLimitsThrottle(t *testing.T) {
+	l := &DownloadLimits{}
+	l.configure(DownloadSettings{Workers: 20, BandwidthMbps: 0.8})
+	if l.Workers() != DOWNLOAD_MAX_WORKERS {
+		t.Errorf("expected the workers to be capped at %d, got %d", DOWNLOAD_MAX_WORKERS, l.Workers())
+	}
+
+	// 0.8 Mbit/s is 100000 bytes per second with a one second burst
+	start := time.Now()
+	l.throttle(100000)
+	l.throttle(50000)
+	elapsed := time.Since(start)
+	if elapsed < 400*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("expected about half a second of throttling, got %v", elapsed)
+	}
+
+	l.configure(DownloadSettings{Workers: 0})
+	if l.Workers() != 1 {
+		t.Errorf("expected at least one worker, got %d", l.Workers())
+	}
+	start = time.Now()
+	l.throttle(10000000)
+	if time.Since(start) > 100*time.Millisecond {
+		t.Error("an unlimited bandwidth should not throttle")
+	}
+}
+
+func TestThrottleWaitIsNotIdle(t *testing.T) {
+	data := make([]byte, 100000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	// 0.4 Mbit/s is 50000 bytes per second, so every chunk after the burst
+	// waits longer than the idle timeout
+	l := &DownloadLimits{}
+	l.configure(DownloadSettings{Workers: 1, BandwidthMbps: 0.4})
+	f := NewFileFetcher()
+	f.Client = server.Client()
+	f.MaxAttempts = 1
+	f.IdleTimeout = 150 * time.Millisecond
+	f.Throttle = l.throttle
+
+	path := filepath.Join(t.TempDir(), "file")
+	if _, err := f.Fetch(server.URL+"/file", path); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != int64(len(data)) {
+		t.Error("expected the whole file to be downloaded")
+	}
+}
@@ -0,0 +1,163 @@
+package settings
+
+import (
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+)
+
+// liveDownloader fetches the group archives for unmapped areas in the
+// background while driving. Requests for a group that is already queued or was
+// tried recently are dropped and downloads are spaced out so a vehicle sitting
+// at the edge of the map data does not hammer the map server.
+type liveDownloader struct {
+	lock        sync.Mutex
+	enabled     bool
+	queued      map[groupCell]bool
+	lastAttempt map[groupCell]time.Time
+	queue       chan groupCell
+	installed   chan bool
+	cancelChan  chan bool
+	minInterval time.Duration
+	retryDelay  time.Duration
+	newDownload func(cancelChan chan bool) *download
+}
+
+func newLiveDownloader() *liveDownloader {
+	return &liveDownloader{
+		queued:      map[groupCell]bool{},
+		lastAttempt: map[groupCell]time.Time{},
+		installed:   make(chan bool, 1),
+		minInterval: LIVE_DOWNLOAD_MIN_INTERVAL,
+		retryDelay:  LIVE_DOWNLOAD_RETRY_DELAY,
+		newDownload: func(cancelChan chan bool) *download {
+			return newDownload(DownloadProgress{
+				LocationDetails: map[string]*DownloadLocationDetail{LIVE_DOWNLOAD_REGION: {}},
+				Active:          true,
+			}, nil, cancelChan)
+		},
+	}
+}
+
+func groupForPosition(lat float64, lon float64) groupCell {
+	return groupCell{
+		Lat: int(math.Floor(lat/GROUP_AREA_BOX_DEGREES)) * GROUP_AREA_BOX_DEGREES,
+		Lon: normalizeGroupLongitude(int(math.Floor(lon/GROUP_AREA_BOX_DEGREES)) * GROUP_AREA_BOX_DEGREES),
+	}
+}
+
+// GroupForPosition is the archive of the group live downloads fetch for a
+// position.
+func GroupForPosition(lat float64, lon float64) string {
+	return groupForPosition(lat, lon).ArchiveName()
+}
+
+// setEnabled turns live downloads on or off. Turning them off drops anything
+// queued and cancels the download in progress.
+func (l *liveDownloader) setEnabled(enabled bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.enabled == enabled {
+		return
+	}
+	l.enabled = enabled
+	if !enabled && l.cancelChan != nil {
+		select {
+		case l.cancelChan <- true:
+		default:
+		}
+	}
+}
+
+// request queues a download of the group containing the position. It returns
+// false when the request was dropped.
+func (l *liveDownloader) request(lat float64, lon float64) bool {
+	if math.IsNaN(lat) || math.IsNaN(lon) || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return false
+	}
+	group := groupForPosition(lat, lon)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !l.enabled || l.queued[group] || time.Since(l.lastAttempt[group]) < l.retryDelay {
+		return false
+	}
+	if l.queue == nil {
+		l.queue = make(chan groupCell, LIVE_DOWNLOAD_QUEUE_SIZE)
+		go l.run()
+	}
+	select {
+	case l.queue <- group:
+		l.queued[group] = true
+		slog.Info("queued live map download", "file", group.ArchiveName())
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *liveDownloader) run() {
+	var lastStart time.Time
+	for group := range l.queue {
+		time.Sleep(time.Until(lastStart.Add(l.minInterval)))
+		lastStart = time.Now()
+
+		l.lock.Lock()
+		enabled := l.enabled
+		cancelChan := make(chan bool, 1)
+		l.cancelChan = cancelChan
+		l.lock.Unlock()
+
+		installed := false
+		if enabled {
+			installed = l.download(group, cancelChan)
+		}
+
+		l.lock.Lock()
+		l.cancelChan = nil
+		delete(l.queued, group)
+		l.lastAttempt[group] = time.Now()
+		l.lock.Unlock()
+
+		if installed {
+			select {
+			case l.installed <- true:
+			default:
+			}
+		}
+	}
+}
+
+func (l *liveDownloader) download(group groupCell, cancelChan chan bool) (installed bool) {
+	d := l.newDownload(cancelChan)
+	defer d.removeTmpPath()
+
+	filename := group.ArchiveName()
+	slog.Info("live downloading map data", "file", filename)
+	attempts, err := d.downloadGroup(LIVE_DOWNLOAD_REGION, filename)
+	if err != nil {
+		slog.Warn("live map download failed", "error", err, "file", filename, "attempts", attempts)
+		return false
+	}
+	slog.Info("live map download installed", "file", filename)
+	return true
+}
+
+// RequestLiveDownload queues a background download of the map data around a
+// position when live downloads are enabled.
+func (s *MapdSettings) RequestLiveDownload(lat float64, lon float64) {
+	s.applyDownloadSettings()
+	s.liveDownloads.request(lat, lon)
+}
+
+// LiveDownloadInstalled reports whether new live downloaded map data was
+// installed since the last call.
+func (s *MapdSettings) LiveDownloadInstalled() bool {
+	select {
+	case <-s.liveDownloads.installed:
+		return true
+	default:
+		return false
+	}
+}
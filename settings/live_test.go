@@ -0,0 +1,151 @@
+package settings
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newTestLiveDownloader(t *testing.T, server *httptest.Server) (*liveDownloader, string) {
+	t.Helper()
+	basePath := t.TempDir()
+	l := newLiveDownloader()
+	l.minInterval = 0
+	l.newDownload = func(cancelChan chan bool) *download {
+		d := newTestDownload(t, server)
+		d.basePath = basePath
+		d.cancelChan = cancelChan
+		return d
+	}
+	return l, basePath
+}
+
+func waitForLiveInstall(t *testing.T, l *liveDownloader) {
+	t.Helper()
+	select {
+	case <-l.installed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("live download was not installed")
+	}
+}
+
+func TestLiveDownloadDeduplicatesRequests(t *testing.T) {
+	archive := testArchive(t, map[string]string{"offline/0/0/tile": "live"})
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz":        archive,
+			"/offline/0/0.manifest.json": testManifest(t, archive),
+		},
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	l, basePath := newTestLiveDownloader(t, server)
+	if l.request(0.5, 0.5) {
+		t.Fatal("live downloads should be opt-in")
+	}
+
+	l.setEnabled(true)
+	if !l.request(0.5, 0.5) {
+		t.Fatal("expected the missing group to be queued")
+	}
+	l.request(1.5, 1.9) // same group
+	waitForLiveInstall(t, l)
+	if l.request(0.1, 0.1) {
+		t.Error("a group that was just downloaded should not be requested again")
+	}
+
+	flaky.mu.Lock()
+	requests := flaky.requests["/offline/0/0.tar.gz"]
+	flaky.mu.Unlock()
+	if requests != 1 {
+		t.Errorf("expected a single archive request, got %d", requests)
+	}
+	if data, _ := os.ReadFile(filepath.Join(basePath, "offline/0/0/tile")); string(data) != "live" {
+		t.Error("live downloaded tiles were not installed")
+	}
+	inventory, err := LoadInventory(basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	region := inventory.Regions[LIVE_DOWNLOAD_REGION]
+	if region == nil || !slices.Contains(region.Groups, "offline/0/0.tar.gz") {
+		t.Errorf("live download was not recorded in the inventory: %+v", inventory)
+	}
+	if dirs := tmpDirs(t, basePath); len(dirs) != 0 {
+		t.Errorf("temporary live download directory was not removed: %v", dirs)
+	}
+}
+
+func TestLiveDownloadRateLimit(t *testing.T) {
+	first := testArchive(t, map[string]string{"offline/0/0/tile": "a"})
+	second := testArchive(t, map[string]string{"offline/0/2/tile": "b"})
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz": first,
+			"/offline/0/2.tar.gz": second,
+		},
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	l, _ := newTestLiveDownloader(t, server)
+	l.minInterval = 200 * time.Millisecond
+	l.setEnabled(true)
+
+	start := time.Now()
+	l.request(0.5, 0.5)
+	waitForLiveInstall(t, l)
+	l.request(0.5, 2.5)
+	waitForLiveInstall(t, l)
+	if elapsed := time.Since(start); elapsed < l.minInterval {
+		t.Errorf("second live download started after %s, expected at least %s", elapsed, l.minInterval)
+	}
+}
+
+func TestLiveDownloadDisableCancels(t *testing.T) {
+	archive := testArchive(t, map[string]string{"offline/0/0/tile": "live"})
+	flaky := &flakyServer{
+		archives: map[string][]byte{"/offline/0/0.tar.gz": archive},
+		drops:    100,
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	l, basePath := newTestLiveDownloader(t, server)
+	newDownload := l.newDownload
+	l.newDownload = func(cancelChan chan bool) *download {
+		// only a cancel can end the wait between retries
+		d := newDownload(cancelChan)
+		d.fetcher.BaseDelay = time.Minute
+		d.fetcher.MaxDelay = time.Minute
+		return d
+	}
+	l.setEnabled(true)
+	l.request(0.5, 0.5)
+	time.Sleep(20 * time.Millisecond)
+	l.setEnabled(false)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		l.lock.Lock()
+		done := !l.queued[groupCell{0, 0}]
+		l.lock.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("disabling live downloads did not stop the download")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := os.Stat(filepath.Join(basePath, "offline/0/0")); !os.IsNotExist(err) {
+		t.Error("canceled live download should not install tiles")
+	}
+}
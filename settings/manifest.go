@@ -0,0 +1,158 @@
+package settings
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrIntegrity = errors.New("map data failed integrity verification")
+
+// GroupManifest describes a single group archive (offline/<lat>/<lon>.tar.gz)
+// and the tiles inside of it. It is published next to the archive as
+// offline/<lat>/<lon>.manifest.json and kept next to the installed tiles.
+type GroupManifest struct {
+	Version string            `json:"version"`
+	Archive FileDigest        `json:"archive"`
+	Tiles   map[string]string `json:"tiles"` // tar entry name -> sha256
+}
+
+type FileDigest struct {
+	Sha256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+func GroupManifestName(archiveName string) string {
+	return strings.TrimSuffix(archiveName, ".tar.gz") + ".manifest.json"
+}
+
+func ReadGroupManifest(path string) (manifest GroupManifest, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest, errors.Wrap(err, "could not read manifest")
+	}
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return manifest, errors.Wrap(err, "could not parse manifest")
+	}
+	return manifest, nil
+}
+
+func WriteGroupManifest(path string, manifest GroupManifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return errors.Wrap(err, "could not encode manifest")
+	}
+	err = os.WriteFile(path, data, 0o644)
+	return errors.Wrap(err, "could not write manifest")
+}
+
+func FileSha256(path string) (digest FileDigest, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return digest, errors.Wrap(err, "could not open file to hash")
+	}
+	defer f.Close()
+	h := sha256.New()
+	digest.Size, err = io.Copy(h, f)
+	if err != nil {
+		return digest, errors.Wrap(err, "could not hash file")
+	}
+	digest.Sha256 = hex.EncodeToString(h.Sum(nil))
+	return digest, nil
+}
+
+// VerifyArchive checks a downloaded archive against the manifest digest.
+func (m GroupManifest) VerifyArchive(path string) error {
+	digest, err := FileSha256(path)
+	if err != nil {
+		return err
+	}
+	if digest != m.Archive {
+		return errors.Wrapf(ErrIntegrity, "archive %s has sha256 %s (%d bytes), expected %s (%d bytes)", filepath.Base(path), digest.Sha256, digest.Size, m.Archive.Sha256, m.Archive.Size)
+	}
+	return nil
+}
+
+// VerifyTiles checks every tile listed in the manifest relative to basePath and
+// returns the names of the tiles that are missing or do not match.
+func (m GroupManifest) VerifyTiles(basePath string) (bad []string) {
+	for name, expected := range m.Tiles {
+		digest, err := FileSha256(filepath.Join(basePath, name))
+		if err != nil || digest.Sha256 != expected {
+			bad = append(bad, name)
+		}
+	}
+	return bad
+}
+
+// BuildGroupManifest hashes an archive and every regular file inside of it.
+func BuildGroupManifest(archivePath string, version string) (manifest GroupManifest, err error) {
+	manifest.Version = version
+	manifest.Tiles = map[string]string{}
+	manifest.Archive, err = FileSha256(archivePath)
+	if err != nil {
+		return manifest, err
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return manifest, errors.Wrap(err, "could not open archive")
+	}
+	defer f.Close()
+	reader, err := gzip.NewReader(f)
+	if err != nil {
+		return manifest, errors.Wrap(err, "could not read gzip archive")
+	}
+	defer reader.Close()
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, errors.Wrap(err, "could not read tar archive")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, tr)
+		if err != nil {
+			return manifest, errors.Wrap(err, "could not hash archive entry")
+		}
+		manifest.Tiles[header.Name] = hex.EncodeToString(h.Sum(nil))
+	}
+	return manifest, nil
+}
+
+// GenerateManifests writes a manifest next to every group archive found under
+// <directory>/offline. This is run on the server side after compressing tiles.
+func GenerateManifests(directory string, version string) error {
+	archives, err := filepath.Glob(filepath.Join(directory, "offline", "*", "*.tar.gz"))
+	if err != nil {
+		return errors.Wrap(err, "could not list group archives")
+	}
+	for _, archive := range archives {
+		manifest, err := BuildGroupManifest(archive, version)
+		if err != nil {
+			return errors.Wrapf(err, "could not build manifest for %s", archive)
+		}
+		err = WriteGroupManifest(GroupManifestName(archive), manifest)
+		if err != nil {
+			return err
+		}
+		slog.Info("wrote manifest", "archive", archive, "tiles", len(manifest.Tiles))
+	}
+	return nil
+}
@@ -17,6 +17,14 @@ func Migrate(version uint64, jsonString []byte) MapdSettings {
 			return MapdSettings{}
 		}
 		migratedSettings = v1Settings
+	case 2:
+		var v2Settings MapdSettings
+		err := json.Unmarshal(jsonString, &v2Settings)
+		if err != nil {
+			slog.Error("Error unmarshalling V2 settings: %v", "error", err)
+			return MapdSettings{}
+		}
+		migratedSettings = v2Settings
 	}
 
 	if version == 1 {
@@ -25,6 +33,12 @@ func Migrate(version uint64, jsonString []byte) MapdSettings {
 		version = 2
 	}
 
+	if version == 2 {
+		v2Settings := migratedSettings.(MapdSettings)
+		migratedSettings = v3(v2Settings)
+		version = 3
+	}
+
 	return migratedSettings.(MapdSettings)
 }
 
@@ -85,6 +99,18 @@ func v2(v1Settings V1Settings) MapdSettings {
 	return v2Settings
 }
 
+// v3 adds the map download settings
+func v3(v2Settings MapdSettings) MapdSettings {
+	v2Settings.SettingsVersion = 3
+	v2Settings.DownloadSettings = DownloadSettings{
+		PauseOnMetered: true,
+		MeteredParam:   "NetworkMetered",
+		Sources:        []string{MAP_DATA_URL},
+		Workers:        DOWNLOAD_WORKERS,
+	}
+	return v2Settings
+}
+
 type V1Settings struct {
 	SettingsVersion                     float32 `json:"settings_version"`
 	PressGasToAcceptSpeedLimit          bool    `json:"press_gas_to_accept_speed_limit"`
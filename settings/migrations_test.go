@@ -0,0 +1,33 @@
+package settings
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMigrateV2AddsDownloadSettings(t *testing.T) {
+	migrated := Migrate(2, []byte(`{"settings_version": 2, "speed_limit_control_enabled": true, "logger": {"log_level": "debug"}}`))
+	if migrated.SettingsVersion != SETTINGS_VERSION {
+		t.Errorf("expected settings version %d, got %v", SETTINGS_VERSION, migrated.SettingsVersion)
+	}
+	if !migrated.SpeedLimitControlEnabled || migrated.LogSettings.LogLevel != "debug" {
+		t.Errorf("v2 settings were not kept: %+v", migrated)
+	}
+	download := migrated.DownloadSettings
+	if !download.PauseOnMetered || download.MeteredParam != "NetworkMetered" || download.Workers != DOWNLOAD_WORKERS || !slices.Equal(download.Sources, []string{MAP_DATA_URL}) {
+		t.Errorf("expected default download settings, got %+v", download)
+	}
+}
+
+func TestMigrateV1ThroughV2(t *testing.T) {
+	migrated := Migrate(1, []byte(`{"settings_version": 1, "speed_limit_offset": 3, "log_level": "info"}`))
+	if migrated.SettingsVersion != SETTINGS_VERSION {
+		t.Errorf("expected settings version %d, got %v", SETTINGS_VERSION, migrated.SettingsVersion)
+	}
+	if migrated.SpeedLimitSettings.SpeedLimitOffset != 3 || migrated.LogSettings.LogLevel != "info" {
+		t.Errorf("v1 settings were not kept: %+v", migrated)
+	}
+	if !migrated.DownloadSettings.PauseOnMetered || len(migrated.DownloadSettings.Sources) == 0 {
+		t.Errorf("expected default download settings, got %+v", migrated.DownloadSettings)
+	}
+}
@@ -0,0 +1,88 @@
+package settings
+
+import (
+	"slices"
+	"strings"
+)
+
+// NearbyRegion is a download menu entry that contains the vehicle.
+type NearbyRegion struct {
+	Path           string // download path, e.g. nation.US
+	FullName       string
+	Files          int // group archives of the region
+	InstalledFiles int
+	area           float64
+}
+
+func (r NearbyRegion) Installed() bool {
+	return r.Files > 0 && r.InstalledFiles == r.Files
+}
+
+// contains checks whether a position lies inside the location. Bounding boxes
+// spanning more than half the globe usually wrap around the antimeridian, so
+// they only match through a boundary.
+func (l LocationData) contains(lat float64, lon float64) bool {
+	if len(l.Boundary) > 0 {
+		for _, ring := range l.Boundary {
+			if len(ring) >= 3 && pointInRing(ring, lon, lat) {
+				return true
+			}
+		}
+		return false
+	}
+	b := l.BoundingBox
+	if b.MaxLon-b.MinLon > 180 {
+		return false
+	}
+	return lat >= b.MinLat && lat <= b.MaxLat && lon >= b.MinLon && lon <= b.MaxLon
+}
+
+// area is a rough size used to order regions from the most specific one.
+func (l LocationData) area() float64 {
+	if len(l.Boundary) == 0 {
+		b := l.BoundingBox
+		return (b.MaxLat - b.MinLat) * (b.MaxLon - b.MinLon)
+	}
+	area := 0.0
+	for _, ring := range l.Boundary {
+		b := ringBounds(ring)
+		area += (b.MaxLat - b.MinLat) * (b.MaxLon - b.MinLon)
+	}
+	return area
+}
+
+// RegionsAt lists the download menu entries that contain a position along with
+// how much of each is installed. Smaller regions come first, so a state is
+// listed before its nation.
+func RegionsAt(menu DownloadMenu, inventory Inventory, lat float64, lon float64) []NearbyRegion {
+	regions := []NearbyRegion{}
+	for menuName, locations := range menu {
+		for name, location := range locations {
+			if !location.contains(lat, lon) {
+				continue
+			}
+			region := NearbyRegion{
+				Path:     menuName + "." + name,
+				FullName: location.FullName,
+				area:     location.area(),
+			}
+			for _, group := range location.groups() {
+				region.Files++
+				if _, ok := inventory.Groups[group.ArchiveName()]; ok {
+					region.InstalledFiles++
+				}
+			}
+			regions = append(regions, region)
+		}
+	}
+	slices.SortFunc(regions, func(a, b NearbyRegion) int {
+		if a.area != b.area {
+			if a.area < b.area {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	return regions
+}
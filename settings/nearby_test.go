@@ -0,0 +1,45 @@
+package settings
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRegionsAt(t *testing.T) {
+	var menu DownloadMenu
+	if err := json.Unmarshal(boundingBoxesJson, &menu); err != nil {
+		t.Fatal(err)
+	}
+	inventory := Inventory{Groups: map[string]*InstalledGroup{}}
+	ohio := menu["us_state"]["OH"].groups()
+	for _, group := range ohio {
+		inventory.Groups[group.ArchiveName()] = &InstalledGroup{}
+	}
+
+	regions := RegionsAt(menu, inventory, 39.96, -83.0)
+	if len(regions) != 2 || regions[0].Path != "us_state.OH" || regions[1].Path != "nation.US" {
+		t.Fatalf("expected Ohio before the United States, got %+v", regions)
+	}
+	if !regions[0].Installed() || regions[0].Files != len(ohio) {
+		t.Errorf("expected Ohio to be installed: %+v", regions[0])
+	}
+	if regions[1].Installed() || regions[1].InstalledFiles != len(ohio) {
+		t.Errorf("expected the United States to be partially installed: %+v", regions[1])
+	}
+
+	// the bounding box of Russia wraps around the globe and must not match
+	regions = RegionsAt(menu, inventory, 69.65, 18.95)
+	if len(regions) != 1 || regions[0].Path != "nation.NO" || regions[0].InstalledFiles != 0 {
+		t.Errorf("expected only an uninstalled Norway around Tromsø, got %+v", regions)
+	}
+
+	// Alaska across the antimeridian
+	regions = RegionsAt(menu, inventory, 52.9, 173.2)
+	if len(regions) != 1 || regions[0].Path != "us_state.AK" {
+		t.Errorf("expected Alaska on Attu island, got %+v", regions)
+	}
+
+	if regions = RegionsAt(menu, inventory, -40, -30); len(regions) != 0 {
+		t.Errorf("expected no region in the ocean, got %+v", regions)
+	}
+}
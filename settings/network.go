@@ -0,0 +1,103 @@
+package settings
+
+import (
+	"context"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"pfeifer.dev/mapd/params"
+)
+
+// NetworkPolicy decides whether map downloads may use the network. The
+// connection counts as metered when a fork reports it through MapdIn, when the
+// configured param (openpilot's NetworkMetered by default) is set, or when the
+// configured command exits successfully.
+type NetworkPolicy struct {
+	lock           sync.Mutex
+	pauseOnMetered bool
+	meteredParam   string
+	meteredCommand string
+	inputMetered   bool
+	metered        bool
+	lastCheck      time.Time
+	checkInterval  time.Duration
+}
+
+var networkPolicy = newNetworkPolicy()
+
+func newNetworkPolicy() *NetworkPolicy {
+	return &NetworkPolicy{
+		pauseOnMetered: true,
+		meteredParam:   "NetworkMetered",
+		checkInterval:  METERED_CHECK_INTERVAL,
+	}
+}
+
+func (p *NetworkPolicy) configure(settings DownloadSettings) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.pauseOnMetered == settings.PauseOnMetered && p.meteredParam == settings.MeteredParam && p.meteredCommand == settings.MeteredCommand {
+		return
+	}
+	p.pauseOnMetered = settings.PauseOnMetered
+	p.meteredParam = settings.MeteredParam
+	p.meteredCommand = settings.MeteredCommand
+	p.lastCheck = time.Time{}
+}
+
+func (p *NetworkPolicy) setInputMetered(metered bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.inputMetered = metered
+}
+
+// Paused reports whether downloads should wait instead of transferring data.
+func (p *NetworkPolicy) Paused() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !p.pauseOnMetered {
+		return false
+	}
+	if p.inputMetered {
+		return true
+	}
+	if time.Since(p.lastCheck) >= p.checkInterval {
+		p.metered = p.paramMetered() || p.commandMetered()
+		p.lastCheck = time.Now()
+	}
+	return p.metered
+}
+
+// openpilot writes bool params as "1" or "0"
+func (p *NetworkPolicy) paramMetered() bool {
+	if p.meteredParam == "" {
+		return false
+	}
+	data, err := params.GetParam(params.ParamPath(p.meteredParam))
+	if err != nil {
+		return false
+	}
+	value := strings.ToLower(strings.TrimSpace(string(data)))
+	return value == "1" || value == "true"
+}
+
+func (p *NetworkPolicy) commandMetered() bool {
+	if p.meteredCommand == "" {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), METERED_COMMAND_TIMEOUT)
+	defer cancel()
+	err := exec.CommandContext(ctx, "sh", "-c", p.meteredCommand).Run()
+	if err == nil {
+		return true
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		slog.Warn("could not run metered connection command", "error", err, "command", p.meteredCommand)
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package settings
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pfeifer.dev/mapd/params"
+)
+
+func TestNetworkPolicySignals(t *testing.T) {
+	paramsPath := params.ParamsPath
+	params.ParamsPath = t.TempDir()
+	defer func() { params.ParamsPath = paramsPath }()
+
+	p := newNetworkPolicy()
+	p.checkInterval = 0
+	if p.Paused() {
+		t.Fatal("connection should not be metered without a signal")
+	}
+
+	err := os.WriteFile(filepath.Join(params.ParamsPath, "NetworkMetered"), []byte("1"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Paused() {
+		t.Error("expected the NetworkMetered param to pause downloads")
+	}
+	p.configure(DownloadSettings{PauseOnMetered: false, MeteredParam: "NetworkMetered"})
+	if p.Paused() {
+		t.Error("downloads should not pause when pause_on_metered is disabled")
+	}
+
+	p.configure(DownloadSettings{PauseOnMetered: true, MeteredCommand: "exit 0"})
+	if !p.Paused() {
+		t.Error("expected a successful metered command to pause downloads")
+	}
+	p.configure(DownloadSettings{PauseOnMetered: true, MeteredCommand: "exit 1"})
+	if p.Paused() {
+		t.Error("expected a failing metered command to allow downloads")
+	}
+
+	p.setInputMetered(true)
+	if !p.Paused() {
+		t.Error("expected the MapdIn flag to pause downloads")
+	}
+}
+
+func TestDownloadWaitsWhileMetered(t *testing.T) {
+	archive := testArchive(t, map[string]string{"offline/0/0/tile": "data"})
+	flaky := &flakyServer{
+		archives: map[string][]byte{"/offline/0/0.tar.gz": archive},
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	d.network = &NetworkPolicy{pauseOnMetered: true}
+	d.network.setInputMetered(true)
+	d.fetcher.PausePoll = time.Millisecond
+
+	done := make(chan bool)
+	go func() {
+		_, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}, "test.location")
+		done <- canceled
+	}()
+
+	deadline := time.After(5 * time.Second)
+	for paused := false; !paused; {
+		select {
+		case progress := <-d.progressChan:
+			paused = progress.Paused
+		case <-deadline:
+			t.Fatal("download progress never reported the pause")
+		}
+	}
+	flaky.mu.Lock()
+	requests := len(flaky.requests)
+	flaky.mu.Unlock()
+	if requests != 0 {
+		t.Fatalf("no data should be transferred while metered, got %d requests", requests)
+	}
+
+	d.network.setInputMetered(false)
+	select {
+	case canceled := <-done:
+		if canceled {
+			t.Fatal("download should not be canceled by a pause")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("download did not resume")
+	}
+	if d.progress.Paused || len(d.progress.FailedFiles) != 0 {
+		t.Errorf("unexpected progress after resuming: %+v", d.progress)
+	}
+	if data, _ := os.ReadFile(filepath.Join(d.basePath, "offline/0/0/tile")); string(data) != "data" {
+		t.Error("tiles were not installed after resuming")
+	}
+}
+
+func TestFetchResumesAfterPauseMidTransfer(t *testing.T) {
+	data := []byte(strings.Repeat("resumable data ", 50000))
+	flaky := &flakyServer{
+		archives: map[string][]byte{"/file": data},
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	// pause after the transfer started, then allow it again
+	calls := 0
+	f := NewFileFetcher()
+	f.Client = server.Client()
+	f.PausePoll = time.Millisecond
+	f.Paused = func() bool {
+		calls++
+		return calls >= 3 && calls < 6
+	}
+
+	path := filepath.Join(t.TempDir(), "file")
+	attempts, err := f.Fetch(server.URL+"/file", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 1 {
+		t.Errorf("a pause should not count as a failed attempt, used %d attempts", attempts)
+	}
+	if len(flaky.ranges) == 0 {
+		t.Error("expected the transfer to resume with a range request")
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, data) {
+		t.Error("resumed file does not match the original")
+	}
+}
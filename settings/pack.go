@@ -0,0 +1,90 @@
+package settings
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// A pack holds every tile of a group in one file. The header is the pack magic
+// and the number of tiles, followed by the box, offset and length of every
+// tile. The header is read here so the loader and the download checks agree
+// on what a valid pack is.
+const (
+	PACK_MAGIC       = "MAPDPAK1" // first bytes of a pack
+	PACK_HEADER_SIZE = 16         // magic and tile count
+	PACK_ENTRY_SIZE  = 48         // box, offset and length of a tile
+)
+
+// PackEntry is where a tile is in a pack. The box is the minimum latitude,
+// minimum longitude, maximum latitude and maximum longitude of the tile.
+type PackEntry struct {
+	Box    [4]float64
+	Offset uint64
+	Length uint64
+}
+
+// PackHeaderSize is the size of the header of a pack with count tiles.
+func PackHeaderSize(count int) int {
+	return PACK_HEADER_SIZE + count*PACK_ENTRY_SIZE
+}
+
+// EncodePackHeader encodes the header of a pack holding entries.
+func EncodePackHeader(entries []PackEntry) []byte {
+	buf := make([]byte, PackHeaderSize(len(entries)))
+	copy(buf, PACK_MAGIC)
+	binary.LittleEndian.PutUint32(buf[len(PACK_MAGIC):], uint32(len(entries)))
+	for i, e := range entries {
+		raw := buf[PackHeaderSize(i):]
+		for j, value := range e.Box {
+			binary.LittleEndian.PutUint64(raw[8*j:], math.Float64bits(value))
+		}
+		binary.LittleEndian.PutUint64(raw[32:], e.Offset)
+		binary.LittleEndian.PutUint64(raw[40:], e.Length)
+	}
+	return buf
+}
+
+// ReadPackHeader reads the tile entries of a pack of size bytes and checks
+// every tile is inside of it. A pack that is not valid fails with ErrIntegrity.
+func ReadPackHeader(r io.ReaderAt, size int64) ([]PackEntry, error) {
+	header := make([]byte, PACK_HEADER_SIZE)
+	err := readPackAt(r, header, 0)
+	if err != nil || string(header[:len(PACK_MAGIC)]) != PACK_MAGIC {
+		return nil, errors.Wrap(ErrIntegrity, "pack has an unknown format")
+	}
+	count := int64(binary.LittleEndian.Uint32(header[len(PACK_MAGIC):]))
+	if size < PACK_HEADER_SIZE+count*PACK_ENTRY_SIZE {
+		return nil, errors.Wrap(ErrIntegrity, "pack header is truncated")
+	}
+	raw := make([]byte, count*PACK_ENTRY_SIZE)
+	err = readPackAt(r, raw, PACK_HEADER_SIZE)
+	if err != nil {
+		return nil, errors.Wrap(ErrIntegrity, "could not read pack header")
+	}
+	entries := make([]PackEntry, count)
+	for i := range entries {
+		e := raw[i*PACK_ENTRY_SIZE:]
+		for j := range entries[i].Box {
+			entries[i].Box[j] = math.Float64frombits(binary.LittleEndian.Uint64(e[8*j:]))
+		}
+		entries[i].Offset = binary.LittleEndian.Uint64(e[32:])
+		entries[i].Length = binary.LittleEndian.Uint64(e[40:])
+		if entries[i].Offset > uint64(size) || entries[i].Length > uint64(size)-entries[i].Offset {
+			return nil, errors.Wrap(ErrIntegrity, "pack tile is outside of the pack")
+		}
+	}
+	return entries, nil
+}
+
+// readPackAt fills buf, a read that ends at the end of the pack may tell so
+// with io.EOF.
+func readPackAt(r io.ReaderAt, buf []byte, offset int64) error {
+	n, err := r.ReadAt(buf, offset)
+	if n == len(buf) {
+		return nil
+	}
+	return err
+}
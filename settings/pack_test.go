@@ -0,0 +1,42 @@
+package settings
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestReadPackHeader(t *testing.T) {
+	entries := []PackEntry{
+		{Box: [4]float64{40, -84, 40.25, -83.75}, Offset: 112, Length: 8},
+		{Box: [4]float64{40.25, -84, 40.5, -83.75}, Offset: 120, Length: 0},
+	}
+	data := append(EncodePackHeader(entries), make([]byte, 8)...)
+	read, err := ReadPackHeader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != len(entries) {
+		t.Fatalf("expected %d entries, got %d", len(entries), len(read))
+	}
+	for i := range entries {
+		if read[i] != entries[i] {
+			t.Errorf("expected entry %d to be %v, got %v", i, entries[i], read[i])
+		}
+	}
+
+	empty := EncodePackHeader(nil)
+	if _, err := ReadPackHeader(bytes.NewReader(empty), int64(len(empty))); err != nil {
+		t.Errorf("expected an empty pack to pass, got %v", err)
+	}
+	if _, err := ReadPackHeader(bytes.NewReader(data[:len(data)-1]), int64(len(data)-1)); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("expected a tile past the end of the pack to fail, got %v", err)
+	}
+	if _, err := ReadPackHeader(bytes.NewReader(data[:PACK_HEADER_SIZE]), PACK_HEADER_SIZE); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("expected a pack missing its tile entries to fail, got %v", err)
+	}
+	if _, err := ReadPackHeader(bytes.NewReader([]byte("MAPD")), 4); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("expected a short pack to fail, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package settings
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// transferStats tracks what is needed to turn the bytes reported by the
+// fetchers into throughput and an estimate of the time left.
+type transferStats struct {
+	files          map[string]*fileTransfer // files being fetched by name
+	completedBytes int64                    // size of the group archives finished so far
+	completedFiles int
+	windowStart    time.Time
+	windowBytes    int64
+	lastSend       time.Time
+}
+
+type fileTransfer struct {
+	received int64
+	size     int64 // 0 when unknown
+}
+
+// publishProgress sends a copy of the progress that does not share any state
+// with the download. The lock must be held.
+func (d *download) publishProgress() {
+	progress := d.progress
+	progress.LocationDetails = make(map[string]*DownloadLocationDetail, len(d.progress.LocationDetails))
+	for location, details := range d.progress.LocationDetails {
+		copied := *details
+		progress.LocationDetails[location] = &copied
+	}
+	progress.FailedFiles = append([]DownloadFailure{}, d.progress.FailedFiles...)
+	select { // nonblocking update of progress
+	case d.progressChan <- progress:
+	default:
+	}
+}
+
+func (d *download) sendProgress() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.publishProgress()
+}
+
+// startFile makes filename the file shown as being processed.
+func (d *download) startFile(filename string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.progress.CurrentFile = filename
+	d.progress.CurrentFileReceived = 0
+	d.progress.CurrentFileSize = 0
+}
+
+// finishFile forgets the transfers of a group archive and its manifest. The
+// archive size feeds the estimate for the files that are left.
+func (d *download) finishFile(filename string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	t, ok := d.stats.files[filename]
+	if ok && t.received > 0 && (t.size == 0 || t.received >= t.size) {
+		d.stats.completedBytes += t.received
+		d.stats.completedFiles++
+	}
+	delete(d.stats.files, filename)
+	delete(d.stats.files, GroupManifestName(filename))
+}
+
+// finishFiles clears the current file once nothing is processed anymore.
+func (d *download) finishFiles() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.progress.CurrentFile = ""
+	d.progress.CurrentFileReceived = 0
+	d.progress.CurrentFileSize = 0
+	d.resetThroughput()
+}
+
+// beginTransfer starts counting a fetch of filename. Data left in outputName
+// by an interrupted download is resumed and not counted again.
+func (d *download) beginTransfer(filename string, outputName string) {
+	t := &fileTransfer{}
+	if info, err := os.Stat(outputName); err == nil {
+		t.received = info.Size()
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.stats.files[filename] = t
+}
+
+// fileProgress is called by the fetchers as data arrives.
+func (d *download) fileProgress(filename string, received int64, total int64) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	t, ok := d.stats.files[filename]
+	if !ok {
+		t = &fileTransfer{}
+		d.stats.files[filename] = t
+	}
+	if received < t.received {
+		// the server sent the file from the start again
+		t.received = 0
+	}
+	delta := received - t.received
+	t.received = received
+	if total > 0 {
+		t.size = total
+	}
+	d.progress.DownloadedBytes += delta
+	if filename == d.progress.CurrentFile {
+		d.progress.CurrentFileReceived = t.received
+		d.progress.CurrentFileSize = t.size
+	}
+	d.updateThroughput(delta)
+
+	if time.Since(d.stats.lastSend) >= DOWNLOAD_PROGRESS_INTERVAL {
+		d.stats.lastSend = time.Now()
+		d.updateEta()
+		d.publishProgress()
+	}
+}
+
+// updateThroughput smooths the transfer rate measured over short windows. The
+// lock must be held.
+func (d *download) updateThroughput(received int64) {
+	now := time.Now()
+	if d.stats.windowStart.IsZero() {
+		d.stats.windowStart = now
+	}
+	d.stats.windowBytes += received
+	elapsed := now.Sub(d.stats.windowStart)
+	if elapsed < DOWNLOAD_THROUGHPUT_WINDOW {
+		return
+	}
+	rate := float64(d.stats.windowBytes) / elapsed.Seconds()
+	if d.progress.BytesPerSecond > 0 {
+		rate = d.progress.BytesPerSecond*(1-DOWNLOAD_THROUGHPUT_WEIGHT) + rate*DOWNLOAD_THROUGHPUT_WEIGHT
+	}
+	d.progress.BytesPerSecond = rate
+	d.stats.windowStart = now
+	d.stats.windowBytes = 0
+}
+
+// resetThroughput drops the rate while no data is transferred, e.g. while
+// the download is paused. The lock must be held.
+func (d *download) resetThroughput() {
+	d.progress.BytesPerSecond = 0
+	d.progress.EtaSeconds = -1
+	d.stats.windowStart = time.Time{}
+	d.stats.windowBytes = 0
+}
+
+// updateEta estimates the time left from the rest of the archives in flight
+// and the average size of the archives finished so far. The lock must be held.
+func (d *download) updateEta() {
+	d.progress.EtaSeconds = -1
+	if d.progress.BytesPerSecond <= 0 {
+		return
+	}
+	remaining, largest := int64(0), int64(0)
+	inFlight := 0
+	for filename, t := range d.stats.files {
+		if !strings.HasSuffix(filename, ".tar.gz") {
+			continue
+		}
+		inFlight++
+		remaining += max(t.size-t.received, 0)
+		largest = max(largest, t.size)
+	}
+	remainingFiles := int64(max(d.progress.TotalFiles-d.progress.DownloadedFiles-len(d.progress.FailedFiles)-inFlight, 0))
+	if remainingFiles > 0 {
+		average := largest
+		if d.stats.completedFiles > 0 {
+			average = d.stats.completedBytes / int64(d.stats.completedFiles)
+		}
+		if average <= 0 {
+			return
+		}
+		remaining += remainingFiles * average
+	}
+	d.progress.EtaSeconds = int(float64(remaining) / d.progress.BytesPerSecond)
+}
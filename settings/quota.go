@@ -0,0 +1,255 @@
+package settings
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"pfeifer.dev/mapd/params"
+)
+
+var ErrQuotaExceeded = errors.New("disk quota exceeded")
+
+// DiskQuota keeps the offline map data under a size budget by evicting the
+// groups that were least recently driven in. The group the vehicle is in and
+// its neighbours are never evicted.
+type DiskQuota struct {
+	lock       sync.Mutex
+	limit      int64 // bytes, 0 is unlimited
+	current    groupCell
+	hasCurrent bool
+	lastAccess time.Time
+	evictions  []Eviction
+}
+
+type Eviction struct {
+	File      string
+	Size      int64
+	EvictedAt time.Time
+}
+
+var diskQuota = &DiskQuota{}
+
+func (q *DiskQuota) setLimit(megabytes float32) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.limit = int64(max(megabytes, 0) * 1024 * 1024)
+}
+
+// Limit is the disk budget for offline map data in bytes, 0 when unlimited.
+func (q *DiskQuota) Limit() int64 {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.limit
+}
+
+// setCurrent remembers the group mapd is loading tiles from and reports
+// whether its last access time is due to be written.
+func (q *DiskQuota) setCurrent(group groupCell) (touch bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.hasCurrent && q.current == group && time.Since(q.lastAccess) < GROUP_ACCESS_INTERVAL {
+		return false
+	}
+	q.current = group
+	q.hasCurrent = true
+	q.lastAccess = time.Now()
+	return true
+}
+
+// protectedGroups lists the archives of the current group and its eight
+// neighbours. Before any tiles were loaded the last gps position is used.
+func (q *DiskQuota) protectedGroups() map[string]bool {
+	q.lock.Lock()
+	current, ok := q.current, q.hasCurrent
+	q.lock.Unlock()
+	if !ok {
+		lat, lon, err := readLastGpsPosition()
+		if err != nil {
+			return map[string]bool{}
+		}
+		current = groupForPosition(lat, lon)
+	}
+
+	protected := map[string]bool{}
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			group := groupCell{
+				Lat: current.Lat + i*GROUP_AREA_BOX_DEGREES,
+				Lon: normalizeGroupLongitude(current.Lon + j*GROUP_AREA_BOX_DEGREES),
+			}
+			protected[group.ArchiveName()] = true
+		}
+	}
+	return protected
+}
+
+func (q *DiskQuota) recordEviction(eviction Eviction) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.evictions = append(q.evictions, eviction)
+	if len(q.evictions) > MAX_RECENT_EVICTIONS {
+		q.evictions = q.evictions[len(q.evictions)-MAX_RECENT_EVICTIONS:]
+	}
+}
+
+// Evictions returns the most recent evictions, oldest first.
+func (q *DiskQuota) Evictions() []Eviction {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return slices.Clone(q.evictions)
+}
+
+// OfflineDiskQuota is the quota applied to downloads.
+func OfflineDiskQuota() *DiskQuota {
+	return diskQuota
+}
+
+// RecordMapAccess notes that mapd loaded tiles around a position so the group
+// is kept over ones that were not driven in for longer.
+func RecordMapAccess(lat float64, lon float64) {
+	group := groupForPosition(lat, lon)
+	if !diskQuota.setCurrent(group) {
+		return
+	}
+	go func() {
+		err := touchGroup(params.GetBaseOpPath(), group.ArchiveName())
+		if err != nil {
+			slog.Warn("could not record map data access", "error", err, "file", group.ArchiveName())
+		}
+	}()
+}
+
+func touchGroup(basePath string, filename string) error {
+	return UpdateInventory(basePath, func(inventory *Inventory) {
+		if group, ok := inventory.Groups[filename]; ok {
+			group.LastAccess = time.Now()
+		}
+	})
+}
+
+// offlineGroups lists the archive names of the group directories on disk,
+// including ones that were installed without the inventory.
+func offlineGroups(basePath string) (groups []string, err error) {
+	dirs, err := filepath.Glob(filepath.Join(basePath, "offline", "*", "*"))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list offline map data")
+	}
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(basePath, dir)
+		if err != nil {
+			continue
+		}
+		groups = append(groups, filepath.ToSlash(rel)+".tar.gz")
+	}
+	return groups, nil
+}
+
+// installWithinQuota makes room for a staged group and installs it. The
+// inventory stays locked in between, so a group installed by another worker
+// can not take the room that was made.
+func (d *download) installWithinQuota(filename string, stagedGroup string, size int64) error {
+	inventoryLock.Lock()
+	defer inventoryLock.Unlock()
+	err := d.makeRoom(filename, size)
+	if err != nil {
+		return err
+	}
+	return installGroup(stagedGroup, groupDirectory(d.basePath, filename))
+}
+
+// makeRoom evicts the least recently used groups until a group of the given
+// size fits under the disk quota. The group being installed does not count
+// against the quota since its tiles are replaced. The caller holds the
+// inventory lock.
+func (d *download) makeRoom(filename string, needed int64) error {
+	limit := d.quota.Limit()
+	if limit <= 0 {
+		return nil
+	}
+
+	inventory, err := readInventory(d.basePath)
+	if err != nil {
+		return err
+	}
+	groups, err := offlineGroups(d.basePath)
+	if err != nil {
+		return err
+	}
+
+	type candidate struct {
+		filename string
+		size     int64
+		lastUsed time.Time
+	}
+	protected := d.quota.protectedGroups()
+	candidates := []candidate{}
+	used, evictable := int64(0), int64(0)
+	for _, group := range groups {
+		if group == filename {
+			continue
+		}
+		c := candidate{filename: group}
+		installed, ok := inventory.Groups[group]
+		if ok {
+			c.size = installed.Size
+			c.lastUsed = installed.lastUsed()
+		}
+		if !ok || c.size == 0 {
+			c.size, err = directorySize(groupDirectory(d.basePath, group))
+			if err != nil {
+				slog.Warn("could not measure installed map data", "error", err, "file", group)
+			}
+		}
+		used += c.size
+		if !protected[group] {
+			candidates = append(candidates, c)
+			evictable += c.size
+		}
+	}
+	if used+needed <= limit {
+		return nil
+	}
+	// don't throw away map data when the group would not fit anyway
+	if used-evictable+needed > limit {
+		return errors.Wrapf(ErrQuotaExceeded, "%s needs %d bytes with %d of %d bytes in use by groups that can not be evicted", filename, needed, used-evictable, limit)
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return a.lastUsed.Compare(b.lastUsed)
+	})
+	evicted := false
+	for _, c := range candidates {
+		if used+needed <= limit {
+			break
+		}
+		err := removeGroupFiles(d.basePath, c.filename)
+		if err != nil {
+			slog.Warn("could not evict map data", "error", err, "file", c.filename)
+			continue
+		}
+		inventory.removeGroup(c.filename)
+		used -= c.size
+		evicted = true
+		d.quota.recordEviction(Eviction{File: c.filename, Size: c.size, EvictedAt: time.Now()})
+		slog.Info("evicted map data to stay under the disk quota", "file", c.filename, "size", c.size)
+	}
+	if evicted {
+		err = writeInventory(d.basePath, inventory)
+		if err != nil {
+			slog.Warn("could not record evicted map data", "error", err)
+		}
+	}
+	if used+needed > limit {
+		return errors.Wrapf(ErrQuotaExceeded, "%s needs %d bytes with %d of %d bytes in use", filename, needed, used, limit)
+	}
+	return nil
+}
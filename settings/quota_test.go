@@ -0,0 +1,120 @@
+package settings
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestDownloadEvictsLeastRecentlyUsedGroups(t *testing.T) {
+	tile := strings.Repeat("x", 100)
+	archive := testArchive(t, map[string]string{"offline/40/40/tile": tile})
+	flaky := &flakyServer{
+		archives: map[string][]byte{"/offline/40/40.tar.gz": archive},
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	d.quota = &DiskQuota{limit: 450, current: groupCell{Lat: 30, Lon: 30}, hasCurrent: true}
+
+	// the vehicle is in 30/30 and 32/32 is its neighbour, both are older than
+	// 0/0 and 10/10 but must be kept
+	lastAccess := map[string]time.Time{
+		"offline/0/0.tar.gz":   time.Now().Add(-48 * time.Hour),
+		"offline/10/10.tar.gz": time.Now().Add(-time.Hour),
+		"offline/30/30.tar.gz": time.Now().Add(-72 * time.Hour),
+		"offline/32/32.tar.gz": time.Now().Add(-72 * time.Hour),
+	}
+	err := UpdateInventory(d.basePath, func(inventory *Inventory) {
+		for filename, access := range lastAccess {
+			dir := groupDirectory(d.basePath, filename)
+			if err := os.MkdirAll(dir, 0o775); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "tile"), []byte(tile), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			inventory.recordGroup("test.location", filename, nil, int64(len(tile)))
+			inventory.Groups[filename].InstalledAt = access
+			inventory.Groups[filename].LastAccess = access
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.downloadBounds(Bounds{MinLat: 40, MinLon: 40, MaxLat: 41, MaxLon: 41}, "test.location")
+	if len(d.progress.FailedFiles) != 0 {
+		t.Fatalf("download failed: %+v", d.progress.FailedFiles)
+	}
+	for filename, kept := range map[string]bool{
+		"offline/0/0.tar.gz":   false,
+		"offline/10/10.tar.gz": true,
+		"offline/30/30.tar.gz": true,
+		"offline/32/32.tar.gz": true,
+		"offline/40/40.tar.gz": true,
+	} {
+		_, err := os.Stat(groupDirectory(d.basePath, filename))
+		if kept != (err == nil) {
+			t.Errorf("%s: expected kept=%t, stat error %v", filename, kept, err)
+		}
+	}
+	inventory, err := LoadInventory(d.basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := inventory.Groups["offline/0/0.tar.gz"]; ok || len(inventory.Regions["test.location"].Groups) != 4 {
+		t.Errorf("evicted group is still in the inventory: %+v", inventory.Regions["test.location"])
+	}
+	evictions := d.quota.Evictions()
+	if len(evictions) != 1 || evictions[0].File != "offline/0/0.tar.gz" || evictions[0].Size != int64(len(tile)) {
+		t.Errorf("unexpected evictions: %+v", evictions)
+	}
+
+	// the protected groups alone leave no room for this one
+	err = d.makeRoom("offline/50/50.tar.gz", 400)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected the quota to be exceeded, got %v", err)
+	}
+	if len(d.quota.Evictions()) != 1 {
+		t.Error("groups should not be evicted when the new group can not fit anyway")
+	}
+}
+
+func TestConcurrentInstallsStayUnderQuota(t *testing.T) {
+	tile := strings.Repeat("x", 200)
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz": testArchive(t, map[string]string{"offline/0/0/tile": tile}),
+			"/offline/0/2.tar.gz": testArchive(t, map[string]string{"offline/0/2/tile": tile}),
+		},
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	d.workers = 2
+	d.quota = &DiskQuota{limit: 300}
+	d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 3}, "test.location")
+	if len(d.progress.FailedFiles) != 0 {
+		t.Fatalf("download failed: %+v", d.progress.FailedFiles)
+	}
+	size, err := directorySize(filepath.Join(d.basePath, "offline"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size > d.quota.Limit() {
+		t.Errorf("expected at most %d bytes of map data, got %d", d.quota.Limit(), size)
+	}
+	if len(d.quota.Evictions()) != 1 {
+		t.Errorf("expected the first group to be evicted for the second, got %+v", d.quota.Evictions())
+	}
+}
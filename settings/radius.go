@@ -0,0 +1,143 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"pfeifer.dev/mapd/params"
+)
+
+// great circle distance in meters
+func haversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	latDiff := (lat2 - lat1) * TO_RADIANS
+	lonDiff := (lon2 - lon1) * TO_RADIANS
+	a := math.Pow(math.Sin(latDiff/2), 2) + math.Cos(lat1*TO_RADIANS)*math.Cos(lat2*TO_RADIANS)*math.Pow(math.Sin(lonDiff/2), 2)
+	return R * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// absolute difference between two longitudes taking the antimeridian into account
+func longitudeDelta(a, b float64) float64 {
+	delta := math.Mod(math.Abs(a-b), 360)
+	return math.Min(delta, 360-delta)
+}
+
+func normalizeGroupLongitude(lon int) int {
+	return ((lon+180)%360+360)%360 - 180
+}
+
+// distance from a point to the nearest edge of a group cell, zero when inside
+func distanceToGroup(lat, lon float64, group groupCell) float64 {
+	west := float64(group.Lon)
+	east := west + GROUP_AREA_BOX_DEGREES
+	nearestLon := lon
+	if longitudeDelta(lon, west)+longitudeDelta(lon, east) > GROUP_AREA_BOX_DEGREES+1e-9 {
+		if longitudeDelta(lon, west) < longitudeDelta(lon, east) {
+			nearestLon = west
+		} else {
+			nearestLon = east
+		}
+	}
+	nearestLat := math.Max(float64(group.Lat), math.Min(lat, float64(group.Lat+GROUP_AREA_BOX_DEGREES)))
+	return haversineDistance(lat, lon, nearestLat, nearestLon)
+}
+
+// groupsForRadius returns the group archives that cover a circle of radiusKm
+// around the given position.
+func groupsForRadius(lat, lon, radiusKm float64) (groups []groupCell) {
+	radius := radiusKm * 1000
+	latDelta := radius / R * TO_DEGREES
+	minLat := math.Max(lat-latDelta, -90)
+	maxLat := math.Min(lat+latDelta, 90)
+
+	// meridians converge toward the poles so widen the longitude search using
+	// the latitude furthest from the equator
+	minLon, maxLon := -180.0, 180.0
+	widestLat := math.Max(math.Abs(minLat), math.Abs(maxLat))
+	if widestLat < 89 {
+		lonDelta := latDelta / math.Cos(widestLat*TO_RADIANS)
+		if lonDelta < 180 {
+			minLon = lon - lonDelta
+			maxLon = lon + lonDelta
+		}
+	}
+
+	seen := map[groupCell]bool{}
+	startLat := int(math.Floor(minLat/GROUP_AREA_BOX_DEGREES)) * GROUP_AREA_BOX_DEGREES
+	startLon := int(math.Floor(minLon/GROUP_AREA_BOX_DEGREES)) * GROUP_AREA_BOX_DEGREES
+	for i := startLat; float64(i) < maxLat; i += GROUP_AREA_BOX_DEGREES {
+		for j := startLon; float64(j) < maxLon; j += GROUP_AREA_BOX_DEGREES {
+			group := groupCell{Lat: i, Lon: normalizeGroupLongitude(j)}
+			if seen[group] || distanceToGroup(lat, lon, group) > radius {
+				continue
+			}
+			seen[group] = true
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+func RadiusLocationName(lat, lon, radiusKm float64) string {
+	return fmt.Sprintf("radius:%gkm@%.4f,%.4f", radiusKm, lat, lon)
+}
+
+// DownloadRadius downloads the group archives within radiusKm of a position.
+func DownloadRadius(lat, lon, radiusKm float64, progressChan chan DownloadProgress, cancelChan chan bool) {
+	name := RadiusLocationName(lat, lon, radiusKm)
+	groups := groupsForRadius(lat, lon, radiusKm)
+	slog.Info("download radius", "latitude", lat, "longitude", lon, "radius_km", radiusKm, "files", len(groups))
+	d := newDownload(DownloadProgress{
+		LocationsToDownload: []string{name},
+		TotalFiles:          len(groups),
+		LocationDetails:     map[string]*DownloadLocationDetail{name: {TotalFiles: len(groups)}},
+		Active:              true,
+	}, progressChan, cancelChan)
+
+	d.progress.Canceled = d.downloadGroups(groups, name)
+	d.progress.Active = false
+	d.finishFiles()
+	d.sendProgress()
+}
+
+type lastGpsPosition struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// ParseDownloadCenter parses an explicit "lat,lon" download center.
+func ParseDownloadCenter(center string) (lat float64, lon float64, err error) {
+	latStr, lonStr, found := strings.Cut(center, ",")
+	if !found {
+		return 0, 0, errors.Errorf("invalid position %q, expected lat,lon", center)
+	}
+	lat, err = strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid latitude")
+	}
+	lon, err = strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid longitude")
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return 0, 0, errors.Errorf("position %q is out of range", center)
+	}
+	return lat, lon, nil
+}
+
+func readLastGpsPosition() (lat float64, lon float64, err error) {
+	data, err := params.GetParam(params.LAST_GPS_POSITION)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "could not read last gps position")
+	}
+	var position lastGpsPosition
+	err = json.Unmarshal(data, &position)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "could not parse last gps position")
+	}
+	return position.Latitude, position.Longitude, nil
+}
@@ -0,0 +1,65 @@
+package settings
+
+import (
+	"slices"
+	"testing"
+)
+
+func sortedGroups(groups []groupCell) []groupCell {
+	slices.SortFunc(groups, func(a, b groupCell) int {
+		if a.Lat != b.Lat {
+			return a.Lat - b.Lat
+		}
+		return a.Lon - b.Lon
+	})
+	return groups
+}
+
+func TestGroupsForRadius(t *testing.T) {
+	cases := []struct {
+		name     string
+		lat, lon float64
+		radiusKm float64
+		expected []groupCell
+	}{
+		{"inside a single group", 41, -83, 10, []groupCell{{40, -84}}},
+		{"near a group corner", 40.01, -83.99, 5, []groupCell{{38, -86}, {38, -84}, {40, -86}, {40, -84}}},
+		{"across the antimeridian", 0.5, 179.9, 30, []groupCell{{0, -180}, {0, 178}}},
+		{"negative coordinates", -33.9, -70.6, 5, []groupCell{{-34, -72}}},
+	}
+	for _, c := range cases {
+		groups := sortedGroups(groupsForRadius(c.lat, c.lon, c.radiusKm))
+		if !slices.Equal(groups, c.expected) {
+			t.Errorf("%s: groupsForRadius(%f, %f, %f) = %v, expected %v", c.name, c.lat, c.lon, c.radiusKm, groups, c.expected)
+		}
+	}
+}
+
+func TestGroupsForRadiusStaysInsideCircle(t *testing.T) {
+	lat, lon, radiusKm := 45.3, 7.1, 400.0
+	groups := groupsForRadius(lat, lon, radiusKm)
+	bbox := groupsForBounds(Bounds{MinLat: lat - 4, MinLon: lon - 6, MaxLat: lat + 4, MaxLon: lon + 6})
+	if len(groups) == 0 || len(groups) >= len(bbox) {
+		t.Fatalf("expected the circle to select fewer groups than its bounding box, got %d of %d", len(groups), len(bbox))
+	}
+	for _, group := range groups {
+		if d := distanceToGroup(lat, lon, group); d > radiusKm*1000 {
+			t.Errorf("group %v is %fm away", group, d)
+		}
+	}
+	if !slices.Contains(groups, groupCell{44, 6}) {
+		t.Error("expected the group containing the center")
+	}
+}
+
+func TestParseDownloadCenter(t *testing.T) {
+	lat, lon, err := ParseDownloadCenter(" 40.5, -83.25 ")
+	if err != nil || lat != 40.5 || lon != -83.25 {
+		t.Errorf("ParseDownloadCenter() = %f, %f, %v", lat, lon, err)
+	}
+	for _, invalid := range []string{"", "40.5", "north,south", "91,0", "0,-181"} {
+		if _, _, err := ParseDownloadCenter(invalid); err == nil {
+			t.Errorf("ParseDownloadCenter(%q) should fail", invalid)
+		}
+	}
+}
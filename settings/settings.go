@@ -14,12 +14,13 @@ import (
 	"github.com/Jeffail/gabs/v2"
 )
 
-const SETTINGS_VERSION = 2 // Used for migrations
+const SETTINGS_VERSION = 3 // Used for migrations
 
 var Settings = MapdSettings{
 	SettingsVersion:  SETTINGS_VERSION,
 	downloadProgress: make(chan DownloadProgress, 1),
 	cancelDownload:   make(chan bool, 1),
+	liveDownloads:    newLiveDownloader(),
 }
 
 type SpeedLimitPriority string
@@ -35,6 +36,10 @@ type MapdSettings struct {
 	downloadProgress                    chan DownloadProgress
 	cancelDownload                      chan bool
 	downloadActive                      bool
+	liveDownloads                       *liveDownloader
+	lastLatitude                        float64
+	lastLongitude                       float64
+	hasLastPosition                     bool
 	externalSpeedLimit                  float32
 	speedLimitAccepted                  bool
 	currentPersonality                  log.LongitudinalPersonality
@@ -49,8 +54,10 @@ type MapdSettings struct {
 	MapCurveUseEnableSpeed              bool               `json:"map_curve_use_enable_speed"`
 	EnableSpeed                         float32            `json:"enable_speed"`
 	DefaultLaneWidth                    float32            `json:"default_lane_width"`
+	LiveDownloadEnabled                 bool               `json:"live_download_enabled"`
 	SubscriberSettings                  SubscriberSettings `json:"subscriber"`
 	SpeedLimitSettings                  SpeedLimitSettings `json:"speed_limit"`
+	DownloadSettings                    DownloadSettings   `json:"download"`
 	LogSettings                         LogSettings        `json:"logger"`
 	Personalities                       Personalities      `json:"personalities"`
 }
@@ -67,6 +74,16 @@ type SpeedLimitSettings struct {
 	SpeedLimitOffset                    float32 `json:"speed_limit_offset"`
 }
 
+type DownloadSettings struct {
+	PauseOnMetered bool     `json:"pause_on_metered"`
+	MeteredParam   string   `json:"metered_param"`
+	MeteredCommand string   `json:"metered_command"`
+	Sources        []string `json:"sources"`
+	DiskQuotaMB    float32  `json:"disk_quota_mb"` // 0 is unlimited
+	Workers        int      `json:"workers"`
+	BandwidthMbps  float32  `json:"bandwidth_limit_mbps"` // 0 is unlimited
+}
+
 type LogSettings struct {
 	LogLevel  string `json:"log_level"`
 	LogJson   bool   `json:"log_json"`
@@ -307,6 +324,33 @@ func (s *MapdSettings) GetDownloadProgress() (progress DownloadProgress, success
 	return
 }
 
+// SetLastPosition remembers the latest gps position for downloads centered on
+// the vehicle.
+func (s *MapdSettings) SetLastPosition(lat float64, lon float64) {
+	s.lastLatitude = lat
+	s.lastLongitude = lon
+	s.hasLastPosition = true
+}
+
+// downloadCenter picks the center for a radius download: an explicit "lat,lon"
+// from the input, then the current position, then openpilot's last known position.
+func (s *MapdSettings) downloadCenter(input custom.MapdIn) (lat float64, lon float64, err error) {
+	center, err := input.Str()
+	if err == nil && center != "" {
+		return ParseDownloadCenter(center)
+	}
+	return s.LastPosition()
+}
+
+// LastPosition is the latest gps position, or the last position openpilot
+// saved when there was no fix since mapd started.
+func (s *MapdSettings) LastPosition() (lat float64, lon float64, err error) {
+	if s.hasLastPosition {
+		return s.lastLatitude, s.lastLongitude, nil
+	}
+	return readLastGpsPosition()
+}
+
 func (s *MapdSettings) ExternalSpeedLimit() float32 {
 	return s.externalSpeedLimit
 }
@@ -340,6 +384,48 @@ func (s *MapdSettings) Handle(input custom.MapdIn) {
 		if !s.downloadActive {
 			go Download(path, s.downloadProgress, s.cancelDownload)
 		}
+	case custom.MapdInputType_downloadRadius:
+		lat, lon, err := s.downloadCenter(input)
+		if err != nil {
+			slog.Warn("could not determine position for radius download", "error", err)
+			return
+		}
+		if input.Float() <= 0 {
+			slog.Warn("ignoring radius download without a positive radius", "radius", input.Float())
+			return
+		}
+		if !s.downloadActive {
+			go DownloadRadius(lat, lon, float64(input.Float()), s.downloadProgress, s.cancelDownload)
+		}
+	case custom.MapdInputType_deleteRegion:
+		region, err := input.Str()
+		if err != nil {
+			slog.Warn("failed to read region string", "error", err)
+			return
+		}
+		if s.downloadActive {
+			slog.Warn("not deleting region while a download is active", "region", region)
+			return
+		}
+		go DeleteInstalledRegion(region)
+	case custom.MapdInputType_checkForUpdates:
+		if !s.downloadActive {
+			go CheckForUpdates(s.downloadProgress, s.cancelDownload)
+		}
+	case custom.MapdInputType_updateMaps:
+		if !s.downloadActive {
+			go UpdateMaps(s.downloadProgress, s.cancelDownload)
+		}
+	case custom.MapdInputType_setMeteredConnection:
+		networkPolicy.setInputMetered(input.Bool())
+	case custom.MapdInputType_setMapSources:
+		sources, err := input.Str()
+		if err != nil {
+			slog.Warn("failed to read map sources string", "error", err)
+			return
+		}
+		s.DownloadSettings.Sources = ParseSources(sources)
+		mapSources.set(s.DownloadSettings.Sources)
 	case custom.MapdInputType_acceptSpeedLimit:
 		s.AcceptSpeedLimit()
 	case custom.MapdInputType_setJsonPathBool:
@@ -456,6 +542,17 @@ func (s *MapdSettings) Handle(input custom.MapdIn) {
 		s.LogSettings.LogJson = input.Bool()
 		s.setupLogger()
 	}
+	s.applyDownloadSettings()
+}
+
+// applyDownloadSettings hands settings to the background downloads, e.g. a
+// fork disabling live downloads on a metered connection.
+func (s *MapdSettings) applyDownloadSettings() {
+	s.liveDownloads.setEnabled(s.LiveDownloadEnabled)
+	networkPolicy.configure(s.DownloadSettings)
+	mapSources.set(s.DownloadSettings.Sources)
+	diskQuota.setLimit(s.DownloadSettings.DiskQuotaMB)
+	downloadLimits.configure(s.DownloadSettings)
 }
 
 func (s *MapdSettings) PrioritySpeedLimit(mapLimit float32) float32 {
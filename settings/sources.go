@@ -0,0 +1,193 @@
+package settings
+
+import (
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// A map source is an http(s) url, a file:// url or a plain directory laid out
+// like the map server (offline/<lat>/<lon>.tar.gz and its manifest). Sources
+// are tried in priority order.
+type sourceList struct {
+	lock    sync.Mutex
+	sources []string
+}
+
+var mapSources = &sourceList{sources: []string{MAP_DATA_URL}}
+
+func (l *sourceList) set(sources []string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.sources = normalizeSources(sources)
+}
+
+func (l *sourceList) get() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]string{}, l.sources...)
+}
+
+// normalizeSources drops empty entries and falls back to the default map
+// server when nothing is left.
+func normalizeSources(sources []string) []string {
+	normalized := []string{}
+	for _, source := range sources {
+		source = strings.TrimSpace(source)
+		if source == "" {
+			continue
+		}
+		if isRemoteSource(source) && !strings.HasSuffix(source, "/") {
+			source += "/"
+		}
+		normalized = append(normalized, source)
+	}
+	if len(normalized) == 0 {
+		return []string{MAP_DATA_URL}
+	}
+	return normalized
+}
+
+// ParseSources splits a comma separated priority list of map sources.
+func ParseSources(sources string) []string {
+	return normalizeSources(strings.Split(sources, ","))
+}
+
+func isRemoteSource(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// localSourcePath returns the directory of a file:// or plain directory source
+func localSourcePath(source string) (string, error) {
+	if !strings.HasPrefix(source, "file://") {
+		return source, nil
+	}
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid map source %q", source)
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return "", errors.Errorf("map source %q must be a local path", source)
+	}
+	return u.Path, nil
+}
+
+// sourceOrder lists the sources with the ones that already failed during this
+// download moved to the back.
+func (d *download) sourceOrder() []string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	working := []string{}
+	failed := []string{}
+	for _, source := range d.sources {
+		if d.failedSources[source] {
+			failed = append(failed, source)
+		} else {
+			working = append(working, source)
+		}
+	}
+	return append(working, failed...)
+}
+
+// fromEachSource runs fetch with every source in order until one of them has
+// the file. A source that fails for any other reason than not having the file,
+// including data that does not match its manifest, is tried last for the rest
+// of the download. A file missing from every source is reported as
+// ErrNotFound.
+func (d *download) fromEachSource(filename string, fetch func(source string) (attempts int, err error)) (attempts int, err error) {
+	var lastErr error
+	for _, source := range d.sourceOrder() {
+		sourceAttempts, err := fetch(source)
+		attempts += sourceAttempts
+		if err == nil {
+			d.lock.Lock()
+			delete(d.failedSources, source)
+			d.lock.Unlock()
+			return attempts, nil
+		}
+		if errors.Is(err, ErrDownloadCanceled) {
+			return attempts, err
+		}
+		if !errors.Is(err, ErrNotFound) {
+			lastErr = err
+			d.lock.Lock()
+			d.failedSources[source] = true
+			d.lock.Unlock()
+			slog.Warn("map source failed, trying the next source", "error", err, "source", source, "file", filename)
+		}
+	}
+	if lastErr != nil {
+		return attempts, lastErr
+	}
+	return attempts, errors.Wrapf(ErrNotFound, "%s is not available from any map source", filename)
+}
+
+func (d *download) fetchFrom(source string, filename string, outputName string) (attempts int, err error) {
+	d.beginTransfer(filename, outputName)
+	fetcher := d.fetcher
+	fetcher.Progress = func(received int64, total int64) {
+		d.fileProgress(filename, received, total)
+	}
+	if isRemoteSource(source) {
+		return fetcher.Fetch(source+filename, outputName)
+	}
+	dir, err := localSourcePath(source)
+	if err != nil {
+		return 1, err
+	}
+	if d.checkCanceled() {
+		return 0, ErrDownloadCanceled
+	}
+	slog.Info("Copying", "source", source, "file", filename)
+	return 1, copyLocalFile(filepath.Join(dir, filepath.FromSlash(filename)), outputName, fetcher.Progress, d.checkCanceled)
+}
+
+// cancelReader stops a copy as soon as the download is canceled.
+type cancelReader struct {
+	reader   io.Reader
+	canceled func() bool
+}
+
+func (r cancelReader) Read(p []byte) (int, error) {
+	if r.canceled() {
+		return 0, ErrDownloadCanceled
+	}
+	return r.reader.Read(p)
+}
+
+func copyLocalFile(sourcePath string, outputName string, progress func(received int64, total int64), canceled func() bool) error {
+	in, err := os.Open(sourcePath)
+	if os.IsNotExist(err) {
+		return errors.Wrapf(ErrNotFound, "%s does not exist", sourcePath)
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not open map source file")
+	}
+	defer in.Close()
+
+	out, err := os.Create(outputName)
+	if err != nil {
+		return errors.Wrap(err, "could not create file for map source copy")
+	}
+	defer out.Close()
+	var body io.Reader = cancelReader{reader: in, canceled: canceled}
+	if info, err := in.Stat(); err == nil && progress != nil {
+		progress(0, info.Size())
+		body = &progressReader{reader: body, total: info.Size(), report: progress}
+	}
+	_, err = io.Copy(out, body)
+	if err == nil {
+		err = out.Sync()
+	}
+	if err != nil {
+		os.Remove(outputName)
+		return errors.Wrap(err, "could not copy map source file")
+	}
+	return nil
+}
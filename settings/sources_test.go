@@ -0,0 +1,189 @@
+package settings
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func writeSourceFiles(t *testing.T, files map[string][]byte) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, data, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParseSources(t *testing.T) {
+	sources := ParseSources(" https://mirror.example.com/maps , file:///media/usb,, /data/maps ")
+	expected := []string{"https://mirror.example.com/maps/", "file:///media/usb", "/data/maps"}
+	if !slices.Equal(sources, expected) {
+		t.Errorf("expected %v, got %v", expected, sources)
+	}
+	if sources := ParseSources(" , "); !slices.Equal(sources, []string{MAP_DATA_URL}) {
+		t.Errorf("expected the default map server for an empty list, got %v", sources)
+	}
+}
+
+func TestDownloadFailsOverToLocalSource(t *testing.T) {
+	archive := testArchive(t, map[string]string{"offline/0/0/tile": "usb"})
+	dir := writeSourceFiles(t, map[string][]byte{
+		"offline/0/0.tar.gz":        archive,
+		"offline/0/0.manifest.json": testManifest(t, archive),
+	})
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	d := newTestDownload(t, down)
+	d.sources = []string{down.URL + "/", "file://" + dir}
+	err, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}, "test.location")
+	if err != nil || canceled || len(d.progress.FailedFiles) != 0 {
+		t.Fatalf("download failed: %v, %v, %+v", err, canceled, d.progress.FailedFiles)
+	}
+	if data, _ := os.ReadFile(filepath.Join(d.basePath, "offline/0/0/tile")); string(data) != "usb" {
+		t.Error("tiles from the local source were not installed")
+	}
+	if !d.failedSources[down.URL+"/"] {
+		t.Error("the failing mirror should be tried last for the rest of the download")
+	}
+	if _, err := os.Stat(filepath.Join(d.basePath, "offline/0/0.manifest.json")); err != nil {
+		t.Error("manifest from the local source was not installed")
+	}
+	if len(d.progress.UnverifiedFiles) != 0 {
+		t.Errorf("expected verified archives to not be reported, got %v", d.progress.UnverifiedFiles)
+	}
+}
+
+func TestLocalSourceIsVerified(t *testing.T) {
+	archive := testArchive(t, map[string]string{"offline/0/0/tile": "good"})
+	corrupt := testArchive(t, map[string]string{"offline/0/0/tile": "evil"})
+	dir := writeSourceFiles(t, map[string][]byte{
+		"offline/0/0.tar.gz":        corrupt,
+		"offline/0/0.manifest.json": testManifest(t, archive),
+	})
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	d.sources = []string{dir}
+	d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}, "test.location")
+	if len(d.progress.FailedFiles) != 1 {
+		t.Fatalf("expected the corrupt archive to be rejected, got %+v", d.progress.FailedFiles)
+	}
+	if _, err := os.Stat(filepath.Join(d.basePath, "offline/0/0")); !os.IsNotExist(err) {
+		t.Error("tiles that fail verification should not be installed")
+	}
+}
+
+func TestMissingFileFromAllSources(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	d.sources = []string{server.URL + "/", t.TempDir()}
+	_, err := d.downloadGroup("test.location", "offline/0/0.tar.gz")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if len(d.failedSources) != 0 {
+		t.Error("missing files should not mark a source as failed")
+	}
+}
+
+func TestManifestAndArchiveComeFromOneSource(t *testing.T) {
+	archive := testArchive(t, map[string]string{"offline/0/0/tile": "new"})
+	newer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/offline/0/0.manifest.json" {
+			w.Write(testManifest(t, archive))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer newer.Close()
+	older := testArchive(t, map[string]string{"offline/0/0/tile": "old"})
+	dir := writeSourceFiles(t, map[string][]byte{
+		"offline/0/0.tar.gz":        older,
+		"offline/0/0.manifest.json": testManifest(t, older),
+	})
+
+	d := newTestDownload(t, newer)
+	d.sources = []string{newer.URL + "/", dir}
+	d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}, "test.location")
+	if len(d.progress.FailedFiles) != 0 {
+		t.Fatalf("expected the archive and its manifest from the second source, got %+v", d.progress.FailedFiles)
+	}
+	if data, _ := os.ReadFile(filepath.Join(d.basePath, "offline/0/0/tile")); string(data) != "old" {
+		t.Error("tiles from the second source were not installed")
+	}
+	if !d.failedSources[newer.URL+"/"] {
+		t.Error("the source that could not serve the archive should be tried last")
+	}
+}
+
+func TestCorruptSourceFailsOver(t *testing.T) {
+	archive := testArchive(t, map[string]string{"offline/0/0/tile": "good"})
+	corrupt := &flakyServer{
+		archives: map[string][]byte{"/offline/0/0.tar.gz": archive},
+		corrupt:  DOWNLOAD_VERIFY_ATTEMPTS,
+		requests: map[string]int{},
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/offline/0/0.tar.gz", corrupt)
+	mux.HandleFunc("/offline/0/0.manifest.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(testManifest(t, archive))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	dir := writeSourceFiles(t, map[string][]byte{
+		"offline/0/0.tar.gz":        archive,
+		"offline/0/0.manifest.json": testManifest(t, archive),
+	})
+
+	d := newTestDownload(t, server)
+	d.sources = []string{server.URL + "/", dir}
+	d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}, "test.location")
+	if len(d.progress.FailedFiles) != 0 {
+		t.Fatalf("expected the download to fail over to the second source, got %+v", d.progress.FailedFiles)
+	}
+	if data, _ := os.ReadFile(filepath.Join(d.basePath, "offline/0/0/tile")); string(data) != "good" {
+		t.Error("tiles from the second source were not installed")
+	}
+	if !d.failedSources[server.URL+"/"] {
+		t.Error("the source serving corrupt data should be tried last")
+	}
+	if requests := corrupt.requests["/offline/0/0.tar.gz"]; requests != DOWNLOAD_VERIFY_ATTEMPTS {
+		t.Errorf("expected %d attempts at the corrupt source, got %d", DOWNLOAD_VERIFY_ATTEMPTS, requests)
+	}
+}
+
+func TestLocalCopyStopsOnCancel(t *testing.T) {
+	dir := writeSourceFiles(t, map[string][]byte{"offline/0/0.tar.gz": make([]byte, 1<<20)})
+	output := filepath.Join(t.TempDir(), "archive")
+	reads := 0
+	err := copyLocalFile(filepath.Join(dir, "offline/0/0.tar.gz"), output, nil, func() bool {
+		reads++
+		return reads > 1
+	})
+	if !errors.Is(err, ErrDownloadCanceled) {
+		t.Errorf("expected the copy to be canceled, got %v", err)
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Error("expected the partial copy to be removed")
+	}
+}
@@ -0,0 +1,145 @@
+package settings
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// CheckForUpdates compares the installed groups against the manifests on the
+// map server and reports how many groups have newer data.
+func CheckForUpdates(progressChan chan DownloadProgress, cancelChan chan bool) {
+	runUpdate(false, progressChan, cancelChan)
+}
+
+// UpdateMaps downloads only the installed groups whose data changed on the map
+// server.
+func UpdateMaps(progressChan chan DownloadProgress, cancelChan chan bool) {
+	runUpdate(true, progressChan, cancelChan)
+}
+
+func runUpdate(apply bool, progressChan chan DownloadProgress, cancelChan chan bool) {
+	d := newDownload(DownloadProgress{
+		LocationDetails: make(map[string]*DownloadLocationDetail),
+		Active:          true,
+	}, progressChan, cancelChan)
+	d.update(apply)
+	d.progress.Active = false
+	d.finishFiles()
+	d.sendProgress()
+	d.removeTmpPath()
+}
+
+func (d *download) update(apply bool) {
+	d.progress.UpdateCheck = true
+	err := seedInventory(d.basePath)
+	if err != nil {
+		slog.Warn("could not record map data installed without the inventory", "error", err)
+	}
+	inventory, err := LoadInventory(d.basePath)
+	if err != nil {
+		slog.Warn("could not load map inventory", "error", err)
+		return
+	}
+
+	groups := make([]string, 0, len(inventory.Groups))
+	for name := range inventory.Groups {
+		groups = append(groups, name)
+	}
+	slices.Sort(groups)
+	regions := inventory.RegionNames()
+	d.progress.LocationsToDownload = regions
+	d.progress.TotalFiles = len(groups)
+	for _, region := range regions {
+		d.progress.LocationDetails[region] = &DownloadLocationDetail{TotalFiles: len(inventory.Regions[region].Groups)}
+	}
+
+	slog.Info("checking for map updates", "groups", len(groups))
+	outdated := map[string]bool{}
+	for _, name := range groups {
+		d.startFile(name)
+		d.sendProgress()
+		if d.checkCanceled() {
+			d.progress.Canceled = true
+			return
+		}
+
+		var manifest *GroupManifest
+		_, err := d.fromEachSource(GroupManifestName(name), func(source string) (int, error) {
+			var err error
+			manifest, err = d.fetchManifest(source, name)
+			return 1, err
+		})
+		d.finishFile(name)
+		if errors.Is(err, ErrDownloadCanceled) {
+			d.progress.Canceled = true
+			return
+		}
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			d.recordFailure(name, "", 1, err)
+			continue
+		}
+		// without a manifest there is nothing to compare against
+		if manifest != nil && manifest.Archive.Sha256 != inventory.Groups[name].Sha256 {
+			outdated[name] = true
+		}
+		d.progress.DownloadedFiles++
+		for _, region := range inventory.regionsWithGroup(name) {
+			d.progress.LocationDetails[region].DownloadedFiles++
+		}
+	}
+	d.progress.UpdatesAvailable = len(outdated)
+	slog.Info("finished checking for map updates", "updates", len(outdated))
+	if !apply || len(outdated) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(outdated))
+	for name := range outdated {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	d.progress.UpdateCheck = false
+	d.progress.TotalFiles = len(names)
+	d.progress.DownloadedFiles = 0
+	for _, region := range regions {
+		d.progress.LocationDetails[region] = &DownloadLocationDetail{}
+	}
+	for _, name := range names {
+		for _, region := range inventory.regionsWithGroup(name) {
+			d.progress.LocationDetails[region].TotalFiles++
+		}
+	}
+
+	d.progress.Canceled = d.processGroups(names, func(name string) {
+		d.updateGroup(name, inventory)
+	})
+}
+
+// updateGroup downloads the newer data of an installed group.
+func (d *download) updateGroup(name string, inventory Inventory) {
+	d.startFile(name)
+	d.sendProgress()
+	if d.checkCanceled() {
+		return
+	}
+
+	attempts, err := d.downloadGroup("", name)
+	d.finishFile(name)
+	if errors.Is(err, ErrDownloadCanceled) {
+		return
+	}
+	if err != nil {
+		d.recordFailure(name, "", attempts, err)
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.progress.DownloadedFiles++
+	d.progress.UpdatesAvailable--
+	for _, region := range inventory.regionsWithGroup(name) {
+		d.progress.LocationDetails[region].DownloadedFiles++
+	}
+}
@@ -0,0 +1,192 @@
+package settings
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUpdateOnlyDownloadsChangedGroups(t *testing.T) {
+	changed := testArchive(t, map[string]string{"offline/0/0/tile": "old"})
+	unchanged := testArchive(t, map[string]string{"offline/0/2/tile": "same"})
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz":        changed,
+			"/offline/0/0.manifest.json": testManifest(t, changed),
+			"/offline/0/2.tar.gz":        unchanged,
+			"/offline/0/2.manifest.json": testManifest(t, unchanged),
+		},
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	err, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 3}, "test.location")
+	if err != nil || canceled || len(d.progress.FailedFiles) != 0 {
+		t.Fatalf("initial download failed: %v, %v, %+v", err, canceled, d.progress.FailedFiles)
+	}
+	inventory, err := LoadInventory(d.basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	region := inventory.Regions["test.location"]
+	if region == nil || len(region.Groups) != 2 || len(inventory.Groups) != 2 {
+		t.Fatalf("expected both groups in the inventory, got %+v", inventory)
+	}
+
+	// publish new data for one group
+	updated := testArchive(t, map[string]string{"offline/0/0/tile": "new"})
+	flaky.archives["/offline/0/0.tar.gz"] = updated
+	flaky.archives["/offline/0/0.manifest.json"] = testManifest(t, updated)
+
+	check := newTestDownload(t, server)
+	check.basePath = d.basePath
+	check.update(false)
+	if check.progress.UpdatesAvailable != 1 || check.progress.DownloadedFiles != 2 || !check.progress.UpdateCheck {
+		t.Fatalf("unexpected update check progress: %+v", check.progress)
+	}
+	if data, _ := os.ReadFile(filepath.Join(d.basePath, "offline/0/0/tile")); string(data) != "old" {
+		t.Error("checking for updates should not change installed tiles")
+	}
+
+	flaky.requests = map[string]int{}
+	apply := newTestDownload(t, server)
+	apply.basePath = d.basePath
+	apply.update(true)
+	if apply.progress.UpdatesAvailable != 0 || apply.progress.TotalFiles != 1 || apply.progress.DownloadedFiles != 1 {
+		t.Fatalf("unexpected update progress: %+v", apply.progress)
+	}
+	if apply.progress.LocationDetails["test.location"].TotalFiles != 1 {
+		t.Errorf("expected one file to update for the region, got %+v", apply.progress.LocationDetails["test.location"])
+	}
+	if flaky.requests["/offline/0/2.tar.gz"] != 0 || flaky.requests["/offline/0/0.tar.gz"] != 1 {
+		t.Errorf("expected only the changed group to be downloaded, got %v", flaky.requests)
+	}
+	if data, _ := os.ReadFile(filepath.Join(d.basePath, "offline/0/0/tile")); string(data) != "new" {
+		t.Error("expected the updated tile to be installed")
+	}
+
+	inventory, err = LoadInventory(d.basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := ReadGroupManifest(filepath.Join(d.basePath, "offline/0/0.manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inventory.Groups["offline/0/0.tar.gz"].Sha256 != manifest.Archive.Sha256 {
+		t.Error("expected the inventory to record the updated version")
+	}
+	if len(inventory.Regions["test.location"].Groups) != 2 {
+		t.Error("updates should not change region membership")
+	}
+}
+
+func TestUpdateChecksGroupsInstalledWithoutInventory(t *testing.T) {
+	current := testArchive(t, map[string]string{"offline/0/2/tile": "current"})
+	updated := testArchive(t, map[string]string{"offline/0/0/tile": "new"})
+	flaky := &flakyServer{
+		archives: map[string][]byte{
+			"/offline/0/0.tar.gz":        updated,
+			"/offline/0/0.manifest.json": testManifest(t, updated),
+			"/offline/0/2.tar.gz":        current,
+			"/offline/0/2.manifest.json": testManifest(t, current),
+		},
+		requests: map[string]int{},
+	}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	// groups from before the inventory, one with the manifest it was installed with
+	d := newTestDownload(t, server)
+	writeLiveTile(t, d.basePath, "offline/0/0/tile", "old")
+	writeLiveTile(t, d.basePath, "offline/0/2/tile", "current")
+	writeLiveTile(t, d.basePath, "offline/0/2.manifest.json", string(testManifest(t, current)))
+	d.update(false)
+	if d.progress.TotalFiles != 2 || d.progress.UpdatesAvailable != 1 {
+		t.Fatalf("expected both groups to be checked and one to be outdated, got %+v", d.progress)
+	}
+	inventory, err := LoadInventory(d.basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inventory.Groups) != 2 || inventory.Groups["offline/0/0.tar.gz"].Size != int64(len("old")) {
+		t.Errorf("expected the groups on disk to be recorded, got %+v", inventory.Groups)
+	}
+	if inventory.Groups["offline/0/2.tar.gz"].Sha256 == "" {
+		t.Error("expected the version of the installed manifest to be recorded")
+	}
+}
+
+func TestUpdateDownloadsGroupsInParallel(t *testing.T) {
+	archives := map[string][]byte{}
+	for _, lon := range []string{"0", "2", "4"} {
+		archive := testArchive(t, map[string]string{"offline/0/" + lon + "/tile": "old"})
+		archives["/offline/0/"+lon+".tar.gz"] = archive
+		archives["/offline/0/"+lon+".manifest.json"] = testManifest(t, archive)
+	}
+	flaky := &flakyServer{archives: archives, requests: map[string]int{}}
+	blocking := &blockingServer{started: make(chan string, 10), release: make(chan struct{})}
+	var updating atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if updating.Load() && strings.HasSuffix(r.URL.Path, ".tar.gz") {
+			blocking.ServeHTTP(w, r)
+			return
+		}
+		flaky.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	d := newTestDownload(t, server)
+	err, canceled := d.downloadBounds(Bounds{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 5}, "test.location")
+	if err != nil || canceled || len(d.progress.FailedFiles) != 0 {
+		t.Fatalf("initial download failed: %v, %v, %+v", err, canceled, d.progress.FailedFiles)
+	}
+
+	// publish new data for every group
+	for _, lon := range []string{"0", "2", "4"} {
+		archive := testArchive(t, map[string]string{"offline/0/" + lon + "/tile": "new"})
+		archives["/offline/0/"+lon+".tar.gz"] = archive
+		archives["/offline/0/"+lon+".manifest.json"] = testManifest(t, archive)
+	}
+	updating.Store(true)
+	apply := newTestDownload(t, server)
+	apply.basePath = d.basePath
+	apply.workers = 3
+	done := make(chan struct{})
+	go func() {
+		apply.update(true)
+		close(done)
+	}()
+
+	for range 3 {
+		select {
+		case <-blocking.started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the updates were not downloaded in parallel")
+		}
+	}
+	blocking.mu.Lock()
+	peak := blocking.peak
+	blocking.mu.Unlock()
+	if peak != 3 {
+		t.Errorf("expected 3 parallel transfers, got %d", peak)
+	}
+
+	apply.cancelChan <- true
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancel did not stop the update")
+	}
+	if !apply.progress.Canceled || apply.progress.DownloadedFiles != 0 || len(apply.progress.FailedFiles) != 0 {
+		t.Errorf("expected a canceled update without failures, got %+v", apply.progress)
+	}
+	close(blocking.release)
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"slices"
+
 	"pfeifer.dev/mapd/cereal"
 	"pfeifer.dev/mapd/cereal/car"
 	"pfeifer.dev/mapd/cereal/custom"
@@ -17,6 +19,7 @@ type State struct {
 	SpeedLimit                SpeedLimitState
 	NextWays                  []maps.NextWayResult
 	Position                  m.Position
+	Altitude                  AltitudeTracker
 	Curvatures                []m.Curvature
 	TargetVelocities          []Velocity
 	DistanceSinceLastPosition float32
@@ -25,6 +28,8 @@ type State struct {
 	VisionCurveMA             m.MovingAverage
 	NextAdvisorySpeed         Upcoming[float32]
 	NextHazard                Upcoming[string]
+	UpcomingMaps              UpcomingMaps
+	retiredData               []maps.Offline
 }
 
 func (s *State) Init() {
@@ -35,6 +40,27 @@ func (s *State) Init() {
 	s.SpeedLimit.Init()
 }
 
+// SwapData replaces the loaded map data. The ways of the previous data can
+// still be in use, so it is only closed by CloseRetiredData.
+func (s *State) SwapData(data maps.Offline) {
+	s.retiredData = append(s.retiredData, s.Data)
+	s.Data = data
+}
+
+// CloseRetiredData closes swapped out map data that neither the current way
+// nor the next ways were read from.
+func (s *State) CloseRetiredData() {
+	s.retiredData = slices.DeleteFunc(s.retiredData, func(data maps.Offline) bool {
+		inUse := data.Holds(s.CurrentWay.Way) || slices.ContainsFunc(s.NextWays, func(next maps.NextWayResult) bool {
+			return data.Holds(next.Way)
+		})
+		if !inUse {
+			data.Close()
+		}
+		return !inUse
+	})
+}
+
 func (s *State) SuggestedSpeed() float32 {
 	suggestedSpeed := min(s.Car.VCruise*ms.KPH_TO_MS, ms.MAX_OP_SPEED)
 
@@ -70,7 +70,23 @@ func (w *CurrentWay) EffectiveMaxSpeed() float64 {
 	return w.MaxSpeed()
 }
 
-func selectBestWayAdvanced(possibleWays []maps.Way, location log.GpsLocationData, currentWay maps.Way) maps.Way {
+// levelScore keeps us on the level of the current way at interchanges and
+// overpasses. Candidates on another level are penalised unless the altitude
+// trend agrees with the direction of the level change.
+func levelScore(levelDelta int, expectedLevelChange int) float32 {
+	if levelDelta == 0 {
+		if expectedLevelChange == 0 {
+			return 20.0
+		}
+		return 0
+	}
+	if expectedLevelChange != 0 && (levelDelta > 0) == (expectedLevelChange > 0) {
+		return 0
+	}
+	return -40.0 * float32(max(levelDelta, -levelDelta))
+}
+
+func selectBestWayAdvanced(possibleWays []maps.Way, location log.GpsLocationData, currentWay maps.Way, expectedLevelChange int) maps.Way {
 	if len(possibleWays) == 0 {
 		return maps.Way{}
 	}
@@ -110,6 +126,8 @@ func selectBestWayAdvanced(possibleWays []maps.Way, location log.GpsLocationData
 			if len(currentRef) > 0 && currentRef == wayRef {
 				score += 25.0
 			}
+
+			score += levelScore(way.Level()-currentWay.Level(), expectedLevelChange)
 		}
 
 		if score > bestScore {
@@ -121,7 +139,7 @@ func selectBestWayAdvanced(possibleWays []maps.Way, location log.GpsLocationData
 	return bestWay
 }
 
-func GetCurrentWay(currentWay CurrentWay, nextWays []maps.NextWayResult, offline *maps.Offline, location log.GpsLocationData) (CurrentWay, error) {
+func GetCurrentWay(currentWay CurrentWay, nextWays []maps.NextWayResult, offline *maps.Offline, location log.GpsLocationData, altitude *AltitudeTracker) (CurrentWay, error) {
 	distanceFromCurrentWay := currentWay.OnWay.Distance.Distance
 	if currentWay.Way.Nodes.Len() > 1 {
 		onWay, err := currentWay.Way.OnWay(location, currentWay.Way.DistanceMultiplier())
@@ -174,7 +192,7 @@ func GetCurrentWay(currentWay CurrentWay, nextWays []maps.NextWayResult, offline
 
 	possibleWays, err := getPossibleWays(offline, location)
 	if err == nil && len(possibleWays) > 0 {
-		selectedWay := selectBestWayAdvanced(possibleWays, location, currentWay.Way)
+		selectedWay := selectBestWayAdvanced(possibleWays, location, currentWay.Way, altitude.ExpectedLevelChange())
 		if selectedWay.Nodes.Len() > 0 {
 			selectedOnWay, err := selectedWay.OnWay(location, selectedWay.DistanceMultiplier())
 			if err == nil && selectedOnWay.OnWay {
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+
+	"capnproto.org/go/capnp/v3"
+	"pfeifer.dev/mapd/cereal/offline"
+	"pfeifer.dev/mapd/maps"
+)
+
+// stackedWay is an east bound road at lat on the given layer.
+func stackedWay(t *testing.T, name string, lat float64, layer int8, bridge bool) maps.Way {
+	t.Helper()
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := offline.NewRootWay(seg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetName(name); err != nil {
+		t.Fatal(err)
+	}
+	w.SetLanes(2)
+	w.SetLayer(layer)
+	w.SetBridge(bridge)
+	w.SetMinLat(lat)
+	w.SetMaxLat(lat)
+	w.SetMinLon(-83.001)
+	w.SetMaxLon(-82.999)
+	nodes, err := w.NewNodes(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes.At(0).SetLatitude(lat)
+	nodes.At(0).SetLongitude(-83.001)
+	nodes.At(1).SetLatitude(lat)
+	nodes.At(1).SetLongitude(-82.999)
+	return maps.NewWay(w)
+}
+
+func TestLevelScore(t *testing.T) {
+	cases := []struct {
+		levelDelta          int
+		expectedLevelChange int
+		expected            float32
+	}{
+		{0, 0, 20},
+		{0, 1, 0},
+		{1, 0, -40},
+		{-2, 0, -80},
+		{1, 1, 0},
+		{-1, -1, 0},
+		{1, -1, -40},
+		{-1, 1, -40},
+	}
+	for _, c := range cases {
+		if score := levelScore(c.levelDelta, c.expectedLevelChange); score != c.expected {
+			t.Errorf("levelScore(%d, %d) = %f, expected %f", c.levelDelta, c.expectedLevelChange, score, c.expected)
+		}
+	}
+}
+
+func TestSelectBestWayOnStackedLayers(t *testing.T) {
+	ramp := stackedWay(t, "Ramp", 40, 0, false)
+	// the overpass is closer to the gps position than the road below it
+	overpass := stackedWay(t, "Overpass", 40, 1, true)
+	below := stackedWay(t, "Below", 40.00003, 0, false)
+	candidates := []maps.Way{overpass, below}
+	location := testLocation(t, 40, -83, 90)
+
+	cases := []struct {
+		name                string
+		expectedLevelChange int
+		expected            string
+	}{
+		{"level", 0, "Below"},
+		{"climbing", 1, "Overpass"},
+		{"descending", -1, "Below"},
+	}
+	for _, c := range cases {
+		best := selectBestWayAdvanced(candidates, location, ramp, c.expectedLevelChange)
+		if name := best.WayName(); name != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, name)
+		}
+	}
+
+	// from the overpass the road below needs a descent
+	best := selectBestWayAdvanced([]maps.Way{below, overpass}, location, overpass, 0)
+	if name := best.WayName(); name != "Overpass" {
+		t.Errorf("expected to stay on the overpass, got %s", name)
+	}
+}